@@ -0,0 +1,72 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterNotifier is the Schema for the clusternotifiers API
+// It is the cluster-scoped counterpart of the Notifier and can be referenced by Providers in any namespace.
+// The secret and config map are read from the cluster resource namespace of the controller.
+type ClusterNotifier struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotifierSpec   `json:"spec,omitempty"`
+	Status NotifierStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterNotifierList contains a list of ClusterNotifier
+type ClusterNotifierList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterNotifier `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterNotifier{}, &ClusterNotifierList{})
+}
+
+// GetNotifierSpec returns the spec of the ClusterNotifier
+func (n *ClusterNotifier) GetNotifierSpec() *NotifierSpec {
+	return &n.Spec
+}
+
+// GetNotifierStatus returns the status of the ClusterNotifier
+func (n *ClusterNotifier) GetNotifierStatus() *NotifierStatus {
+	return &n.Status
+}
+
+// =================================================== Status ===================================================
+
+func (n *ClusterNotifier) Conditions() *conditions.Conditions {
+	return &conditions.Conditions{
+		Conditions:     &n.Status.Conditions,
+		ConditionTypes: []string{NotifierConditionTypeConfigMap, NotifierConditionTypeSecret, NotifierConditionTypeClient},
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="PublicIP",type=string,JSONPath=`.status.publicIP`
+// +kubebuilder:printcolumn:name="ProviderIP",type=string,JSONPath=`.status.providerIP`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
+
+// ClusterProvider is the Schema for the clusterproviders API
+// It is the cluster-scoped counterpart of the Provider and can be referenced from any namespace.
+// The secret and config map are read from the cluster resource namespace of the controller.
+type ClusterProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderSpec   `json:"spec,omitempty"`
+	Status ProviderStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterProviderList contains a list of ClusterProvider
+type ClusterProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterProvider{}, &ClusterProviderList{})
+}
+
+// GetProviderSpec returns the spec of the ClusterProvider
+func (p *ClusterProvider) GetProviderSpec() *ProviderSpec {
+	return &p.Spec
+}
+
+// GetProviderStatus returns the status of the ClusterProvider
+func (p *ClusterProvider) GetProviderStatus() *ProviderStatus {
+	return &p.Status
+}
+
+// =================================================== Status ===================================================
+
+func (p *ClusterProvider) Conditions() *conditions.Conditions {
+	return &conditions.Conditions{
+		Conditions:     &p.Status.Conditions,
+		ConditionTypes: []string{ProviderConditionTypeClient, ProviderConditionTypeConfigMap, ProviderConditionTypeSecret},
+	}
+}
@@ -0,0 +1,69 @@
+package conditions
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// FieldOwner is the field manager the status of the resources is applied with
+const FieldOwner = "go-ddns-controller"
+
+// csaFieldOwners are the field managers the status was patched with before it was applied: the FieldOwner, and the
+// default field manager of the controller binary before it had one
+var csaFieldOwners = sets.New(FieldOwner, "manager")
+
+// ApplyStatus server-side applies the status of the resource as the FieldOwner. The applied status is the whole status
+// owned by the controller, so fields that are unset in the resource are removed.
+// The status is applied with the resourceVersion of the resource, so a status computed from a stale resource, e.g. with
+// counters or timestamps that moved on since, fails with a conflict instead of overwriting the newer one. The
+// reconciliation returns the conflict and is retried with the resource read again. The resource is updated with the
+// response, so the next apply of the same reconciliation is locked to the resourceVersion it wrote
+func ApplyStatus(ctx context.Context, c client.Client, obj client.Object) error {
+	upgradeManagedFields(ctx, c, obj)
+
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	applied := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	applied.SetGroupVersionKind(gvk)
+	applied.SetNamespace(obj.GetNamespace())
+	applied.SetName(obj.GetName())
+	applied.SetResourceVersion(obj.GetResourceVersion())
+	if status, ok := content["status"]; ok {
+		applied.Object["status"] = status
+	}
+
+	if err := c.Status().Patch(ctx, applied, client.Apply, client.FieldOwner(FieldOwner), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(applied.Object, obj)
+}
+
+// upgradeManagedFields hands the status fields that were patched before they were applied over to the FieldOwner, so
+// the apply removes them once they are unset. Otherwise the fields would stay owned by the old patches and left as they
+// are. It is best effort: a failed upgrade, e.g. of a stale resource, is retried with the next apply
+func upgradeManagedFields(ctx context.Context, c client.Client, obj client.Object) {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(obj, csaFieldOwners, FieldOwner, csaupgrade.Subresource("status"))
+	if err == nil && patch != nil {
+		err = c.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch))
+	}
+
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Unable to hand the status fields over to the field manager", "error", err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package conditions
 
 import (
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -76,6 +77,32 @@ func (c *Conditions) SetCondition(conditionType string, options ...ConditionOpti
 	return changed
 }
 
+// ReadyConditionType is the type of the condition that summarizes all the ConditionTypes.
+// It is not part of the ConditionTypes itself.
+const ReadyConditionType = "Ready"
+
+// ReadyOptions returns the options for the Ready condition.
+// The resource is ready only if the reconciliation did not fail and all the ConditionTypes are True,
+// otherwise the error or the first condition that is not True is reported.
+func (c *Conditions) ReadyOptions(err error) []ConditionOption {
+	if err != nil {
+		return []ConditionOption{WithReasonAndMessage("ReconcileFailed", err.Error()), False()}
+	}
+
+	for _, conditionType := range c.ConditionTypes {
+		condition := c.GetCondition(conditionType)
+		if condition == nil {
+			return []ConditionOption{WithReasonAndMessage(conditionType+"NotReady", fmt.Sprintf("%s condition is missing", conditionType)), False()}
+		}
+
+		if condition.Status != metav1.ConditionTrue {
+			return []ConditionOption{WithReasonAndMessage(conditionType+"NotReady", condition.Message), False()}
+		}
+	}
+
+	return []ConditionOption{WithReasonAndMessage("Ready", "All conditions are met"), True()}
+}
+
 // ================================================ Private Functions ================================================
 
 func (c *Conditions) addUnknownCondition(conditionType string) bool {
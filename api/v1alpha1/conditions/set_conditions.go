@@ -3,32 +3,68 @@ package conditions
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type conditionResource[T any] interface {
+type conditionResource interface {
 	client.Object
-	DeepCopy() T
 	Conditions() *Conditions
 }
 
 // PatchConditions is a helper function to patch the conditions of a resource.
 // It works by merging the conditions of the resource with the new condition options.
-func PatchConditions[T client.Object](
+func PatchConditions(
 	ctx context.Context,
 	r client.Client,
-	res conditionResource[T],
+	res conditionResource,
 	conditionType string,
 	options ...ConditionOption,
 ) error {
-	patch := client.MergeFrom(res.DeepCopy())
-	options = append(options, WithObservedGeneration(res.GetGeneration()))
-	if res.Conditions().SetCondition(conditionType, options...) {
-		err := r.Status().Patch(ctx, res, patch)
-		if err != nil {
-			return err
-		}
+	set := PatchConditionsSet(res)
+	set.Set(conditionType, options...)
+
+	return set.Patch(ctx, r)
+}
+
+// ConditionsSet accumulates the changes to the conditions of a resource, so they are sent in a single status apply
+// instead of one per condition type
+type ConditionsSet struct {
+	res     conditionResource
+	changed bool
+}
+
+// PatchConditionsSet starts a batch of changes to the conditions of the resource
+func PatchConditionsSet(res conditionResource) *ConditionsSet {
+	return &ConditionsSet{res: res}
+}
+
+// Set merges the condition options into the condition of the given type, like PatchConditions, without patching it yet
+func (s *ConditionsSet) Set(conditionType string, options ...ConditionOption) {
+	options = append(options, WithObservedGeneration(s.res.GetGeneration()))
+	if s.res.Conditions().SetCondition(conditionType, options...) {
+		s.changed = true
+	}
+}
+
+// Remove removes the condition of the given type, e.g. one that is only present in some modes, without patching it yet
+func (s *ConditionsSet) Remove(conditionType string) {
+	if meta.RemoveStatusCondition(s.res.Conditions().Conditions, conditionType) {
+		s.changed = true
+	}
+}
+
+// Patch applies the status with all the changes at once. Nothing is sent if no condition changed
+func (s *ConditionsSet) Patch(ctx context.Context, r client.Client) error {
+	if !s.changed {
+		return nil
+	}
+
+	if err := ApplyStatus(ctx, r, s.res); err != nil {
+		return err
 	}
 
+	s.changed = false
+
 	return nil
 }
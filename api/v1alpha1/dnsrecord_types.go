@@ -0,0 +1,182 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DNSRecordSpec defines the desired state of DNSRecord
+type DNSRecordSpec struct {
+	// ProviderRef is the Provider, in the same namespace, or the ClusterProvider whose credentials are used to manage the record.
+	// +kubebuilder:validation:Required
+	ProviderRef ProviderRef `json:"providerRef"`
+
+	// Zone is the zone the record belongs to, e.g. example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Zone string `json:"zone"`
+
+	// Name is the full name of the record, e.g. www.example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Type is the type of the record.
+	// Default is A.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=A;AAAA;CNAME;TXT
+	// +kubebuilder:default:=A
+	Type string `json:"type,omitempty"`
+
+	// Value is the value (or target) of the record.
+	// For A and AAAA records it can be left empty, in which case the public IP detected by the Provider is used.
+	// +kubebuilder:validation:Optional
+	Value string `json:"value,omitempty"`
+
+	// TTL is the time to live of the record in seconds. 1 means automatic, where supported.
+	// Default is 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=1
+	TTL int `json:"ttl,omitempty"`
+
+	// Proxied is whether the record should be proxied by the provider, if supported.
+	// +kubebuilder:validation:Optional
+	Proxied bool `json:"proxied,omitempty"`
+
+	// DeletionPolicy controls what happens to the record at the provider when the DNSRecord is deleted.
+	// Delete removes the record, as long as it was created by the controller, Orphan leaves it as it is.
+	// Default is Delete.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Orphan;Delete
+	// +kubebuilder:default:=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DNSRecordStatus defines the observed state of DNSRecord
+type DNSRecordStatus struct {
+	// Value is the value of the record at the provider.
+	Value string `json:"value,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this DNSRecord.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Represents the observations of a DNSRecord's current state.
+	// For further information see: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Record",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Value",type=string,JSONPath=`.status.value`
+// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.providerRef.name`
+
+// DNSRecord is the Schema for the dnsrecords API
+type DNSRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DNSRecordSpec   `json:"spec,omitempty"`
+	Status DNSRecordStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DNSRecordList contains a list of DNSRecord
+type DNSRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DNSRecord `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DNSRecord{}, &DNSRecordList{})
+}
+
+// DNSRecordFinalizer is added to every DNSRecord so the record can be removed from the provider on deletion
+const DNSRecordFinalizer = "ddns.stefangenov.site/dnsrecord-finalizer"
+
+// Annotations of Ingresses whose hosts should be kept pointed at the public IP.
+// The controller creates a DNSRecord for every host of the rules of an Ingress with the IngressProviderAnnotation, and
+// deletes the DNSRecords once the host, the annotation or the Ingress is removed.
+const (
+	// IngressProviderAnnotation names the Provider, in the namespace of the Ingress, whose public IP the hosts point at.
+	// ClusterProviders are named as ClusterProvider/<name>
+	IngressProviderAnnotation = "ddns.stefangenov.site/provider"
+
+	// IngressZoneAnnotation is the zone the hosts belong to, e.g. example.com. Hosts outside of it are skipped
+	IngressZoneAnnotation = "ddns.stefangenov.site/zone"
+
+	// IngressProxiedAnnotation set to "true" proxies the records, if supported by the provider
+	IngressProxiedAnnotation = "ddns.stefangenov.site/proxied"
+
+	// IngressTTLAnnotation is the time to live of the records in seconds. 1, the default, means automatic
+	IngressTTLAnnotation = "ddns.stefangenov.site/ttl"
+
+	// IngressDeletionPolicyAnnotation is the DeletionPolicy of the records, Orphan or Delete. It decides if the records are
+	// removed from the provider once the host, the annotation or the Ingress is removed. Default is Delete
+	IngressDeletionPolicyAnnotation = "ddns.stefangenov.site/deletion-policy"
+
+	// IngressRecordTypeAnnotation restricts the records to A or AAAA, or a comma separated list of both.
+	// By default the hosts get a record for every IP family the Provider keeps in sync
+	IngressRecordTypeAnnotation = "ddns.stefangenov.site/record-type"
+)
+
+// IngressLabel is set on the DNSRecords created for an Ingress to the name of the Ingress
+const IngressLabel = "ddns.stefangenov.site/ingress"
+
+// ServiceHostnamesAnnotation lists the hosts, comma separated, of a Service of type LoadBalancer that should be kept
+// pointed at the address of its load balancer, e.g. one assigned by MetalLB, instead of the public IP.
+// The Provider, zone and proxied setting of the records are annotated the same as for Ingresses
+const ServiceHostnamesAnnotation = "ddns.stefangenov.site/hostnames"
+
+// ServiceLabel is set on the DNSRecords created for a Service to the name of the Service
+const ServiceLabel = "ddns.stefangenov.site/service"
+
+// DNSEndpointLabel is set on the DNSRecords created for an external-dns DNSEndpoint to the name of the DNSEndpoint.
+// DNSEndpoints name their Provider, zone and proxied setting with the same annotations as Ingresses
+const DNSEndpointLabel = "ddns.stefangenov.site/dnsendpoint"
+
+// IstioGatewayLabel is set on the DNSRecords created for an Istio Gateway to the name of the Gateway.
+// Istio Gateways and VirtualServices are annotated the same as Ingresses
+const IstioGatewayLabel = "ddns.stefangenov.site/istio-gateway"
+
+// IstioVirtualServiceLabel is set on the DNSRecords created for an Istio VirtualService to the name of the VirtualService
+const IstioVirtualServiceLabel = "ddns.stefangenov.site/istio-virtualservice"
+
+// IngressRouteLabel is set on the DNSRecords created for a Traefik IngressRoute to the name of the IngressRoute.
+// IngressRoutes are annotated the same as Ingresses
+const IngressRouteLabel = "ddns.stefangenov.site/ingressroute"
+
+// =================================================== Status ===================================================
+
+const (
+	DNSRecordConditionTypeProvider = "Provider"
+
+	DNSRecordConditionTypeSynced = "Synced"
+)
+
+func (d *DNSRecord) Conditions() *conditions.Conditions {
+	return &conditions.Conditions{
+		Conditions:     &d.Status.Conditions,
+		ConditionTypes: []string{DNSRecordConditionTypeProvider, DNSRecordConditionTypeSynced},
+	}
+}
@@ -1,7 +1,113 @@
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // ResourceRef is a reference to a resource in the cluster.
 type ResourceRef struct {
+	// Kind is the kind of the referenced resource, for references that accept more than one kind.
+	// For notifierRefs it is one of Notifier or ClusterNotifier. Empty means Notifier.
+	// +kubebuilder:validation:Optional
+	Kind string `json:"kind,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the referenced resource, for namespaced kinds.
+	// For notifierRefs, it defaults to the namespace of the Provider. Notifiers in other namespaces are only
+	// used if the controller runs with `--allow-cross-namespace-notifier-refs`. Ignored for ClusterNotifiers.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ProviderRef is a reference to either a Provider in the same namespace or a ClusterProvider.
+type ProviderRef struct {
+	// Kind is the kind of the referenced provider.
+	// Default is Provider.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Provider;ClusterProvider
+	// +kubebuilder:default:=Provider
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the referenced provider.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+const (
+	ProviderKind = "Provider"
+
+	ClusterProviderKind = "ClusterProvider"
+)
+
+// IsClusterProvider returns true if the reference points to a ClusterProvider
+func (r ProviderRef) IsClusterProvider() bool {
+	return r.Kind == ClusterProviderKind
+}
+
+// ProviderSelector selects Providers and ClusterProviders by their labels and/or by reference.
+// A Notifier only selects Providers in its own namespace (and any ClusterProvider), while a ClusterNotifier selects
+// Providers in every namespace.
+type ProviderSelector struct {
+	// Selector selects the Providers and ClusterProviders with matching labels. An empty selector selects all of them.
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Refs lists Providers and ClusterProviders explicitly, by name.
+	// +kubebuilder:validation:Optional
+	Refs []ProviderRef `json:"refs,omitempty"`
+}
+
+const (
+	NotifierKind = "Notifier"
+
+	ClusterNotifierKind = "ClusterNotifier"
+)
+
+// IsClusterNotifier returns true if the reference points to a ClusterNotifier
+func (r ResourceRef) IsClusterNotifier() bool {
+	return r.Kind == ClusterNotifierKind
+}
+
+// SecretRef is a reference to a Secret with optional overrides for the names of the keys read from it.
+type SecretRef struct {
+	// Name is the name of the secret.
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
 	Name string `json:"name"`
+
+	// Keys maps the key that the provider or notifier expects (e.g. `apiToken` or `url`) to the key in the secret that holds the value.
+	// Keys that are not mapped are read as is.
+	// +kubebuilder:validation:Optional
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+// ConfigMapRef is a reference to a ConfigMap in the namespace of the referencing resource.
+type ConfigMapRef struct {
+	// Name is the name of the config map.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+}
+
+// KeyFor returns the key in the secret that holds the value for the expected key.
+func (s SecretRef) KeyFor(key string) string {
+	if mapped, ok := s.Keys[key]; ok && mapped != "" {
+		return mapped
+	}
+
+	return key
+}
+
+// ObservedResources identifies the Secret and ConfigMap data that was used for the last successful reconciliation.
+// The data itself is not stored, only a hash of it.
+type ObservedResources struct {
+	// SecretHash is the hash of the data of the Secret.
+	// +optional
+	SecretHash string `json:"secretHash,omitempty"`
+
+	// ConfigMapHash is the hash of the data of the ConfigMap. Empty if the config is inline in the spec.
+	// +optional
+	ConfigMapHash string `json:"configMapHash,omitempty"`
 }
@@ -0,0 +1,126 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IPChangeTrigger is what started the sync that detected a change of the public IP
+type IPChangeTrigger string
+
+const (
+	// IPChangeTriggerScheduled is a sync started by the retryInterval or driftCheckInterval of the Provider, or by a
+	// change to the Provider or the resources it reads
+	IPChangeTriggerScheduled IPChangeTrigger = "Scheduled"
+
+	// IPChangeTriggerSyncRequested is a sync requested with the SyncNowAnnotation
+	IPChangeTriggerSyncRequested IPChangeTrigger = "SyncRequested"
+)
+
+// IPChangeOutcome is what was done about a change of the public IP
+type IPChangeOutcome string
+
+const (
+	// IPChangeOutcomeUpdated means the records were pointed at the new IP
+	IPChangeOutcomeUpdated IPChangeOutcome = "Updated"
+
+	// IPChangeOutcomeFailed means the records could not be updated. The Message holds the error
+	IPChangeOutcomeFailed IPChangeOutcome = "Failed"
+
+	// IPChangeOutcomeDryRun means the records were not updated, as the Provider is in dry run
+	IPChangeOutcomeDryRun IPChangeOutcome = "DryRun"
+
+	// IPChangeOutcomeDeferred means the records were not updated, as none of the updateWindows of the Provider was open
+	IPChangeOutcomeDeferred IPChangeOutcome = "Deferred"
+)
+
+// IPChangeSpec records a change of the public IP detected by a Provider and what was done about it.
+// IPChanges are created by the controller and are not meant to be edited
+type IPChangeSpec struct {
+	// ProviderRef is the Provider, in the same namespace, or the ClusterProvider that detected the change.
+	// +kubebuilder:validation:Required
+	ProviderRef ProviderRef `json:"providerRef"`
+
+	// Type is the type of the records that were changed, A or AAAA.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=A;AAAA
+	Type string `json:"type"`
+
+	// OldIP is the IP the records pointed at before the change. Empty if the Provider had not set one yet.
+	// +kubebuilder:validation:Optional
+	OldIP string `json:"oldIP,omitempty"`
+
+	// NewIP is the public IP the records were changed to.
+	// +kubebuilder:validation:Required
+	NewIP string `json:"newIP"`
+
+	// Records are the names of the records that pointed at another IP than NewIP.
+	// +kubebuilder:validation:Optional
+	Records []string `json:"records,omitempty"`
+
+	// Trigger is what started the sync that detected the change: Scheduled or SyncRequested.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=Scheduled;SyncRequested
+	Trigger IPChangeTrigger `json:"trigger"`
+
+	// Outcome is what was done about the change: Updated, Failed, DryRun or Deferred.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=Updated;Failed;DryRun;Deferred
+	Outcome IPChangeOutcome `json:"outcome"`
+
+	// Message is the error of a failed update.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// Time is when the change was detected. It is precise to the microsecond, so the IPChanges of a provider can be ordered.
+	// +kubebuilder:validation:Required
+	Time metav1.MicroTime `json:"time"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.providerRef.name`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="OldIP",type=string,JSONPath=`.spec.oldIP`
+// +kubebuilder:printcolumn:name="NewIP",type=string,JSONPath=`.spec.newIP`
+// +kubebuilder:printcolumn:name="Outcome",type=string,JSONPath=`.spec.outcome`
+// +kubebuilder:printcolumn:name="Time",type=date,JSONPath=`.spec.time`
+
+// IPChange is the Schema for the ipchanges API
+type IPChange struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IPChangeSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IPChangeList contains a list of IPChange
+type IPChangeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPChange `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IPChange{}, &IPChangeList{})
+}
+
+// IPChangeProviderLabel is set on the IPChanges to the name of the Provider or ClusterProvider that detected the change,
+// so the history of a provider can be listed with kubectl get ipchanges -l ddns.stefangenov.site/provider-name=<name>
+const IPChangeProviderLabel = "ddns.stefangenov.site/provider-name"
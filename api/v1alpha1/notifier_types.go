@@ -19,9 +19,11 @@ package v1alpha1
 import (
 	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // NotifierSpec defines the desired state of Notifier
+// +kubebuilder:validation:XValidation:rule="size(self.secretName) > 0 || has(self.secretRef)",message="one of secretName or secretRef must be set"
 type NotifierSpec struct {
 	// Name is the name of the notifier we want to create.
 	// +kubebuilder:validation:Required
@@ -33,12 +35,44 @@ type NotifierSpec struct {
 	// Notifiers:
 	// - Webhook: The secret should have the following keys:
 	//   - url: .The Webhook URL. Treated as a secret as it may contain sensitive data.
-	// +kubebuilder:validation:Required
+	//
+	// Deprecated: Use SecretRef instead, which also allows the key names to be configured.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=""
 	SecretName string `json:"secretName"`
 
+	// SecretRef is a reference to the secret that holds the notifier specific configuration.
+	// The names of the keys that are read from the secret can be overridden with `keys`, which allows
+	// using existing secrets (e.g. created by External Secrets) that do not follow the expected key names.
+	// Either SecretName or SecretRef must be set. If both are set, SecretRef takes precedence.
+	// +kubebuilder:validation:Optional
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+
 	// ConfigMap is the name of the config map that holds the provider specific configuration.
 	// +kubebuilder:validation:Required
 	ConfigMap string `json:"configMap"`
+
+	// ProviderSelector selects the Providers and ClusterProviders the notifier reports on,
+	// in addition to the ones that reference it in their notifierRefs.
+	// +kubebuilder:validation:Optional
+	ProviderSelector *ProviderSelector `json:"providerSelector,omitempty"`
+
+	// Suspend tells the controller to suspend the reconciliation of this Notifier.
+	// No notifications are sent while suspended, but the last known status is kept.
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// NotifyOnDegraded also sends a notification when a Provider the notifier reports on becomes Degraded,
+	// i.e. its reconciliations failed FailureThreshold times in a row. It is sent once, until the Provider recovers.
+	// +kubebuilder:validation:Optional
+	NotifyOnDegraded bool `json:"notifyOnDegraded,omitempty"`
+
+	// NotificationRetries is how many times a notification that could not be delivered is retried, waiting twice as long
+	// after every failed attempt, before it is dropped.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=5
+	NotificationRetries *int32 `json:"notificationRetries,omitempty"`
 }
 
 // NotifierStatus defines the observed state of Notifier
@@ -47,6 +81,40 @@ type NotifierStatus struct {
 	// It is set to true when the notifier is ready to send notifications.
 	IsReady bool `json:"isReady,omitempty"`
 
+	// ConsecutiveFailures is the number of greetings that failed since the notifier was last ready.
+	// It is used to back off the greetings that probe if the notifier recovered.
+	// +optional
+	ConsecutiveFailures int64 `json:"consecutiveFailures,omitempty"`
+
+	// ObservedResources identifies the Secret and ConfigMap used for the last successful reconciliation.
+	// The ResourcesChanged condition reports if they changed since.
+	// +optional
+	ObservedResources *ObservedResources `json:"observedResources,omitempty"`
+
+	// LastNotificationTime is when the last notification was delivered.
+	// +optional
+	LastNotificationTime *metav1.Time `json:"lastNotificationTime,omitempty"`
+
+	// NotificationsSent is how many notifications were delivered, including the retried and the test ones.
+	// +optional
+	NotificationsSent int64 `json:"notificationsSent,omitempty"`
+
+	// FailedDeliveries is how many attempts to deliver a notification failed.
+	// +optional
+	FailedDeliveries int64 `json:"failedDeliveries,omitempty"`
+
+	// PendingNotifications are the notifications that could not be delivered yet and are retried, oldest first.
+	// +optional
+	PendingNotifications []PendingNotification `json:"pendingNotifications,omitempty"`
+
+	// NotifiedProviders holds what the notifier last reported about every provider it reports on, so it is not reported again.
+	// +optional
+	NotifiedProviders []NotifiedProvider `json:"notifiedProviders,omitempty"`
+
+	// LastHandledTest is the last value of the test annotation that a test message was sent for.
+	// +optional
+	LastHandledTest string `json:"lastHandledTest,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Notifier.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
@@ -61,11 +129,55 @@ type NotifierStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
+// NotifiedProvider is what the notifier last reported about a Provider or ClusterProvider
+type NotifiedProvider struct {
+	// Kind is either Provider or ClusterProvider.
+	// +kubebuilder:validation:Enum=Provider;ClusterProvider
+	Kind string `json:"kind"`
+
+	// Name is the name of the provider.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Provider, empty for ClusterProviders.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Value is the IP of the provider that was reported, or Degraded once the notifier reported that the provider is Degraded.
+	Value string `json:"value"`
+}
+
+// PendingNotification is a notification that could not be delivered yet
+type PendingNotification struct {
+	// Provider is the namespace/name of the Provider, or the name of the ClusterProvider, the notification is about.
+	Provider string `json:"provider"`
+
+	// Value is what the notification reports about the provider. It is recorded in the NotifiedProviders once the
+	// notification is delivered.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Message is the notification that is retried.
+	Message string `json:"message"`
+
+	// Attempts is how many times delivering the notification failed.
+	Attempts int32 `json:"attempts"`
+
+	// NextAttemptTime is when the notification is retried.
+	NextAttemptTime metav1.Time `json:"nextAttemptTime"`
+
+	// LastError is the error of the last failed attempt.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
 // +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretName`
 // +kubebuilder:printcolumn:name="ConfigMap",type=string,JSONPath=`.spec.configMap`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Notifier is the Schema for the notifiers API
 type Notifier struct {
@@ -89,6 +201,67 @@ func init() {
 	SchemeBuilder.Register(&Notifier{}, &NotifierList{})
 }
 
+// NotifierObject is implemented by both Notifier and ClusterNotifier, so they can be reconciled the same way.
+// +kubebuilder:object:generate=false
+type NotifierObject interface {
+	client.Object
+	GetNotifierSpec() *NotifierSpec
+	GetNotifierStatus() *NotifierStatus
+	Conditions() *conditions.Conditions
+}
+
+// GetNotifierSpec returns the spec of the Notifier
+func (n *Notifier) GetNotifierSpec() *NotifierSpec {
+	return &n.Spec
+}
+
+// GetNotifierStatus returns the status of the Notifier
+func (n *Notifier) GetNotifierStatus() *NotifierStatus {
+	return &n.Status
+}
+
+// NotifierFinalizer is added to every Notifier and ClusterNotifier so the annotations it wrote onto the Providers can be
+// removed on deletion
+const NotifierFinalizer = "ddns.stefangenov.site/notifier-finalizer"
+
+// TestAnnotation sends a one-off test message through a Notifier whenever its value changes, the result is reported in the
+// Tested condition, e.g. `kubectl annotate notifier webhook ddns.stefangenov.site/test="$(date +%s)" --overwrite`
+const TestAnnotation = "ddns.stefangenov.site/test"
+
+// DefaultNotificationRetries is how many times a notification that could not be delivered is retried, if NotificationRetries is not set
+const DefaultNotificationRetries = 5
+
+// GetNotificationRetries returns the NotificationRetries, or the DefaultNotificationRetries if it is not set
+func (s NotifierSpec) GetNotificationRetries() int32 {
+	if s.NotificationRetries == nil {
+		return DefaultNotificationRetries
+	}
+
+	return *s.NotificationRetries
+}
+
+// GetSecretRef returns the SecretRef of the Notifier, falling back to SecretName if it is not set.
+func (s NotifierSpec) GetSecretRef() SecretRef {
+	if s.SecretRef != nil {
+		return *s.SecretRef
+	}
+
+	return SecretRef{Name: s.SecretName}
+}
+
+// Default fills in the defaults of the NotifierSpec
+func (s *NotifierSpec) Default() {
+	if s.ProviderSelector == nil {
+		return
+	}
+
+	for i := range s.ProviderSelector.Refs {
+		if s.ProviderSelector.Refs[i].Kind == "" {
+			s.ProviderSelector.Refs[i].Kind = ProviderKind
+		}
+	}
+}
+
 // =================================================== Status ===================================================
 
 const (
@@ -97,6 +270,15 @@ const (
 	NotifierConditionTypeConfigMap = "ConfigMap"
 
 	NotifierConditionTypeSecret = "Secret"
+
+	// NotifierConditionTypeReady summarizes all the other conditions
+	NotifierConditionTypeReady = conditions.ReadyConditionType
+
+	// NotifierConditionTypeResourcesChanged is True if the Secret or ConfigMap changed since the last successful reconciliation
+	NotifierConditionTypeResourcesChanged = "ResourcesChanged"
+
+	// NotifierConditionTypeTested reports if the last test message requested with the TestAnnotation was delivered
+	NotifierConditionTypeTested = "Tested"
 )
 
 func (n *Notifier) Conditions() *conditions.Conditions {
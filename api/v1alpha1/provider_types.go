@@ -17,11 +17,22 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
 	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ProviderSpec defines the desired state of Provider
+// +kubebuilder:validation:XValidation:rule="size(self.configMap) > 0 || has(self.config)",message="one of configMap or config must be set"
+// +kubebuilder:validation:XValidation:rule="size(self.secretName) > 0 || has(self.secretRef)",message="one of secretName or secretRef must be set"
+// +kubebuilder:validation:XValidation:rule="self.name == oldSelf.name || self.secretName != oldSelf.secretName || has(self.secretRef) != has(oldSelf.secretRef) || (has(self.secretRef) && self.secretRef.name != oldSelf.secretRef.name)",message="name can only be changed together with the secret, as the records are left behind at the old provider"
 type ProviderSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -36,37 +47,431 @@ type ProviderSpec struct {
 	// Providers:
 	// - Cloudflare: The secret should have the following keys:
 	//   - apiToken: The Cloudflare API token.
-	// +kubebuilder:validation:Required
+	//
+	// Deprecated: Use SecretRef instead, which also allows the key names to be configured.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=""
 	SecretName string `json:"secretName"`
 
+	// SecretRef is a reference to the secret that holds the provider specific configuration.
+	// The names of the keys that are read from the secret can be overridden with `keys`, which allows
+	// using existing secrets (e.g. created by External Secrets) that do not follow the expected key names.
+	// Either SecretName or SecretRef must be set. If both are set, SecretRef takes precedence.
+	// +kubebuilder:validation:Optional
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+
 	// ConfigMap is the name of the config map that holds the provider specific configuration.
-	// +kubebuilder:validation:Required
+	// Either ConfigMap or Config must be set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=""
 	ConfigMap string `json:"configMap"`
 
-	// RetryInterval is the interval in seconds that the provider should wait before retrying to update the IP.
-	// Default is 900 seconds (15 minutes).
+	// Config is the provider specific configuration inlined in the Provider.
+	// It can be used instead of ConfigMap for simple setups. If both are set, Config takes precedence.
+	// +kubebuilder:validation:Optional
+	Config *ProviderConfig `json:"config,omitempty"`
+
+	// RetryInterval is how long the provider should wait before checking the IP again.
+	// It is a duration like `15m` or `1h`. Integers are still accepted as a number of seconds for backwards compatibility.
+	// Must be at least 1 minute, to not hammer the APIs of the provider. Default is 15 minutes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 60 : duration(self) >= duration('1m')",message="retryInterval must be at least 1m"
+	// +kubebuilder:default:="15m"
+	RetryInterval *intstr.IntOrString `json:"retryInterval,omitempty"`
+
+	// ErrorRetryInterval is how long the provider should wait before retrying after a failed reconciliation.
+	// The interval doubles with every consecutive failure, up to RetryInterval. Takes the same values as RetryInterval.
+	// If not set, the default of the controller (`--error-retry-interval`) is used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 10 : duration(self) >= duration('10s')",message="errorRetryInterval must be at least 10s"
+	ErrorRetryInterval *intstr.IntOrString `json:"errorRetryInterval,omitempty"`
+
+	// DriftCheckInterval is how often the public IP is compared with the one set at the provider, without calling the API of
+	// the provider. The records are then only verified at the provider every RetryInterval, or as soon as the public IP changes.
+	// Takes the same values as RetryInterval. If not set, the records are verified every RetryInterval.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 30 : duration(self) >= duration('30s')",message="driftCheckInterval must be at least 30s"
+	DriftCheckInterval *intstr.IntOrString `json:"driftCheckInterval,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed reconciliations after which the Provider is reported as Degraded,
+	// so single transient errors are not surfaced. Default is 3.
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:default:=900
-	RetryInterval int64 `json:"retryInterval"`
+	// +kubebuilder:validation:Minimum:=1
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 
 	// CustomIPProvider is the URL of the custom IP provider that should be used to get the IP.
 	// If this is set, the provider will use this URL to get the IP FIRST, but will fallback to the rest of the IP providers.
 	// +kubebuilder:validation:Optional
 	CustomIPProvider string `json:"customIPProvider"`
 
+	// OverrideIP skips the public IP detection and forces the records to this address.
+	// An IPv4 address is used for A records and an IPv6 address for AAAA records, the other family is still detected.
+	// Useful when failing over to a backup site or pinning the records during maintenance.
+	// +kubebuilder:validation:Optional
+	OverrideIP string `json:"overrideIP,omitempty"`
+
+	// NodeAddress takes the public IP from the addresses of Nodes instead of detecting it with an echo service, e.g. on
+	// bare-metal clusters where the ingress node has a public ExternalIP. The records follow the address when it changes or
+	// the node is replaced. OverrideIP still takes precedence. Cannot be used if the controller only watches some namespaces.
+	// +kubebuilder:validation:Optional
+	NodeAddress *NodeAddressSource `json:"nodeAddress,omitempty"`
+
+	// IPVersion controls which IP families the provider keeps in sync.
+	// IPv4 manages A records, IPv6 manages AAAA records and DualStack manages both.
+	// Default is IPv4.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=IPv4;IPv6;DualStack
+	// +kubebuilder:default:=IPv4
+	IPVersion IPVersion `json:"ipVersion,omitempty"`
+
+	// Suspend tells the controller to suspend the reconciliation of this Provider.
+	// No IP lookups or DNS updates are done while suspended, but the last known status is kept.
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DryRun makes the controller detect the public IP and compare it with the provider, without ever updating the records.
+	// What would have been changed is reported in the DryRun condition. Useful when onboarding an existing zone.
+	// +kubebuilder:validation:Optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// AdoptExisting makes the first reconciliation adopt the records as they are at the provider: their values are imported
+	// into the status and they are marked as owned by the controller, but not changed. They are only updated once the
+	// public IP changes from the one detected at the adoption. Eases migrating records that were managed by hand.
+	// +kubebuilder:validation:Optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// UpdateWindows restrict when the records at the provider may be updated, e.g. to nightly maintenance windows.
+	// Drift detected outside of the windows is reported in the UpdateWindow condition and applied once a window opens.
+	// If empty, the records are updated as soon as drift is detected.
+	// +kubebuilder:validation:Optional
+	UpdateWindows []UpdateWindow `json:"updateWindows,omitempty"`
+
+	// PropagationCheck verifies that the records resolve to the new IP at public DNS resolvers after they are updated.
+	// The result is reported in the Propagated condition. Proxied records are not checked, as they resolve to the proxy.
+	// +kubebuilder:validation:Optional
+	PropagationCheck *PropagationCheck `json:"propagationCheck,omitempty"`
+
+	// DeletionPolicy controls what happens to the records at the provider when the Provider is deleted.
+	// Orphan leaves the records as they are, Delete removes the records that are owned by the controller.
+	// Default is Orphan.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Orphan;Delete
+	// +kubebuilder:default:=Orphan
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
 	// Notifiers is a list of notifiers that the provider should use to notify for changes.
 	// +kubebuilder:validation:Optional
 	NotifierRefs []ResourceRef `json:"notifierRefs,omitempty"`
+
+	// Backends route some of the records of the Provider to other providers, e.g. when the domains are spread
+	// over several registrars. Every record is managed by the backend with the longest DomainSuffix it matches,
+	// records that match no backend are managed by the Provider itself.
+	// Requires the records to be in the provider-neutral format, either in Config.Records or in the `records` key of the ConfigMap.
+	// +kubebuilder:validation:Optional
+	Backends []ProviderBackend `json:"backends,omitempty"`
+}
+
+// NodeAddressSource selects the Nodes whose address is used as the public IP.
+type NodeAddressSource struct {
+	// Selector selects the Nodes by their labels, e.g. the ingress nodes. The address of the first Ready Node, by name, is used.
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// AddressType is the type of the address of the Node that is used, either ExternalIP or InternalIP.
+	// Default is ExternalIP.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=ExternalIP;InternalIP
+	// +kubebuilder:default:=ExternalIP
+	AddressType string `json:"addressType,omitempty"`
+}
+
+// PropagationCheck configures the DNS resolvers that the records are resolved at after an update.
+type PropagationCheck struct {
+	// Resolvers are the addresses of the DNS resolvers to query, as `host` or `host:port`. Port 53 is used if not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:default:={"1.1.1.1","8.8.8.8"}
+	Resolvers []string `json:"resolvers,omitempty"`
+}
+
+// UpdateWindow is a recurring window in which the records at the provider may be updated.
+type UpdateWindow struct {
+	// Schedule is a cron expression for when the window opens, e.g. `0 2 * * *` for every night at 2:00.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open, e.g. `2h`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="duration must be positive"
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone the schedule is in, e.g. `Europe/Sofia`. Default is UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ProviderBackend is another provider that manages the records of a Provider under a domain suffix.
+type ProviderBackend struct {
+	// DomainSuffix selects the records managed by the backend, e.g. example.org matches example.org and www.example.org
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	DomainSuffix string `json:"domainSuffix"`
+
+	// Client is the name of the provider that manages the records.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=Cloudflare
+	Client string `json:"client"`
+
+	// SecretRef is a reference to the secret with the credentials of the backend.
+	// It is read from the same namespace as the secret of the Provider.
+	// +kubebuilder:validation:Required
+	SecretRef SecretRef `json:"secretRef"`
+
+	// ConfigRef is an optional reference to a ConfigMap with provider specific settings of the backend.
+	// Its `records` key is replaced by the records routed to the backend.
+	// +kubebuilder:validation:Optional
+	ConfigRef *ConfigMapRef `json:"configRef,omitempty"`
+}
+
+// ProviderConfig is the provider specific configuration inlined in the Provider.
+// Exactly one of Zones, Records or Raw must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.zones), has(self.records), has(self.raw)].filter(x, x).size() == 1",message="exactly one of zones, records or raw must be set"
+type ProviderConfig struct {
+	// Zones is a structured list of zones and the records in them that should be managed.
+	// +kubebuilder:validation:Optional
+	Zones []ZoneConfig `json:"zones,omitempty"`
+
+	// Records is a provider-neutral list of records that should be managed.
+	// Unlike Zones and Raw, it does not depend on the configuration format of the provider.
+	// +kubebuilder:validation:Optional
+	Records []ManagedRecord `json:"records,omitempty"`
+
+	// Raw is the provider specific configuration in the same JSON format as the `config` key of the ConfigMap.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Raw *runtime.RawExtension `json:"raw,omitempty"`
+}
+
+// ZoneConfig is a zone with the records that should be managed in it.
+type ZoneConfig struct {
+	// Name is the name of the zone, e.g. example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Records is the list of records in the zone that should be managed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Records []RecordConfig `json:"records"`
+}
+
+// RecordConfig is a single record that should be managed.
+type RecordConfig struct {
+	// Name is the full name of the record, e.g. www.example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Proxied is whether the record should be proxied by the provider, if supported.
+	// +kubebuilder:validation:Optional
+	Proxied bool `json:"proxied,omitempty"`
+
+	// EnforceProxied reports the record as out of sync when its proxied setting at the provider differs from Proxied,
+	// e.g. because it was toggled in the dashboard, and sets it back. Otherwise the setting is only applied when the IP changes.
+	// +kubebuilder:validation:Optional
+	EnforceProxied bool `json:"enforceProxied,omitempty"`
+}
+
+// ManagedRecord is a provider-neutral record that should be kept in sync with the public IP.
+// Every provider maps it to its own backend, so the same records can be used regardless of the provider.
+type ManagedRecord struct {
+	// Zone is the zone the record belongs to, e.g. example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Zone string `json:"zone"`
+
+	// Name is the full name of the record, e.g. www.example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Type restricts the record to A or AAAA records. If empty, the record is kept in sync for every IP family
+	// enabled by the IPVersion of the Provider.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=A;AAAA
+	Type string `json:"type,omitempty"`
+
+	// TTL is the time to live of the record in seconds. If not set, the TTL at the provider is left untouched.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	TTL int `json:"ttl,omitempty"`
+
+	// Proxied is whether the record should be proxied by the provider, if supported.
+	// +kubebuilder:validation:Optional
+	Proxied bool `json:"proxied,omitempty"`
+
+	// EnforceProxied reports the record as out of sync when its proxied setting at the provider differs from Proxied,
+	// e.g. because it was toggled in the dashboard, and sets it back. Otherwise the setting is only applied when the IP changes.
+	// +kubebuilder:validation:Optional
+	EnforceProxied bool `json:"enforceProxied,omitempty"`
+}
+
+// DefaultRetryInterval is how long the provider waits before checking the IP again, if RetryInterval is not set
+const DefaultRetryInterval = 15 * time.Minute
+
+// DefaultFailureThreshold is the number of consecutive failures after which a Provider is Degraded, if FailureThreshold is not set
+const DefaultFailureThreshold = 3
+
+// DeletionPolicy is what happens to the records at the provider when a Provider is deleted.
+type DeletionPolicy string
+
+const (
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+)
+
+// ProviderFinalizer is added to every Provider so records can be cleaned up according to the DeletionPolicy
+const ProviderFinalizer = "ddns.stefangenov.site/finalizer"
+
+// SyncNowAnnotation triggers an immediate reconciliation of a Provider whenever its value changes,
+// e.g. `kubectl annotate provider cloudflare ddns.stefangenov.site/sync-now="$(date +%s)" --overwrite`
+const SyncNowAnnotation = "ddns.stefangenov.site/sync-now"
+
+// ProtectedAnnotation set to "true" protects a Provider or a ClusterProvider from being deleted while its records are still
+// in use, e.g. by an accidental prune of a GitOps tool. The annotation has to be removed before it can be deleted.
+// It is enforced by a validating webhook that ignores failures, so it has no effect if the webhooks are disabled or down
+const ProtectedAnnotation = "ddns.stefangenov.site/protected"
+
+// IPVersion is the IP family (or families) that a Provider manages.
+type IPVersion string
+
+const (
+	IPVersionIPv4      IPVersion = "IPv4"
+	IPVersionIPv6      IPVersion = "IPv6"
+	IPVersionDualStack IPVersion = "DualStack"
+)
+
+// IPv4Enabled returns true if A records should be managed.
+// An empty IPVersion is treated as IPv4 for objects created before the field existed.
+func (v IPVersion) IPv4Enabled() bool {
+	return v == "" || v == IPVersionIPv4 || v == IPVersionDualStack
+}
+
+// IPv6Enabled returns true if AAAA records should be managed.
+func (v IPVersion) IPv6Enabled() bool {
+	return v == IPVersionIPv6 || v == IPVersionDualStack
 }
 
 // ProviderStatus defines the observed state of Provider
 type ProviderStatus struct {
 	// ProviderIP is the IP address that the provider has set.
+	// If the records have different IPs, they are joined with a comma. See Records for the state of every record.
 	ProviderIP string `json:"providerIP,omitempty"`
 
 	// PublicIP is your public IP address.
 	PublicIP string `json:"publicIP,omitempty"`
 
+	// ProviderIPv6 is the IPv6 address that the provider has set.
+	// Only populated when the IPVersion is IPv6 or DualStack.
+	ProviderIPv6 string `json:"providerIPv6,omitempty"`
+
+	// PublicIPv6 is your public IPv6 address.
+	// Only populated when the IPVersion is IPv6 or DualStack.
+	PublicIPv6 string `json:"publicIPv6,omitempty"`
+
+	// Records is the state of every record managed by the Provider.
+	// +optional
+	Records []RecordStatus `json:"records,omitempty"`
+
+	// Zones is the state of every zone of the Provider, if its records are grouped in zones.
+	// A zone that fails, e.g. because the token lacks permission for it, does not keep the other zones from being synced.
+	// +optional
+	Zones []ProviderZoneStatus `json:"zones,omitempty"`
+
+	// LastSyncTime is the time of the last successful reconciliation of the Provider that verified the records at the provider.
+	// Drift checks between verifications (see DriftCheckInterval) do not update it.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastIPChangeTime is the time the records at the provider were last updated with a new IP.
+	// +optional
+	LastIPChangeTime *metav1.Time `json:"lastIPChangeTime,omitempty"`
+
+	// LastHeartbeatTime is the last time the controller reconciled the Provider, even if nothing changed or it failed.
+	// It is updated at most once per heartbeat interval of the controller, so a stale one means the controller is not running.
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+
+	// ControllerVersion is the version of the controller that last reconciled the Provider.
+	// +optional
+	ControllerVersion string `json:"controllerVersion,omitempty"`
+
+	// ClientVersion is the library the records are managed with and its version, e.g. github.com/cloudflare/cloudflare-go@v0.101.0,
+	// as of the last reconciliation. The libraries of the Backends are joined with a comma.
+	// +optional
+	ClientVersion string `json:"clientVersion,omitempty"`
+
+	// OutOfSyncSince is the time the records were first found out of sync after an update, if they still are.
+	// While set, the Provider is requeued sooner than the RetryInterval.
+	// +optional
+	OutOfSyncSince *metav1.Time `json:"outOfSyncSince,omitempty"`
+
+	// AdoptedTime is the time the existing records were adopted, see AdoptExisting.
+	// +optional
+	AdoptedTime *metav1.Time `json:"adoptedTime,omitempty"`
+
+	// AdoptedPublicIP is the public IP detected when the A records were adopted. The records are left as they are until the
+	// public IP changes from it, after which it is cleared.
+	// +optional
+	AdoptedPublicIP string `json:"adoptedPublicIP,omitempty"`
+
+	// AdoptedPublicIPv6 is the public IPv6 address detected when the AAAA records were adopted, like AdoptedPublicIP.
+	// +optional
+	AdoptedPublicIPv6 string `json:"adoptedPublicIPv6,omitempty"`
+
+	// DesyncedSince is the time the public IP was first found to differ from the records at the provider, until the records
+	// are verified to be in sync again. It is kept through failed reconciliations.
+	// +optional
+	DesyncedSince *metav1.Time `json:"desyncedSince,omitempty"`
+
+	// LastDesyncDuration is how long the records were out of sync the last time, from DesyncedSince until they were
+	// verified to be in sync again.
+	// +optional
+	LastDesyncDuration *metav1.Duration `json:"lastDesyncDuration,omitempty"`
+
+	// SyncCount is the number of successful reconciliations of the Provider.
+	// +optional
+	SyncCount int64 `json:"syncCount,omitempty"`
+
+	// FailedSyncCount is the number of reconciliations of the Provider that returned an error.
+	// +optional
+	FailedSyncCount int64 `json:"failedSyncCount,omitempty"`
+
+	// LastError is the error of the last failed reconciliation. It is cleared on the next successful one.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// Message is a human readable summary of the state of the Provider, e.g. "All 4 records synced to 1.2.3.4".
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ConsecutiveFailures is the number of reconciliations that failed since the last successful one.
+	// It is used to back off the ErrorRetryInterval.
+	ConsecutiveFailures int64 `json:"consecutiveFailures,omitempty"`
+
+	// ObservedResources identifies the Secret and ConfigMap used for the last successful reconciliation.
+	// The ResourcesChanged condition reports if they changed since.
+	// +optional
+	ObservedResources *ObservedResources `json:"observedResources,omitempty"`
+
+	// LastHandledSyncNow is the last value of the sync-now annotation that triggered a reconciliation.
+	// +optional
+	LastHandledSyncNow string `json:"lastHandledSyncNow,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Provider.
 	// This gets updated at the end of a successful reconciliation.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -82,6 +487,40 @@ type ProviderStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
+// ProviderZoneStatus is the state of a single zone of the Provider
+type ProviderZoneStatus struct {
+	// Name is the name of the zone, e.g. example.com
+	Name string `json:"name"`
+
+	// Conditions of the zone. The Ready condition is False while the records of the zone cannot be read or updated.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RecordStatus is the state of a single record managed by the Provider
+type RecordStatus struct {
+	// FQDN is the fully qualified domain name of the record.
+	FQDN string `json:"fqdn"`
+
+	// Type is the type of the record, A or AAAA.
+	Type string `json:"type"`
+
+	// CurrentValue is the value of the record at the provider. Empty if the record does not exist.
+	// +optional
+	CurrentValue string `json:"currentValue,omitempty"`
+
+	// DesiredValue is the value the record should have, i.e. the detected public IP.
+	DesiredValue string `json:"desiredValue"`
+
+	// Synced is true when the current value of the record matches the desired one.
+	Synced bool `json:"synced"`
+
+	// ProxiedDrift is true when the proxied setting of the record at the provider differs from the configured one.
+	// Only reported for records with EnforceProxied, which are not Synced then.
+	// +optional
+	ProxiedDrift bool `json:"proxiedDrift,omitempty"`
+}
+
 type ProviderCondition struct {
 	Type    string `json:"type"`
 	Status  string `json:"status"`
@@ -90,7 +529,14 @@ type ProviderCondition struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="PublicIP",type=string,JSONPath=`.status.publicIP`
+// +kubebuilder:printcolumn:name="ProviderIP",type=string,JSONPath=`.status.providerIP`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
 
 // Provider is the Schema for the providers API
 type Provider struct {
@@ -114,6 +560,192 @@ func init() {
 	SchemeBuilder.Register(&Provider{}, &ProviderList{})
 }
 
+// ProviderObject is implemented by both Provider and ClusterProvider, so they can be reconciled the same way.
+// +kubebuilder:object:generate=false
+type ProviderObject interface {
+	client.Object
+	GetProviderSpec() *ProviderSpec
+	GetProviderStatus() *ProviderStatus
+	Conditions() *conditions.Conditions
+}
+
+// GetProviderSpec returns the spec of the Provider
+func (p *Provider) GetProviderSpec() *ProviderSpec {
+	return &p.Spec
+}
+
+// GetProviderStatus returns the status of the Provider
+func (p *Provider) GetProviderStatus() *ProviderStatus {
+	return &p.Status
+}
+
+// GetRetryInterval returns the RetryInterval as a duration, treating integers as seconds.
+// Invalid values are rejected by the API server, but fall back to the default of 15 minutes just in case.
+func (s ProviderSpec) GetRetryInterval() time.Duration {
+	return intervalDuration(s.RetryInterval, DefaultRetryInterval)
+}
+
+// GetErrorRetryInterval returns the ErrorRetryInterval as a duration, treating integers as seconds.
+// It returns 0 if the ErrorRetryInterval is not set.
+func (s ProviderSpec) GetErrorRetryInterval() time.Duration {
+	return intervalDuration(s.ErrorRetryInterval, 0)
+}
+
+// GetDriftCheckInterval returns the DriftCheckInterval as a duration, treating integers as seconds.
+// It returns 0 if the DriftCheckInterval is not set.
+func (s ProviderSpec) GetDriftCheckInterval() time.Duration {
+	return intervalDuration(s.DriftCheckInterval, 0)
+}
+
+// GetFailureThreshold returns the FailureThreshold, or the DefaultFailureThreshold if it is not set
+func (s ProviderSpec) GetFailureThreshold() int64 {
+	if s.FailureThreshold < 1 {
+		return DefaultFailureThreshold
+	}
+
+	return int64(s.FailureThreshold)
+}
+
+// intervalDuration converts an interval to a duration, falling back to the given default if it is not set or invalid
+func intervalDuration(interval *intstr.IntOrString, defaultInterval time.Duration) time.Duration {
+	if interval == nil {
+		return defaultInterval
+	}
+
+	if interval.Type == intstr.Int {
+		return time.Duration(interval.IntValue()) * time.Second
+	}
+
+	duration, err := time.ParseDuration(interval.StrVal)
+	if err != nil || duration <= 0 {
+		return defaultInterval
+	}
+
+	return duration
+}
+
+// GetSecretRef returns the SecretRef of the Provider, falling back to SecretName if it is not set.
+func (s ProviderSpec) GetSecretRef() SecretRef {
+	if s.SecretRef != nil {
+		return *s.SecretRef
+	}
+
+	return SecretRef{Name: s.SecretName}
+}
+
+// Default fills in the defaults of the ProviderSpec and normalizes the names of the zones and records,
+// so the stored object is the same regardless of how it was written
+func (s *ProviderSpec) Default() {
+	if s.RetryInterval == nil {
+		retryInterval := intstr.FromString("15m")
+		s.RetryInterval = &retryInterval
+	}
+
+	if s.IPVersion == "" {
+		s.IPVersion = IPVersionIPv4
+	}
+
+	if s.DeletionPolicy == "" {
+		s.DeletionPolicy = DeletionPolicyOrphan
+	}
+
+	for i := range s.NotifierRefs {
+		if s.NotifierRefs[i].Kind == "" {
+			s.NotifierRefs[i].Kind = NotifierKind
+		}
+	}
+
+	for i := range s.Backends {
+		s.Backends[i].DomainSuffix = normalizeName(s.Backends[i].DomainSuffix)
+	}
+
+	if s.Config == nil {
+		return
+	}
+
+	for i := range s.Config.Zones {
+		zone := &s.Config.Zones[i]
+		zone.Name = normalizeName(zone.Name)
+
+		for j := range zone.Records {
+			zone.Records[j].Name = recordFQDN(zone.Records[j].Name, zone.Name)
+		}
+	}
+
+	for i := range s.Config.Records {
+		record := &s.Config.Records[i]
+		record.Zone = normalizeName(record.Zone)
+		record.Name = recordFQDN(record.Name, record.Zone)
+	}
+}
+
+// normalizeName lowercases a DNS name and removes the trailing dot of absolute names
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// recordFQDN returns the lowercase FQDN of a record in the given zone.
+// `@` is the zone apex and names that are not in the zone yet are treated as relative to it, e.g. `www` becomes `www.example.com`
+func recordFQDN(name, zone string) string {
+	name = normalizeName(name)
+
+	if name == "@" {
+		return zone
+	}
+
+	if zone == "" || name == zone || strings.HasSuffix(name, "."+zone) {
+		return name
+	}
+
+	return name + "." + zone
+}
+
+// Warnings describes the settings of the ProviderSpec that are valid but risky, so they can be pointed out when the
+// Provider is applied. The names of the records are expected to be normalized by Default
+func (s ProviderSpec) Warnings() []string {
+	warnings := []string{}
+
+	if u, err := url.Parse(s.CustomIPProvider); err == nil && strings.EqualFold(u.Scheme, "http") {
+		warnings = append(warnings, "customIPProvider uses plain HTTP, so the detected public IP can be tampered with in transit")
+	}
+
+	if interval := s.GetErrorRetryInterval(); interval > 0 && interval < time.Minute {
+		warnings = append(warnings, fmt.Sprintf(
+			"errorRetryInterval of %s is under 1m, so a persistent failure calls the API of the provider more than once a minute", interval,
+		))
+	}
+
+	if interval := s.GetDriftCheckInterval(); interval > 0 && interval < time.Minute {
+		warnings = append(warnings, fmt.Sprintf(
+			"driftCheckInterval of %s is under 1m, so the public IP is detected more than once a minute", interval,
+		))
+	}
+
+	if s.Config == nil {
+		return warnings
+	}
+
+	apexWarning := func(name string) string {
+		return fmt.Sprintf("apex record %s is not proxied, so it exposes the public IP", name)
+	}
+
+	for _, zone := range s.Config.Zones {
+		for _, record := range zone.Records {
+			if record.Name == zone.Name && !record.Proxied {
+				warnings = append(warnings, apexWarning(record.Name))
+			}
+		}
+	}
+
+	for _, record := range s.Config.Records {
+		if record.Name == record.Zone && !record.Proxied {
+			warnings = append(warnings, apexWarning(record.Name))
+		}
+	}
+
+	return warnings
+}
+
 // =================================================== Status ===================================================
 
 const (
@@ -122,6 +754,41 @@ const (
 	ProviderConditionTypeConfigMap = "ConfigMap"
 
 	ProviderConditionTypeSecret = "Secret"
+
+	// ProviderConditionTypeReady summarizes all the other conditions
+	ProviderConditionTypeReady = conditions.ReadyConditionType
+
+	// ProviderConditionTypeDryRun is only present while the Provider is in DryRun mode
+	ProviderConditionTypeDryRun = "DryRun"
+
+	// ProviderConditionTypeUpdateWindow is only present if the Provider has UpdateWindows and reports if updates are deferred
+	ProviderConditionTypeUpdateWindow = "UpdateWindow"
+
+	// ProviderConditionTypePropagated is only present if the Provider has a PropagationCheck and reports if the records
+	// resolve to the new IP at the resolvers
+	ProviderConditionTypePropagated = "Propagated"
+
+	// ProviderConditionTypeDriftDetected is only present once records were changed outside of the controller and reports
+	// which ones, until a later reconciliation finds no such changes
+	ProviderConditionTypeDriftDetected = "DriftDetected"
+
+	// ProviderConditionTypeRetrying is only present while the Provider is backing off after failed reconciliations
+	ProviderConditionTypeRetrying = "Retrying"
+
+	// ProviderConditionTypeResourcesChanged is True if the Secret or ConfigMap changed since the last successful reconciliation
+	ProviderConditionTypeResourcesChanged = "ResourcesChanged"
+
+	// ProviderConditionTypeDegraded is True once the reconciliations failed FailureThreshold times in a row,
+	// until the next successful one
+	ProviderConditionTypeDegraded = "Degraded"
+
+	// ProviderConditionTypeSynced is True if every record at the provider had the detected public IP when it was last verified,
+	// i.e. as of the LastSyncTime. Unlike Ready, it does not depend on the client, the Secret or the ConfigMap
+	ProviderConditionTypeSynced = "Synced"
+
+	// ProviderConditionTypeNotifierRefs is only present if the Provider has NotifierRefs and reports if they all point at
+	// existing notifiers, or which ones do not
+	ProviderConditionTypeNotifierRefs = "NotifierRefs"
 )
 
 func (p *Provider) Conditions() *conditions.Conditions {
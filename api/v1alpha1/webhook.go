@@ -0,0 +1,84 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the conversion and defaulting webhooks for Providers, and the validating webhook if
+// a validator is given
+func (r *Provider) SetupWebhookWithManager(mgr ctrl.Manager, validator admission.CustomValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&providerDefaulter{}).
+		WithValidator(validator).
+		Complete()
+}
+
+// SetupWebhookWithManager registers the conversion and defaulting webhooks for Notifiers, and the validating webhook if
+// a validator is given
+func (r *Notifier) SetupWebhookWithManager(mgr ctrl.Manager, validator admission.CustomValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&notifierDefaulter{}).
+		WithValidator(validator).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-ddns-stefangenov-site-v1alpha1-provider,mutating=true,failurePolicy=fail,sideEffects=None,groups=ddns.stefangenov.site,resources=providers,verbs=create;update,versions=v1alpha1,name=mprovider.kb.io,admissionReviewVersions=v1
+
+// providerDefaulter fills in the defaults of Providers before they are stored
+type providerDefaulter struct{}
+
+var _ admission.CustomDefaulter = &providerDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *providerDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	provider, ok := obj.(*Provider)
+	if !ok {
+		return fmt.Errorf("expected a Provider but got a %T", obj)
+	}
+
+	provider.Spec.Default()
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/mutate-ddns-stefangenov-site-v1alpha1-notifier,mutating=true,failurePolicy=fail,sideEffects=None,groups=ddns.stefangenov.site,resources=notifiers,verbs=create;update,versions=v1alpha1,name=mnotifier.kb.io,admissionReviewVersions=v1
+
+// notifierDefaulter fills in the defaults of Notifiers before they are stored
+type notifierDefaulter struct{}
+
+var _ admission.CustomDefaulter = &notifierDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *notifierDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	notifier, ok := obj.(*Notifier)
+	if !ok {
+		return fmt.Errorf("expected a Notifier but got a %T", obj)
+	}
+
+	notifier.Spec.Default()
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Defaulting webhook", func() {
+	Context("Provider", func() {
+		It("should fill in the defaults", func() {
+			provider := &Provider{
+				Spec: ProviderSpec{
+					Name:         "Cloudflare",
+					NotifierRefs: []ResourceRef{{Name: "webhook"}, {Kind: ClusterNotifierKind, Name: "webhook"}},
+				},
+			}
+
+			Expect((&providerDefaulter{}).Default(context.Background(), provider)).To(Succeed())
+
+			Expect(provider.Spec.RetryInterval).To(Equal(ptr.To(intstr.FromString("15m"))))
+			Expect(provider.Spec.IPVersion).To(Equal(IPVersionIPv4))
+			Expect(provider.Spec.DeletionPolicy).To(Equal(DeletionPolicyOrphan))
+			Expect(provider.Spec.NotifierRefs).To(Equal([]ResourceRef{
+				{Kind: NotifierKind, Name: "webhook"},
+				{Kind: ClusterNotifierKind, Name: "webhook"},
+			}))
+		})
+
+		It("should keep the values that are set", func() {
+			spec := ProviderSpec{
+				RetryInterval:  ptr.To(intstr.FromInt32(300)),
+				IPVersion:      IPVersionDualStack,
+				DeletionPolicy: DeletionPolicyDelete,
+			}
+
+			spec.Default()
+
+			Expect(spec.RetryInterval).To(Equal(ptr.To(intstr.FromInt32(300))))
+			Expect(spec.IPVersion).To(Equal(IPVersionDualStack))
+			Expect(spec.DeletionPolicy).To(Equal(DeletionPolicyDelete))
+		})
+
+		It("should normalize the record names to lowercase FQDNs", func() {
+			spec := ProviderSpec{
+				Config: &ProviderConfig{
+					Zones: []ZoneConfig{{
+						Name:    "Example.COM.",
+						Records: []RecordConfig{{Name: "@"}, {Name: "WWW"}, {Name: "api.example.com."}},
+					}},
+					Records: []ManagedRecord{{Zone: "example.org", Name: "Home.Example.org"}, {Zone: "example.org", Name: "vpn"}},
+				},
+			}
+
+			spec.Default()
+
+			Expect(spec.Config.Zones[0].Name).To(Equal("example.com"))
+			Expect(spec.Config.Zones[0].Records).To(Equal([]RecordConfig{
+				{Name: "example.com"}, {Name: "www.example.com"}, {Name: "api.example.com"},
+			}))
+			Expect(spec.Config.Records).To(Equal([]ManagedRecord{
+				{Zone: "example.org", Name: "home.example.org"}, {Zone: "example.org", Name: "vpn.example.org"},
+			}))
+		})
+
+		It("should reject other objects", func() {
+			Expect((&providerDefaulter{}).Default(context.Background(), &Notifier{})).NotTo(Succeed())
+		})
+
+		It("should warn about risky settings", func() {
+			spec := ProviderSpec{
+				CustomIPProvider:   "http://ip.example.com",
+				ErrorRetryInterval: ptr.To(intstr.FromInt32(30)),
+				DriftCheckInterval: ptr.To(intstr.FromString("1m")),
+				Config: &ProviderConfig{
+					Zones: []ZoneConfig{{
+						Name:    "example.com",
+						Records: []RecordConfig{{Name: "@"}, {Name: "www"}},
+					}},
+					Records: []ManagedRecord{{Zone: "example.org", Name: "example.org", Proxied: true}},
+				},
+			}
+
+			spec.Default()
+
+			Expect(spec.Warnings()).To(Equal([]string{
+				"customIPProvider uses plain HTTP, so the detected public IP can be tampered with in transit",
+				"errorRetryInterval of 30s is under 1m, so a persistent failure calls the API of the provider more than once a minute",
+				"apex record example.com is not proxied, so it exposes the public IP",
+			}))
+
+			Expect(ProviderSpec{CustomIPProvider: "https://ip.example.com"}.Warnings()).To(BeEmpty())
+		})
+	})
+
+	Context("Notifier", func() {
+		It("should fill in the kind of the provider refs", func() {
+			notifier := &Notifier{
+				Spec: NotifierSpec{
+					ProviderSelector: &ProviderSelector{Refs: []ProviderRef{{Name: "cloudflare"}}},
+				},
+			}
+
+			Expect((&notifierDefaulter{}).Default(context.Background(), notifier)).To(Succeed())
+
+			Expect(notifier.Spec.ProviderSelector.Refs).To(Equal([]ProviderRef{{Kind: ProviderKind, Name: "cloudflare"}}))
+		})
+	})
+})
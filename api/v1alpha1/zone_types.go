@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ZoneSpec defines the desired state of Zone
+type ZoneSpec struct {
+	// ProviderRef is the Provider, in the same namespace, or the ClusterProvider whose credentials and public IP are used for the zone.
+	// +kubebuilder:validation:Required
+	ProviderRef ProviderRef `json:"providerRef"`
+
+	// Name is the name of the zone, e.g. example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Records is the list of records in the zone that should be kept in sync with the public IP of the Provider.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Records []RecordConfig `json:"records"`
+}
+
+// ZoneStatus defines the observed state of Zone
+type ZoneStatus struct {
+	// ProviderIP is the IPv4 address currently set for the records of the zone.
+	ProviderIP string `json:"providerIP,omitempty"`
+
+	// ProviderIPv6 is the IPv6 address currently set for the records of the zone.
+	ProviderIPv6 string `json:"providerIPv6,omitempty"`
+
+	// LastSyncTime is the last time the records of the zone were successfully checked against the provider.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this Zone.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Represents the observations of a Zone's current state.
+	// For further information see: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Zone",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.providerRef.name`
+// +kubebuilder:printcolumn:name="ProviderIP",type=string,JSONPath=`.status.providerIP`
+// +kubebuilder:printcolumn:name="LastSync",type=date,JSONPath=`.status.lastSyncTime`
+
+// Zone is the Schema for the zones API
+type Zone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ZoneSpec   `json:"spec,omitempty"`
+	Status ZoneStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ZoneList contains a list of Zone
+type ZoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Zone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Zone{}, &ZoneList{})
+}
+
+// =================================================== Status ===================================================
+
+const (
+	ZoneConditionTypeProvider = "Provider"
+
+	ZoneConditionTypeClient = "Client"
+
+	ZoneConditionTypeSynced = "Synced"
+)
+
+func (z *Zone) Conditions() *conditions.Conditions {
+	return &conditions.Conditions{
+		Conditions:     &z.Status.Conditions,
+		ConditionTypes: []string{ZoneConditionTypeProvider, ZoneConditionTypeClient, ZoneConditionTypeSynced},
+	}
+}
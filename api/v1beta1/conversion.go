@@ -0,0 +1,365 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// ConvertTo converts this Provider to the hub version (v1alpha1)
+func (src *Provider) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Provider)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.Name = src.Spec.Name
+	dst.Spec.SecretName, dst.Spec.SecretRef = secretRefToHub(src.Spec.SecretRef)
+	dst.Spec.ConfigMap, dst.Spec.Config = configToHub(src.Spec.Config)
+	dst.Spec.RetryInterval = retryIntervalToHub(src.Spec.RetryInterval)
+	dst.Spec.ErrorRetryInterval = retryIntervalToHub(src.Spec.ErrorRetryInterval)
+	dst.Spec.DriftCheckInterval = retryIntervalToHub(src.Spec.DriftCheckInterval)
+	dst.Spec.FailureThreshold = src.Spec.FailureThreshold
+	dst.Spec.CustomIPProvider = src.Spec.CustomIPProvider
+	dst.Spec.OverrideIP = src.Spec.OverrideIP
+	dst.Spec.NodeAddress = (*v1alpha1.NodeAddressSource)(src.Spec.NodeAddress)
+	dst.Spec.IPVersion = v1alpha1.IPVersion(src.Spec.IPVersion)
+	dst.Spec.Suspend = src.Spec.Suspend
+	dst.Spec.DryRun = src.Spec.DryRun
+	dst.Spec.AdoptExisting = src.Spec.AdoptExisting
+	dst.Spec.DeletionPolicy = v1alpha1.DeletionPolicy(src.Spec.DeletionPolicy)
+
+	dst.Spec.NotifierRefs = nil
+	for _, ref := range src.Spec.NotifierRefs {
+		dst.Spec.NotifierRefs = append(dst.Spec.NotifierRefs, v1alpha1.ResourceRef{Kind: ref.Kind, Name: ref.Name, Namespace: ref.Namespace})
+	}
+
+	dst.Spec.UpdateWindows = nil
+	for _, window := range src.Spec.UpdateWindows {
+		dst.Spec.UpdateWindows = append(dst.Spec.UpdateWindows, v1alpha1.UpdateWindow(window))
+	}
+
+	dst.Spec.PropagationCheck = (*v1alpha1.PropagationCheck)(src.Spec.PropagationCheck)
+
+	dst.Spec.Backends = nil
+	for _, backend := range src.Spec.Backends {
+		dst.Spec.Backends = append(dst.Spec.Backends, v1alpha1.ProviderBackend{
+			DomainSuffix: backend.DomainSuffix,
+			Client:       backend.Client,
+			SecretRef:    v1alpha1.SecretRef(backend.SecretRef),
+			ConfigRef:    (*v1alpha1.ConfigMapRef)(backend.ConfigRef),
+		})
+	}
+
+	dst.Status.ProviderIP = src.Status.ProviderIP
+	dst.Status.PublicIP = src.Status.PublicIP
+	dst.Status.ProviderIPv6 = src.Status.ProviderIPv6
+	dst.Status.PublicIPv6 = src.Status.PublicIPv6
+	dst.Status.LastSyncTime = src.Status.LastSyncTime
+	dst.Status.LastIPChangeTime = src.Status.LastIPChangeTime
+	dst.Status.LastHeartbeatTime = src.Status.LastHeartbeatTime
+	dst.Status.ControllerVersion = src.Status.ControllerVersion
+	dst.Status.ClientVersion = src.Status.ClientVersion
+	dst.Status.OutOfSyncSince = src.Status.OutOfSyncSince
+	dst.Status.AdoptedTime = src.Status.AdoptedTime
+	dst.Status.AdoptedPublicIP = src.Status.AdoptedPublicIP
+	dst.Status.AdoptedPublicIPv6 = src.Status.AdoptedPublicIPv6
+	dst.Status.DesyncedSince = src.Status.DesyncedSince
+	dst.Status.LastDesyncDuration = src.Status.LastDesyncDuration
+	dst.Status.SyncCount = src.Status.SyncCount
+	dst.Status.FailedSyncCount = src.Status.FailedSyncCount
+	dst.Status.LastError = src.Status.LastError
+	dst.Status.Message = src.Status.Message
+	dst.Status.ConsecutiveFailures = src.Status.ConsecutiveFailures
+	dst.Status.ObservedResources = (*v1alpha1.ObservedResources)(src.Status.ObservedResources)
+	dst.Status.LastHandledSyncNow = src.Status.LastHandledSyncNow
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+
+	dst.Status.Records = nil
+	for _, record := range src.Status.Records {
+		dst.Status.Records = append(dst.Status.Records, v1alpha1.RecordStatus(record))
+	}
+
+	dst.Status.Zones = nil
+	for _, zone := range src.Status.Zones {
+		dst.Status.Zones = append(dst.Status.Zones, v1alpha1.ProviderZoneStatus(zone))
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1alpha1) to this Provider
+func (dst *Provider) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Provider)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.Name = src.Spec.Name
+	dst.Spec.SecretRef = secretRefFromHub(src.Spec.SecretName, src.Spec.SecretRef)
+	dst.Spec.Config = configFromHub(src.Spec.ConfigMap, src.Spec.Config)
+	dst.Spec.RetryInterval = &metav1.Duration{Duration: src.Spec.GetRetryInterval()}
+	if src.Spec.ErrorRetryInterval != nil {
+		dst.Spec.ErrorRetryInterval = &metav1.Duration{Duration: src.Spec.GetErrorRetryInterval()}
+	}
+	if src.Spec.DriftCheckInterval != nil {
+		dst.Spec.DriftCheckInterval = &metav1.Duration{Duration: src.Spec.GetDriftCheckInterval()}
+	}
+	dst.Spec.FailureThreshold = src.Spec.FailureThreshold
+	dst.Spec.CustomIPProvider = src.Spec.CustomIPProvider
+	dst.Spec.OverrideIP = src.Spec.OverrideIP
+	dst.Spec.NodeAddress = (*NodeAddressSource)(src.Spec.NodeAddress)
+	dst.Spec.IPVersion = string(src.Spec.IPVersion)
+	dst.Spec.Suspend = src.Spec.Suspend
+	dst.Spec.DryRun = src.Spec.DryRun
+	dst.Spec.AdoptExisting = src.Spec.AdoptExisting
+	dst.Spec.DeletionPolicy = string(src.Spec.DeletionPolicy)
+
+	dst.Spec.NotifierRefs = nil
+	for _, ref := range src.Spec.NotifierRefs {
+		dst.Spec.NotifierRefs = append(dst.Spec.NotifierRefs, NotifierRef{Kind: ref.Kind, Name: ref.Name, Namespace: ref.Namespace})
+	}
+
+	dst.Spec.UpdateWindows = nil
+	for _, window := range src.Spec.UpdateWindows {
+		dst.Spec.UpdateWindows = append(dst.Spec.UpdateWindows, UpdateWindow(window))
+	}
+
+	dst.Spec.PropagationCheck = (*PropagationCheck)(src.Spec.PropagationCheck)
+
+	dst.Spec.Backends = nil
+	for _, backend := range src.Spec.Backends {
+		dst.Spec.Backends = append(dst.Spec.Backends, ProviderBackend{
+			DomainSuffix: backend.DomainSuffix,
+			Client:       backend.Client,
+			SecretRef:    SecretRef(backend.SecretRef),
+			ConfigRef:    (*ConfigMapRef)(backend.ConfigRef),
+		})
+	}
+
+	dst.Status.ProviderIP = src.Status.ProviderIP
+	dst.Status.PublicIP = src.Status.PublicIP
+	dst.Status.ProviderIPv6 = src.Status.ProviderIPv6
+	dst.Status.PublicIPv6 = src.Status.PublicIPv6
+	dst.Status.LastSyncTime = src.Status.LastSyncTime
+	dst.Status.LastIPChangeTime = src.Status.LastIPChangeTime
+	dst.Status.LastHeartbeatTime = src.Status.LastHeartbeatTime
+	dst.Status.ControllerVersion = src.Status.ControllerVersion
+	dst.Status.ClientVersion = src.Status.ClientVersion
+	dst.Status.OutOfSyncSince = src.Status.OutOfSyncSince
+	dst.Status.AdoptedTime = src.Status.AdoptedTime
+	dst.Status.AdoptedPublicIP = src.Status.AdoptedPublicIP
+	dst.Status.AdoptedPublicIPv6 = src.Status.AdoptedPublicIPv6
+	dst.Status.DesyncedSince = src.Status.DesyncedSince
+	dst.Status.LastDesyncDuration = src.Status.LastDesyncDuration
+	dst.Status.SyncCount = src.Status.SyncCount
+	dst.Status.FailedSyncCount = src.Status.FailedSyncCount
+	dst.Status.LastError = src.Status.LastError
+	dst.Status.Message = src.Status.Message
+	dst.Status.ConsecutiveFailures = src.Status.ConsecutiveFailures
+	dst.Status.ObservedResources = (*ObservedResources)(src.Status.ObservedResources)
+	dst.Status.LastHandledSyncNow = src.Status.LastHandledSyncNow
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+
+	dst.Status.Records = nil
+	for _, record := range src.Status.Records {
+		dst.Status.Records = append(dst.Status.Records, RecordStatus(record))
+	}
+
+	dst.Status.Zones = nil
+	for _, zone := range src.Status.Zones {
+		dst.Status.Zones = append(dst.Status.Zones, ProviderZoneStatus(zone))
+	}
+
+	return nil
+}
+
+// ConvertTo converts this Notifier to the hub version (v1alpha1)
+func (src *Notifier) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Notifier)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.Name = src.Spec.Name
+	dst.Spec.SecretName, dst.Spec.SecretRef = secretRefToHub(src.Spec.SecretRef)
+	dst.Spec.ConfigMap = src.Spec.ConfigMapRef.Name
+	dst.Spec.Suspend = src.Spec.Suspend
+	dst.Spec.NotifyOnDegraded = src.Spec.NotifyOnDegraded
+	dst.Spec.NotificationRetries = src.Spec.NotificationRetries
+
+	dst.Spec.ProviderSelector = nil
+	if src.Spec.ProviderSelector != nil {
+		dst.Spec.ProviderSelector = &v1alpha1.ProviderSelector{Selector: src.Spec.ProviderSelector.Selector}
+		for _, ref := range src.Spec.ProviderSelector.Refs {
+			dst.Spec.ProviderSelector.Refs = append(dst.Spec.ProviderSelector.Refs, v1alpha1.ProviderRef(ref))
+		}
+	}
+
+	dst.Status.IsReady = src.Status.IsReady
+	dst.Status.ConsecutiveFailures = src.Status.ConsecutiveFailures
+	dst.Status.ObservedResources = (*v1alpha1.ObservedResources)(src.Status.ObservedResources)
+	dst.Status.LastHandledTest = src.Status.LastHandledTest
+	dst.Status.LastNotificationTime = src.Status.LastNotificationTime
+	dst.Status.NotificationsSent = src.Status.NotificationsSent
+	dst.Status.FailedDeliveries = src.Status.FailedDeliveries
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+
+	dst.Status.PendingNotifications = nil
+	for _, pending := range src.Status.PendingNotifications {
+		dst.Status.PendingNotifications = append(dst.Status.PendingNotifications, v1alpha1.PendingNotification(pending))
+	}
+
+	dst.Status.NotifiedProviders = nil
+	for _, notified := range src.Status.NotifiedProviders {
+		dst.Status.NotifiedProviders = append(dst.Status.NotifiedProviders, v1alpha1.NotifiedProvider(notified))
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1alpha1) to this Notifier
+func (dst *Notifier) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Notifier)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.Name = src.Spec.Name
+	dst.Spec.SecretRef = secretRefFromHub(src.Spec.SecretName, src.Spec.SecretRef)
+	dst.Spec.ConfigMapRef = ConfigMapRef{Name: src.Spec.ConfigMap}
+	dst.Spec.Suspend = src.Spec.Suspend
+	dst.Spec.NotifyOnDegraded = src.Spec.NotifyOnDegraded
+	dst.Spec.NotificationRetries = src.Spec.NotificationRetries
+
+	dst.Spec.ProviderSelector = nil
+	if src.Spec.ProviderSelector != nil {
+		dst.Spec.ProviderSelector = &ProviderSelector{Selector: src.Spec.ProviderSelector.Selector}
+		for _, ref := range src.Spec.ProviderSelector.Refs {
+			dst.Spec.ProviderSelector.Refs = append(dst.Spec.ProviderSelector.Refs, ProviderRef(ref))
+		}
+	}
+
+	dst.Status.IsReady = src.Status.IsReady
+	dst.Status.ConsecutiveFailures = src.Status.ConsecutiveFailures
+	dst.Status.ObservedResources = (*ObservedResources)(src.Status.ObservedResources)
+	dst.Status.LastHandledTest = src.Status.LastHandledTest
+	dst.Status.LastNotificationTime = src.Status.LastNotificationTime
+	dst.Status.NotificationsSent = src.Status.NotificationsSent
+	dst.Status.FailedDeliveries = src.Status.FailedDeliveries
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+
+	dst.Status.PendingNotifications = nil
+	for _, pending := range src.Status.PendingNotifications {
+		dst.Status.PendingNotifications = append(dst.Status.PendingNotifications, PendingNotification(pending))
+	}
+
+	dst.Status.NotifiedProviders = nil
+	for _, notified := range src.Status.NotifiedProviders {
+		dst.Status.NotifiedProviders = append(dst.Status.NotifiedProviders, NotifiedProvider(notified))
+	}
+
+	return nil
+}
+
+// secretRefToHub converts the SecretRef to the deprecated secretName if it does not map any keys,
+// so objects created as v1alpha1 look the same after a round trip
+func secretRefToHub(ref SecretRef) (string, *v1alpha1.SecretRef) {
+	if len(ref.Keys) == 0 {
+		return ref.Name, nil
+	}
+
+	return "", &v1alpha1.SecretRef{Name: ref.Name, Keys: ref.Keys}
+}
+
+// secretRefFromHub returns the SecretRef of the hub, falling back to the deprecated secretName
+func secretRefFromHub(secretName string, ref *v1alpha1.SecretRef) SecretRef {
+	if ref != nil {
+		return SecretRef{Name: ref.Name, Keys: ref.Keys}
+	}
+
+	return SecretRef{Name: secretName}
+}
+
+func configToHub(config ProviderConfig) (string, *v1alpha1.ProviderConfig) {
+	configMap := ""
+	if config.ConfigMapRef != nil {
+		configMap = config.ConfigMapRef.Name
+	}
+
+	if config.Zones == nil && config.Records == nil && config.Raw == nil {
+		return configMap, nil
+	}
+
+	hubConfig := &v1alpha1.ProviderConfig{Raw: config.Raw}
+	for _, zone := range config.Zones {
+		hubZone := v1alpha1.ZoneConfig{Name: zone.Name}
+		for _, record := range zone.Records {
+			hubZone.Records = append(hubZone.Records, v1alpha1.RecordConfig(record))
+		}
+
+		hubConfig.Zones = append(hubConfig.Zones, hubZone)
+	}
+
+	for _, record := range config.Records {
+		hubConfig.Records = append(hubConfig.Records, v1alpha1.ManagedRecord(record))
+	}
+
+	return configMap, hubConfig
+}
+
+func configFromHub(configMap string, hubConfig *v1alpha1.ProviderConfig) ProviderConfig {
+	config := ProviderConfig{}
+	if configMap != "" {
+		config.ConfigMapRef = &ConfigMapRef{Name: configMap}
+	}
+
+	if hubConfig == nil {
+		return config
+	}
+
+	config.Raw = hubConfig.Raw
+	for _, hubZone := range hubConfig.Zones {
+		zone := ZoneConfig{Name: hubZone.Name}
+		for _, record := range hubZone.Records {
+			zone.Records = append(zone.Records, RecordConfig(record))
+		}
+
+		config.Zones = append(config.Zones, zone)
+	}
+
+	for _, record := range hubConfig.Records {
+		config.Records = append(config.Records, ManagedRecord(record))
+	}
+
+	return config
+}
+
+// retryIntervalToHub converts the interval to the duration string used by v1alpha1
+func retryIntervalToHub(interval *metav1.Duration) *intstr.IntOrString {
+	if interval == nil {
+		return nil
+	}
+
+	hubInterval := intstr.FromString(interval.Duration.String())
+
+	return &hubInterval
+}
@@ -0,0 +1,153 @@
+package v1beta1_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1beta1"
+)
+
+var _ = Describe("Conversion", func() {
+	Context("Provider", func() {
+		It("should convert a v1alpha1 Provider to v1beta1 and back", func() {
+			hub := &v1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "provider", Namespace: "default"},
+				Spec: v1alpha1.ProviderSpec{
+					Name:             "Cloudflare",
+					SecretName:       "cloudflare",
+					ConfigMap:        "cloudflare-config",
+					RetryInterval:    ptr.To(intstr.FromString("1m0s")),
+					CustomIPProvider: "https://ip.example.com",
+					OverrideIP:       "127.0.0.10",
+					NodeAddress: &v1alpha1.NodeAddressSource{
+						Selector:    metav1.LabelSelector{MatchLabels: map[string]string{"node-role.kubernetes.io/ingress": ""}},
+						AddressType: "ExternalIP",
+					},
+					IPVersion:      v1alpha1.IPVersionDualStack,
+					DryRun:         true,
+					DeletionPolicy: v1alpha1.DeletionPolicyDelete,
+					NotifierRefs:   []v1alpha1.ResourceRef{{Kind: "ClusterNotifier", Name: "webhook"}, {Name: "email", Namespace: "mail"}},
+					Backends: []v1alpha1.ProviderBackend{{
+						DomainSuffix: "example.org",
+						Client:       "Cloudflare",
+						SecretRef:    v1alpha1.SecretRef{Name: "registrar", Keys: map[string]string{"apiToken": "token"}},
+						ConfigRef:    &v1alpha1.ConfigMapRef{Name: "registrar-config"},
+					}},
+				},
+				Status: v1alpha1.ProviderStatus{
+					ProviderIP: "127.0.0.1",
+					PublicIP:   "127.0.0.2",
+					SyncCount:  3,
+					Records:    []v1alpha1.RecordStatus{{FQDN: "example.com", Type: "A", DesiredValue: "127.0.0.2"}},
+				},
+			}
+
+			spoke := &v1beta1.Provider{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			Expect(spoke.Spec.SecretRef).To(Equal(v1beta1.SecretRef{Name: "cloudflare"}))
+			Expect(spoke.Spec.Config.ConfigMapRef).To(Equal(&v1beta1.ConfigMapRef{Name: "cloudflare-config"}))
+			Expect(spoke.Spec.RetryInterval.Duration).To(Equal(time.Minute))
+			Expect(spoke.Spec.IPVersion).To(Equal("DualStack"))
+			Expect(spoke.Spec.NotifierRefs).To(Equal([]v1beta1.NotifierRef{{Kind: "ClusterNotifier", Name: "webhook"}, {Name: "email", Namespace: "mail"}}))
+			Expect(spoke.Status.Records).To(HaveLen(1))
+
+			converted := &v1alpha1.Provider{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+
+		It("should convert a v1beta1 Provider with inline config and mapped secret keys to v1alpha1 and back", func() {
+			spoke := &v1beta1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "provider", Namespace: "default"},
+				Spec: v1beta1.ProviderSpec{
+					Name: "Cloudflare",
+					SecretRef: v1beta1.SecretRef{
+						Name: "existing",
+						Keys: map[string]string{"apiToken": "token"},
+					},
+					Config: v1beta1.ProviderConfig{
+						Zones: []v1beta1.ZoneConfig{{
+							Name:    "example.com",
+							Records: []v1beta1.RecordConfig{{Name: "example.com", Proxied: true}},
+						}},
+					},
+					RetryInterval: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			}
+
+			hub := &v1alpha1.Provider{}
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+
+			Expect(hub.Spec.SecretName).To(BeEmpty())
+			Expect(hub.Spec.SecretRef).To(Equal(&v1alpha1.SecretRef{Name: "existing", Keys: map[string]string{"apiToken": "token"}}))
+			Expect(hub.Spec.ConfigMap).To(BeEmpty())
+			Expect(hub.Spec.Config.Zones).To(HaveLen(1))
+			Expect(hub.Spec.GetRetryInterval()).To(Equal(5 * time.Minute))
+
+			converted := &v1beta1.Provider{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(spoke))
+		})
+
+		It("should keep the raw config", func() {
+			spoke := &v1beta1.Provider{
+				Spec: v1beta1.ProviderSpec{
+					Config: v1beta1.ProviderConfig{
+						Raw: &runtime.RawExtension{Raw: []byte(`{"cloudflare":{"zones":[]}}`)},
+					},
+				},
+			}
+
+			hub := &v1alpha1.Provider{}
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Config.Raw).To(Equal(spoke.Spec.Config.Raw))
+			Expect(hub.Spec.GetRetryInterval()).To(Equal(15 * time.Minute))
+		})
+	})
+
+	Context("Notifier", func() {
+		It("should convert a v1alpha1 Notifier to v1beta1 and back", func() {
+			hub := &v1alpha1.Notifier{
+				ObjectMeta: metav1.ObjectMeta{Name: "notifier", Namespace: "default"},
+				Spec: v1alpha1.NotifierSpec{
+					Name:                "Webhook",
+					SecretName:          "webhook",
+					ConfigMap:           "webhook-config",
+					Suspend:             true,
+					NotificationRetries: ptr.To[int32](3),
+				},
+				Status: v1alpha1.NotifierStatus{
+					IsReady:            true,
+					ObservedGeneration: 2,
+					PendingNotifications: []v1alpha1.PendingNotification{
+						{Provider: "provider", Message: "Provider IP changed", Attempts: 1, LastError: "timeout"},
+					},
+					NotifiedProviders: []v1alpha1.NotifiedProvider{
+						{Kind: v1alpha1.ProviderKind, Name: "provider", Namespace: "default", Value: "1.2.3.4"},
+					},
+				},
+			}
+
+			spoke := &v1beta1.Notifier{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			Expect(spoke.Spec.SecretRef).To(Equal(v1beta1.SecretRef{Name: "webhook"}))
+			Expect(spoke.Spec.ConfigMapRef).To(Equal(v1beta1.ConfigMapRef{Name: "webhook-config"}))
+			Expect(spoke.Status.IsReady).To(BeTrue())
+			Expect(spoke.Status.PendingNotifications).To(HaveLen(1))
+			Expect(spoke.Status.NotifiedProviders).To(HaveLen(1))
+
+			converted := &v1alpha1.Notifier{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretRef is a reference to a Secret with optional overrides for the names of the keys read from it.
+type SecretRef struct {
+	// Name is the name of the secret.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Keys maps the key that the provider or notifier expects (e.g. `apiToken` or `url`) to the key in the secret that holds the value.
+	// Keys that are not mapped are read as is.
+	// +kubebuilder:validation:Optional
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+// ConfigMapRef is a reference to a ConfigMap in the namespace of the referencing resource.
+type ConfigMapRef struct {
+	// Name is the name of the config map.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+}
+
+// NotifierRef is a reference to either a Notifier in the same namespace or a ClusterNotifier.
+type NotifierRef struct {
+	// Kind is the kind of the referenced notifier.
+	// Default is Notifier.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Notifier;ClusterNotifier
+	// +kubebuilder:default:=Notifier
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the referenced notifier.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the referenced Notifier. Defaults to the namespace of the Provider.
+	// Notifiers in other namespaces are only used if the controller runs with `--allow-cross-namespace-notifier-refs`.
+	// Ignored for ClusterNotifiers.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ProviderRef is a reference to either a Provider in the same namespace or a ClusterProvider.
+type ProviderRef struct {
+	// Kind is the kind of the referenced provider.
+	// Default is Provider.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Provider;ClusterProvider
+	// +kubebuilder:default:=Provider
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the referenced provider.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// ProviderSelector selects Providers and ClusterProviders by their labels and/or by reference.
+// A Notifier only selects Providers in its own namespace (and any ClusterProvider), while a ClusterNotifier selects
+// Providers in every namespace.
+type ProviderSelector struct {
+	// Selector selects the Providers and ClusterProviders with matching labels. An empty selector selects all of them.
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Refs lists Providers and ClusterProviders explicitly, by name.
+	// +kubebuilder:validation:Optional
+	Refs []ProviderRef `json:"refs,omitempty"`
+}
+
+// ObservedResources identifies the Secret and ConfigMap data that was used for the last successful reconciliation.
+type ObservedResources struct {
+	// SecretHash is the hash of the data of the Secret.
+	// +optional
+	SecretHash string `json:"secretHash,omitempty"`
+
+	// ConfigMapHash is the hash of the data of the ConfigMap. Empty if the config is inline in the spec.
+	// +optional
+	ConfigMapHash string `json:"configMapHash,omitempty"`
+}
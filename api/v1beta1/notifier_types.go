@@ -0,0 +1,177 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotifierSpec defines the desired state of Notifier
+type NotifierSpec struct {
+	// Name is the name of the notifier we want to create.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=Webhook
+	Name string `json:"name"`
+
+	// SecretRef is a reference to the secret that holds the notifier specific configuration.
+	// Notifiers:
+	// - Webhook: The secret should have the following keys:
+	//   - url: The Webhook URL. Treated as a secret as it may contain sensitive data.
+	// +kubebuilder:validation:Required
+	SecretRef SecretRef `json:"secretRef"`
+
+	// ConfigMapRef is a reference to the config map that holds the notifier specific configuration.
+	// +kubebuilder:validation:Required
+	ConfigMapRef ConfigMapRef `json:"configMapRef"`
+
+	// ProviderSelector selects the Providers and ClusterProviders the notifier reports on,
+	// in addition to the ones that reference it in their notifierRefs.
+	// +kubebuilder:validation:Optional
+	ProviderSelector *ProviderSelector `json:"providerSelector,omitempty"`
+
+	// Suspend tells the controller to suspend the reconciliation of this Notifier.
+	// No notifications are sent while suspended, but the last known status is kept.
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// NotifyOnDegraded also sends a notification when a Provider the notifier reports on becomes Degraded,
+	// i.e. its reconciliations failed FailureThreshold times in a row. It is sent once, until the Provider recovers.
+	// +kubebuilder:validation:Optional
+	NotifyOnDegraded bool `json:"notifyOnDegraded,omitempty"`
+
+	// NotificationRetries is how many times a notification that could not be delivered is retried, waiting twice as long
+	// after every failed attempt, before it is dropped.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=5
+	NotificationRetries *int32 `json:"notificationRetries,omitempty"`
+}
+
+// NotifierStatus defines the observed state of Notifier
+type NotifierStatus struct {
+	// IsReady is the status of the notifier.
+	// It is set to true when the notifier is ready to send notifications.
+	IsReady bool `json:"isReady,omitempty"`
+
+	// ConsecutiveFailures is the number of greetings that failed since the notifier was last ready.
+	// It is used to back off the greetings that probe if the notifier recovered.
+	// +optional
+	ConsecutiveFailures int64 `json:"consecutiveFailures,omitempty"`
+
+	// ObservedResources identifies the Secret and ConfigMap used for the last successful reconciliation.
+	// +optional
+	ObservedResources *ObservedResources `json:"observedResources,omitempty"`
+
+	// LastNotificationTime is when the last notification was delivered.
+	// +optional
+	LastNotificationTime *metav1.Time `json:"lastNotificationTime,omitempty"`
+
+	// NotificationsSent is how many notifications were delivered, including the retried and the test ones.
+	// +optional
+	NotificationsSent int64 `json:"notificationsSent,omitempty"`
+
+	// FailedDeliveries is how many attempts to deliver a notification failed.
+	// +optional
+	FailedDeliveries int64 `json:"failedDeliveries,omitempty"`
+
+	// PendingNotifications are the notifications that could not be delivered yet and are retried, oldest first.
+	// +optional
+	PendingNotifications []PendingNotification `json:"pendingNotifications,omitempty"`
+
+	// NotifiedProviders holds what the notifier last reported about every provider it reports on, so it is not reported again.
+	// +optional
+	NotifiedProviders []NotifiedProvider `json:"notifiedProviders,omitempty"`
+
+	// LastHandledTest is the last value of the test annotation that a test message was sent for.
+	// +optional
+	LastHandledTest string `json:"lastHandledTest,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this Notifier.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the observations of the Notifier's current state.
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+// NotifiedProvider is what the notifier last reported about a Provider or ClusterProvider
+type NotifiedProvider struct {
+	// Kind is either Provider or ClusterProvider.
+	// +kubebuilder:validation:Enum=Provider;ClusterProvider
+	Kind string `json:"kind"`
+
+	// Name is the name of the provider.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Provider, empty for ClusterProviders.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Value is the IP of the provider that was reported, or Degraded once the notifier reported that the provider is Degraded.
+	Value string `json:"value"`
+}
+
+// PendingNotification is a notification that could not be delivered yet
+type PendingNotification struct {
+	// Provider is the namespace/name of the Provider, or the name of the ClusterProvider, the notification is about.
+	Provider string `json:"provider"`
+
+	// Value is what the notification reports about the provider. It is recorded in the NotifiedProviders once the
+	// notification is delivered.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Message is the notification that is retried.
+	Message string `json:"message"`
+
+	// Attempts is how many times delivering the notification failed.
+	Attempts int32 `json:"attempts"`
+
+	// NextAttemptTime is when the notification is retried.
+	NextAttemptTime metav1.Time `json:"nextAttemptTime"`
+
+	// LastError is the error of the last failed attempt.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Notifier is the Schema for the notifiers API
+type Notifier struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotifierSpec   `json:"spec,omitempty"`
+	Status NotifierStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotifierList contains a list of Notifier
+type NotifierList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Notifier `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Notifier{}, &NotifierList{})
+}
@@ -0,0 +1,459 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ProviderSpec defines the desired state of Provider
+// +kubebuilder:validation:XValidation:rule="self.name == oldSelf.name || self.secretRef.name != oldSelf.secretRef.name",message="name can only be changed together with the secret, as the records are left behind at the old provider"
+type ProviderSpec struct {
+	// Name is the name of the provider we want to create.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=Cloudflare
+	Name string `json:"name"`
+
+	// SecretRef is a reference to the secret that holds the provider specific configuration.
+	// Providers:
+	// - Cloudflare: The secret should have the following keys:
+	//   - apiToken: The Cloudflare API token.
+	// +kubebuilder:validation:Required
+	SecretRef SecretRef `json:"secretRef"`
+
+	// Config is the provider specific configuration.
+	// +kubebuilder:validation:Required
+	Config ProviderConfig `json:"config"`
+
+	// RetryInterval is how long the provider should wait before checking the IP again.
+	// Must be at least 1 minute, to not hammer the APIs of the provider. Default is 15 minutes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1m')",message="retryInterval must be at least 1m"
+	// +kubebuilder:default:="15m"
+	RetryInterval *metav1.Duration `json:"retryInterval,omitempty"`
+
+	// ErrorRetryInterval is how long the provider should wait before retrying after a failed reconciliation.
+	// The interval doubles with every consecutive failure, up to RetryInterval.
+	// If not set, the default of the controller (`--error-retry-interval`) is used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('10s')",message="errorRetryInterval must be at least 10s"
+	ErrorRetryInterval *metav1.Duration `json:"errorRetryInterval,omitempty"`
+
+	// DriftCheckInterval is how often the public IP is compared with the one set at the provider, without calling the API of
+	// the provider. The records are then only verified at the provider every RetryInterval, or as soon as the public IP changes.
+	// If not set, the records are verified every RetryInterval.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('30s')",message="driftCheckInterval must be at least 30s"
+	DriftCheckInterval *metav1.Duration `json:"driftCheckInterval,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed reconciliations after which the Provider is reported as Degraded,
+	// so single transient errors are not surfaced. Default is 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// CustomIPProvider is the URL of the custom IP provider that should be used to get the IP.
+	// If this is set, the provider will use this URL to get the IP FIRST, but will fallback to the rest of the IP providers.
+	// +kubebuilder:validation:Optional
+	CustomIPProvider string `json:"customIPProvider,omitempty"`
+
+	// OverrideIP skips the public IP detection and forces the records to this address.
+	// An IPv4 address is used for A records and an IPv6 address for AAAA records, the other family is still detected.
+	// +kubebuilder:validation:Optional
+	OverrideIP string `json:"overrideIP,omitempty"`
+
+	// NodeAddress takes the public IP from the addresses of Nodes instead of detecting it with an echo service, e.g. on
+	// bare-metal clusters where the ingress node has a public ExternalIP. The records follow the address when it changes or
+	// the node is replaced. OverrideIP still takes precedence. Cannot be used if the controller only watches some namespaces.
+	// +kubebuilder:validation:Optional
+	NodeAddress *NodeAddressSource `json:"nodeAddress,omitempty"`
+
+	// IPVersion controls which IP families the provider keeps in sync.
+	// IPv4 manages A records, IPv6 manages AAAA records and DualStack manages both.
+	// Default is IPv4.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=IPv4;IPv6;DualStack
+	// +kubebuilder:default:=IPv4
+	IPVersion string `json:"ipVersion,omitempty"`
+
+	// Suspend tells the controller to suspend the reconciliation of this Provider.
+	// No IP lookups or DNS updates are done while suspended, but the last known status is kept.
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DryRun makes the controller detect the public IP and compare it with the provider, without ever updating the records.
+	// What would have been changed is reported in the DryRun condition.
+	// +kubebuilder:validation:Optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// AdoptExisting makes the first reconciliation adopt the records as they are at the provider: their values are imported
+	// into the status and they are marked as owned by the controller, but not changed. They are only updated once the
+	// public IP changes from the one detected at the adoption. Eases migrating records that were managed by hand.
+	// +kubebuilder:validation:Optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// UpdateWindows restrict when the records at the provider may be updated, e.g. to nightly maintenance windows.
+	// +kubebuilder:validation:Optional
+	UpdateWindows []UpdateWindow `json:"updateWindows,omitempty"`
+
+	// PropagationCheck verifies that the records resolve to the new IP at public DNS resolvers after they are updated.
+	// The result is reported in the Propagated condition. Proxied records are not checked, as they resolve to the proxy.
+	// +kubebuilder:validation:Optional
+	PropagationCheck *PropagationCheck `json:"propagationCheck,omitempty"`
+
+	// DeletionPolicy controls what happens to the records at the provider when the Provider is deleted.
+	// Orphan leaves the records as they are, Delete removes the records that are owned by the controller.
+	// Default is Orphan.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=Orphan;Delete
+	// +kubebuilder:default:=Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// NotifierRefs is a list of notifiers that the provider should use to notify for changes.
+	// +kubebuilder:validation:Optional
+	NotifierRefs []NotifierRef `json:"notifierRefs,omitempty"`
+
+	// Backends route some of the records of the Provider to other providers by domain suffix.
+	// Records that match no backend are managed by the Provider itself.
+	// +kubebuilder:validation:Optional
+	Backends []ProviderBackend `json:"backends,omitempty"`
+}
+
+// NodeAddressSource selects the Nodes whose address is used as the public IP.
+type NodeAddressSource struct {
+	// Selector selects the Nodes by their labels, e.g. the ingress nodes. The address of the first Ready Node, by name, is used.
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// AddressType is the type of the address of the Node that is used, either ExternalIP or InternalIP.
+	// Default is ExternalIP.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=ExternalIP;InternalIP
+	// +kubebuilder:default:=ExternalIP
+	AddressType string `json:"addressType,omitempty"`
+}
+
+// PropagationCheck configures the DNS resolvers that the records are resolved at after an update.
+type PropagationCheck struct {
+	// Resolvers are the addresses of the DNS resolvers to query, as `host` or `host:port`. Port 53 is used if not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:default:={"1.1.1.1","8.8.8.8"}
+	Resolvers []string `json:"resolvers,omitempty"`
+}
+
+// UpdateWindow is a recurring window in which the records at the provider may be updated.
+type UpdateWindow struct {
+	// Schedule is a cron expression for when the window opens, e.g. `0 2 * * *` for every night at 2:00.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open, e.g. `2h`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="duration must be positive"
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone the schedule is in, e.g. `Europe/Sofia`. Default is UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ProviderBackend is another provider that manages the records of a Provider under a domain suffix.
+type ProviderBackend struct {
+	// DomainSuffix selects the records managed by the backend, e.g. example.org matches example.org and www.example.org
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	DomainSuffix string `json:"domainSuffix"`
+
+	// Client is the name of the provider that manages the records.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=Cloudflare
+	Client string `json:"client"`
+
+	// SecretRef is a reference to the secret with the credentials of the backend.
+	// +kubebuilder:validation:Required
+	SecretRef SecretRef `json:"secretRef"`
+
+	// ConfigRef is an optional reference to a ConfigMap with provider specific settings of the backend.
+	// +kubebuilder:validation:Optional
+	ConfigRef *ConfigMapRef `json:"configRef,omitempty"`
+}
+
+// ProviderConfig is the provider specific configuration.
+// It is either read from a ConfigMap or set inline with Zones, Records or Raw.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) || has(self.zones) || has(self.records) || has(self.raw)",message="one of configMapRef, zones, records or raw must be set"
+// +kubebuilder:validation:XValidation:rule="[has(self.zones), has(self.records), has(self.raw)].filter(x, x).size() <= 1",message="only one of zones, records or raw can be set"
+type ProviderConfig struct {
+	// ConfigMapRef is a reference to a config map with the configuration in its `config` key.
+	// If Zones, Records or Raw are set as well, they take precedence.
+	// +kubebuilder:validation:Optional
+	ConfigMapRef *ConfigMapRef `json:"configMapRef,omitempty"`
+
+	// Zones is a structured list of zones and the records in them that should be managed.
+	// +kubebuilder:validation:Optional
+	Zones []ZoneConfig `json:"zones,omitempty"`
+
+	// Records is a provider-neutral list of records that should be managed.
+	// Unlike Zones and Raw, it does not depend on the configuration format of the provider.
+	// +kubebuilder:validation:Optional
+	Records []ManagedRecord `json:"records,omitempty"`
+
+	// Raw is the provider specific configuration in the same JSON format as the `config` key of the ConfigMap.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Raw *runtime.RawExtension `json:"raw,omitempty"`
+}
+
+// ZoneConfig is a zone with the records that should be managed in it.
+type ZoneConfig struct {
+	// Name is the name of the zone, e.g. example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Records is the list of records in the zone that should be managed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Records []RecordConfig `json:"records"`
+}
+
+// RecordConfig is a single record that should be managed.
+type RecordConfig struct {
+	// Name is the full name of the record, e.g. www.example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Proxied is whether the record should be proxied by the provider, if supported.
+	// +kubebuilder:validation:Optional
+	Proxied bool `json:"proxied,omitempty"`
+
+	// EnforceProxied reports the record as out of sync when its proxied setting at the provider differs from Proxied,
+	// e.g. because it was toggled in the dashboard, and sets it back. Otherwise the setting is only applied when the IP changes.
+	// +kubebuilder:validation:Optional
+	EnforceProxied bool `json:"enforceProxied,omitempty"`
+}
+
+// ManagedRecord is a provider-neutral record that should be kept in sync with the public IP.
+// Every provider maps it to its own backend, so the same records can be used regardless of the provider.
+type ManagedRecord struct {
+	// Zone is the zone the record belongs to, e.g. example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Zone string `json:"zone"`
+
+	// Name is the full name of the record, e.g. www.example.com
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Type restricts the record to A or AAAA records. If empty, the record is kept in sync for every IP family
+	// enabled by the IPVersion of the Provider.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=A;AAAA
+	Type string `json:"type,omitempty"`
+
+	// TTL is the time to live of the record in seconds. If not set, the TTL at the provider is left untouched.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	TTL int `json:"ttl,omitempty"`
+
+	// Proxied is whether the record should be proxied by the provider, if supported.
+	// +kubebuilder:validation:Optional
+	Proxied bool `json:"proxied,omitempty"`
+
+	// EnforceProxied reports the record as out of sync when its proxied setting at the provider differs from Proxied,
+	// e.g. because it was toggled in the dashboard, and sets it back. Otherwise the setting is only applied when the IP changes.
+	// +kubebuilder:validation:Optional
+	EnforceProxied bool `json:"enforceProxied,omitempty"`
+}
+
+// ProviderStatus defines the observed state of Provider
+type ProviderStatus struct {
+	// ProviderIP is the IP address that the provider has set.
+	// If the records have different IPs, they are joined with a comma. See Records for the state of every record.
+	ProviderIP string `json:"providerIP,omitempty"`
+
+	// PublicIP is your public IP address.
+	PublicIP string `json:"publicIP,omitempty"`
+
+	// ProviderIPv6 is the IPv6 address that the provider has set.
+	ProviderIPv6 string `json:"providerIPv6,omitempty"`
+
+	// PublicIPv6 is your public IPv6 address.
+	PublicIPv6 string `json:"publicIPv6,omitempty"`
+
+	// Records is the state of every record managed by the Provider.
+	// +optional
+	Records []RecordStatus `json:"records,omitempty"`
+
+	// Zones is the state of every zone of the Provider, if its records are grouped in zones.
+	// A zone that fails, e.g. because the token lacks permission for it, does not keep the other zones from being synced.
+	// +optional
+	Zones []ProviderZoneStatus `json:"zones,omitempty"`
+
+	// LastSyncTime is the time of the last successful reconciliation of the Provider that verified the records at the provider.
+	// Drift checks between verifications (see DriftCheckInterval) do not update it.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastIPChangeTime is the time the records at the provider were last updated with a new IP.
+	// +optional
+	LastIPChangeTime *metav1.Time `json:"lastIPChangeTime,omitempty"`
+
+	// LastHeartbeatTime is the last time the controller reconciled the Provider, even if nothing changed or it failed.
+	// It is updated at most once per heartbeat interval of the controller, so a stale one means the controller is not running.
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+
+	// ControllerVersion is the version of the controller that last reconciled the Provider.
+	// +optional
+	ControllerVersion string `json:"controllerVersion,omitempty"`
+
+	// ClientVersion is the library the records are managed with and its version, e.g. github.com/cloudflare/cloudflare-go@v0.101.0,
+	// as of the last reconciliation. The libraries of the Backends are joined with a comma.
+	// +optional
+	ClientVersion string `json:"clientVersion,omitempty"`
+
+	// OutOfSyncSince is the time the records were first found out of sync after an update, if they still are.
+	// While set, the Provider is requeued sooner than the RetryInterval.
+	// +optional
+	OutOfSyncSince *metav1.Time `json:"outOfSyncSince,omitempty"`
+
+	// AdoptedTime is the time the existing records were adopted, see AdoptExisting.
+	// +optional
+	AdoptedTime *metav1.Time `json:"adoptedTime,omitempty"`
+
+	// AdoptedPublicIP is the public IP detected when the A records were adopted. The records are left as they are until the
+	// public IP changes from it, after which it is cleared.
+	// +optional
+	AdoptedPublicIP string `json:"adoptedPublicIP,omitempty"`
+
+	// AdoptedPublicIPv6 is the public IPv6 address detected when the AAAA records were adopted, like AdoptedPublicIP.
+	// +optional
+	AdoptedPublicIPv6 string `json:"adoptedPublicIPv6,omitempty"`
+
+	// DesyncedSince is the time the public IP was first found to differ from the records at the provider, until the records
+	// are verified to be in sync again. It is kept through failed reconciliations.
+	// +optional
+	DesyncedSince *metav1.Time `json:"desyncedSince,omitempty"`
+
+	// LastDesyncDuration is how long the records were out of sync the last time, from DesyncedSince until they were
+	// verified to be in sync again.
+	// +optional
+	LastDesyncDuration *metav1.Duration `json:"lastDesyncDuration,omitempty"`
+
+	// SyncCount is the number of successful reconciliations of the Provider.
+	// +optional
+	SyncCount int64 `json:"syncCount,omitempty"`
+
+	// FailedSyncCount is the number of reconciliations of the Provider that returned an error.
+	// +optional
+	FailedSyncCount int64 `json:"failedSyncCount,omitempty"`
+
+	// LastError is the error of the last failed reconciliation. It is cleared on the next successful one.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// Message is a human readable summary of the state of the Provider.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ConsecutiveFailures is the number of reconciliations that failed since the last successful one.
+	ConsecutiveFailures int64 `json:"consecutiveFailures,omitempty"`
+
+	// ObservedResources identifies the Secret and ConfigMap used for the last successful reconciliation.
+	// +optional
+	ObservedResources *ObservedResources `json:"observedResources,omitempty"`
+
+	// LastHandledSyncNow is the last value of the sync-now annotation that triggered a reconciliation.
+	// +optional
+	LastHandledSyncNow string `json:"lastHandledSyncNow,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this Provider.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the observations of the Provider's current state.
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+// ProviderZoneStatus is the state of a single zone of the Provider
+type ProviderZoneStatus struct {
+	// Name is the name of the zone, e.g. example.com
+	Name string `json:"name"`
+
+	// Conditions of the zone. The Ready condition is False while the records of the zone cannot be read or updated.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RecordStatus is the state of a single record managed by the Provider
+type RecordStatus struct {
+	// FQDN is the fully qualified domain name of the record.
+	FQDN string `json:"fqdn"`
+
+	// Type is the type of the record, A or AAAA.
+	Type string `json:"type"`
+
+	// CurrentValue is the value of the record at the provider. Empty if the record does not exist.
+	// +optional
+	CurrentValue string `json:"currentValue,omitempty"`
+
+	// DesiredValue is the value the record should have, i.e. the detected public IP.
+	DesiredValue string `json:"desiredValue"`
+
+	// Synced is true when the current value of the record matches the desired one.
+	Synced bool `json:"synced"`
+
+	// ProxiedDrift is true when the proxied setting of the record at the provider differs from the configured one.
+	// Only reported for records with EnforceProxied, which are not Synced then.
+	// +optional
+	ProxiedDrift bool `json:"proxiedDrift,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="PublicIP",type=string,JSONPath=`.status.publicIP`
+// +kubebuilder:printcolumn:name="ProviderIP",type=string,JSONPath=`.status.providerIP`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
+
+// Provider is the Schema for the providers API
+type Provider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderSpec   `json:"spec,omitempty"`
+	Status ProviderStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProviderList contains a list of Provider
+type ProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Provider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Provider{}, &ProviderList{})
+}
@@ -17,26 +17,47 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	// Embed the time zone database, since the update windows of Providers may use any time zone and the image has none.
+	_ "time/tzdata"
+
+	"golang.org/x/time/rate"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	ddnsv1beta1 "github.com/Michaelpalacce/go-ddns-controller/api/v1beta1"
 	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
 	"github.com/Michaelpalacce/go-ddns-controller/internal/controller"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/logging"
 	"github.com/Michaelpalacce/go-ddns-controller/internal/network"
 	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/tracing"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/version"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,32 +70,231 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(ddnsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(ddnsv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	var metricsAddr string
+	var secureMetrics bool
 	var enableLeaderElection bool
+	var leaderElectionID string
 	var probeAddr string
+	var pprofAddr string
+	var statusAddr string
+	var debugAddr string
+	var credentialCheck string
+	var credentialCheckQPS float64
+	var clusterResourceNamespace string
+	var namespaces string
+	var ingressClasses string
+	var selector string
+	var allowCrossNamespaceNotifierRefs bool
+	var enableServiceRecords bool
+	var enableDNSEndpoints bool
+	var enableIstio bool
+	var enableTracing bool
+	var controllerLogLevels string
+	var errorRetryInterval time.Duration
+	var driftCheckInterval time.Duration
+	var recordsVerifyInterval time.Duration
+	var syncTimeout time.Duration
+	var ipChangeHistoryLimit int
+	var ipChangeTTL time.Duration
+	var heartbeatInterval time.Duration
+	var requeueJitter float64
+	var unhealthyProvidersThreshold time.Duration
+	var syncPeriod time.Duration
+	var gracefulShutdownTimeout time.Duration
+	var ipCacheTTL time.Duration
+	var httpProxy string
+	var providerAPIQPS float64
+	var providerAPIBurst int
+	var maxConcurrentNotifications int
+	var controllerOptions controller.ControllerOptions
+	var rateLimiterDelays string
+	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to, e.g. :8443. "+
+		"Defaults to 0, which disables the metrics server.")
+	flag.BoolVar(&secureMetrics, "metrics-secure", false,
+		"Serve the metrics endpoint over HTTPS, with a self-signed certificate.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "",
+		"The address the net/http/pprof endpoints bind to for live profiling, e.g. localhost:6060. Disabled if empty.")
+	flag.StringVar(&statusAddr, "status-bind-address", "",
+		"The address the /providers endpoint, with the sync state of every Provider as JSON, binds to, e.g. :8082. "+
+			"Disabled if empty.")
+	flag.StringVar(&debugAddr, "debug-bind-address", "",
+		"The address the /debug/state endpoint, which dumps the cached public IPs and clients and the backoff state of the "+
+			"Providers without any credentials, binds to, e.g. localhost:6061. Disabled if empty.")
+	flag.StringVar(&credentialCheck, "credential-check", string(controller.CredentialCheckOff),
+		"Verify the credentials of Providers and Notifiers with the DNS provider or webhook when they are created or updated, "+
+			"if the webhooks are enabled: off, warn to return a warning or reject to reject the credentials that are rejected.")
+	flag.Float64Var(&credentialCheckQPS, "credential-check-qps", 1,
+		"The number of credentials verified per second by --credential-check, with bursts of 5, apart from provider-api-qps "+
+			"so admissions do not delay the syncs. Set to 0 to disable it.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "eaa58520.stefangenov.site",
+		"The name of the lease used for leader election. Instances that reconcile different resources, e.g. with a "+
+			"different --selector, need different ones.")
+	flag.StringVar(&clusterResourceNamespace, "cluster-resource-namespace", defaultClusterResourceNamespace(),
+		"The namespace the secrets and config maps of cluster-scoped resources are read from. "+
+			"Defaults to the namespace of the controller.")
+	flag.StringVar(&namespaces, "namespaces", os.Getenv("WATCH_NAMESPACE"),
+		"Comma separated list of the namespaces to watch, e.g. for an install without cluster-wide RBAC. "+
+			"ClusterProviders and ClusterNotifiers are not reconciled then. "+
+			"Defaults to the WATCH_NAMESPACE env variable, or all namespaces if it is empty.")
+	flag.StringVar(&selector, "selector", "",
+		"Label selector of the Providers, Notifiers, Zones, DNSRecords (and their cluster-scoped counterparts), Ingresses and Services to reconcile, "+
+			"e.g. team=network, so several instances of the controller can split them up. Defaults to all of them.")
+	flag.StringVar(&ingressClasses, "ingress-classes", "",
+		"Comma separated list of the ingress classes whose Ingresses are reconciled, e.g. the one of the internet-facing "+
+			"ingress controller, so the records of internal ones are not published. Defaults to all of them.")
+	flag.BoolVar(&allowCrossNamespaceNotifierRefs, "allow-cross-namespace-notifier-refs", false,
+		"Allow the notifierRefs of Providers to point to Notifiers in other namespaces.")
+	flag.BoolVar(&enableServiceRecords, "enable-service-records", false,
+		"Keep DNSRecords for the annotated Services of type LoadBalancer, pointed at the address of their load balancer.")
+	flag.BoolVar(&enableDNSEndpoints, "enable-dns-endpoints", false,
+		"Keep DNSRecords for the endpoints of the annotated DNSEndpoints of external-dns. Requires the DNSEndpoint CRD.")
+	flag.BoolVar(&enableIstio, "enable-istio", false,
+		"Keep DNSRecords for the hosts of the annotated Istio Gateways and VirtualServices. Requires the Istio CRDs.")
+	flag.BoolVar(&enableTracing, "enable-tracing", false,
+		"Export the spans of the reconciliations over OTLP/gRPC, configured with the standard OTEL_EXPORTER_OTLP_* env variables.")
+	flag.StringVar(&controllerLogLevels, "controller-log-levels", "",
+		"Comma separated list of the log verbosity of single controllers, e.g. provider=2,notifier=1, overriding "+
+			"--zap-log-level for them. Verbosity 1 logs the HTTP calls, with their secrets redacted.")
+	flag.DurationVar(&errorRetryInterval, "error-retry-interval", 10*time.Second,
+		"How long to wait before retrying a failed Provider that does not set errorRetryInterval. "+
+			"Doubles with every consecutive failure, up to the retryInterval of the Provider. "+
+			"Set to 0 to use the default rate limiter of the controller instead.")
+	flag.DurationVar(&driftCheckInterval, "drift-check-interval", 0,
+		"How often the public IP of a Provider that does not set driftCheckInterval is compared with the one set at the "+
+			"provider, without calling the API of the provider. Set to 0 to verify the records every retryInterval instead.")
+	flag.DurationVar(&recordsVerifyInterval, "records-verify-interval", 0,
+		"How long the records listed at the DNS provider by a successful sync are reused by the syncs of the Provider whose "+
+			"public IP did not change, instead of listing them again. Set to 0 to list the records at every sync.")
+	flag.DurationVar(&syncTimeout, "sync-timeout", 2*time.Minute,
+		"How long detecting the public IP and syncing the records of a Provider may take before it is canceled and retried. "+
+			"Set to 0 to disable the timeout.")
+	flag.IntVar(&ipChangeHistoryLimit, "ip-change-history-limit", 10,
+		"How many IPChanges are kept for every Provider as an audit trail of the changes of its public IP, the oldest ones "+
+			"are deleted first. Set to 0 to record no IPChanges.")
+	flag.DurationVar(&ipChangeTTL, "ip-change-ttl", 0,
+		"How long IPChanges are kept, e.g. 720h. Set to 0 to keep them until the ip-change-history-limit is reached.")
+	flag.DurationVar(&heartbeatInterval, "heartbeat-interval", 5*time.Minute,
+		"How often at most the lastHeartbeatTime in the status of the Providers is updated when they are reconciled, "+
+			"so external tooling can tell an idle controller from a dead one. Set to 0 to disable it.")
+	flag.Float64Var(&requeueJitter, "requeue-jitter", 0.1,
+		"The fraction of the retryInterval of a Provider that is randomly added to it, so Providers created at the same time "+
+			"do not sync at the same time. Set to 0 to requeue after the exact interval.")
+	flag.DurationVar(&unhealthyProvidersThreshold, "unhealthy-providers-threshold", 0,
+		"Report the controller as not ready once all the Providers have been failing for longer than this, "+
+			"e.g. because its egress is blocked. Set to 0 to disable the check.")
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Hour,
+		"How often all the resources are reconciled again even if nothing changed, e.g. to recover from a lost requeue. "+
+			"Status updates of the controller itself never trigger a reconciliation.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 2*time.Minute,
+		"How long the syncs in progress are given to finish when the controller is stopped or steps down as the leader. "+
+			"Should be at least the sync-timeout and shorter than the terminationGracePeriodSeconds of the Pod.")
+	flag.DurationVar(&ipCacheTTL, "ip-cache-ttl", 30*time.Second,
+		"How long a detected public IP is reused by all the Providers (with the same customIPProvider) instead of "+
+			"each of them fetching it again. Set to 0 to fetch it on every reconciliation.")
+	flag.StringVar(&httpProxy, "http-proxy", "",
+		"The proxy that the public IP lookups, the calls to the DNS providers and the webhook notifiers go through, e.g. "+
+			"http://proxy.example.com:3128. Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.")
+	flag.Float64Var(&providerAPIQPS, "provider-api-qps", 4,
+		"The overall number of calls per second to the API of every DNS provider, e.g. of all the Cloudflare Providers "+
+			"together, so Providers sharing an account do not trip its rate limits. Set to 0 to disable it.")
+	flag.IntVar(&providerAPIBurst, "provider-api-burst", 20,
+		"The number of calls to the API of every DNS provider that are made at once, above provider-api-qps.")
+	flag.IntVar(&controllerOptions.MaxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The maximum number of resources of each kind, e.g. Providers or Ingresses, that are reconciled at once.")
+	flag.IntVar(&maxConcurrentNotifications, "max-concurrent-notifications", 5,
+		"The maximum number of notifications about different Providers that a Notifier delivers at once.")
+	flag.DurationVar(&controllerOptions.RateLimiterBaseDelay, "rate-limiter-base-delay", 5*time.Millisecond,
+		"The delay before a failed request is retried by the rate limiter, doubling with every failure.")
+	flag.DurationVar(&controllerOptions.RateLimiterMaxDelay, "rate-limiter-max-delay", 1000*time.Second,
+		"The maximum delay before a failed request is retried by the rate limiter.")
+	flag.StringVar(&rateLimiterDelays, "controller-rate-limiter-delays", "",
+		"Comma separated list of the rate limiter delays of single controllers, as <controller>=<baseDelay>[:<maxDelay>], e.g. "+
+			"provider=1s:5m,notifier=10ms, overriding --rate-limiter-base-delay and --rate-limiter-max-delay for them. "+
+			"The controllers are "+strings.Join(controller.ControllerNames, ", ")+".")
+	flag.Float64Var(&controllerOptions.RateLimiterQPS, "rate-limiter-qps", 10,
+		"The overall number of requests per second that the rate limiter lets through, per controller.")
+	flag.IntVar(&controllerOptions.RateLimiterBurst, "rate-limiter-burst", 100,
+		"The number of requests that the rate limiter lets through at once, above rate-limiter-qps.")
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	controllerLevels, err := logging.ParseLevels(controllerLogLevels)
+	if err != nil {
+		ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+		setupLog.Error(err, "invalid controller log levels", "levels", controllerLogLevels)
+		os.Exit(1)
+	}
 
-	metricsServerOptions := metricsserver.Options{BindAddress: "0"}
+	ctrl.SetLogger(logging.New(opts, controllerLevels))
+
+	if controllerOptions.RateLimiterDelays, err = controller.ParseRateLimiterDelays(rateLimiterDelays); err != nil {
+		setupLog.Error(err, "invalid controller rate limiter delays", "delays", rateLimiterDelays)
+		os.Exit(1)
+	}
+
+	if err = controllerOptions.Validate(); err != nil {
+		setupLog.Error(err, "invalid rate limiter delays")
+		os.Exit(1)
+	}
+
+	if httpProxy != "" {
+		if err := network.SetProxy(httpProxy); err != nil {
+			setupLog.Error(err, "invalid http proxy")
+			os.Exit(1)
+		}
+	}
+
+	clients.SetRateLimit(providerAPIQPS, providerAPIBurst)
+
+	metricsServerOptions := metricsserver.Options{BindAddress: metricsAddr, SecureServing: secureMetrics}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		setupLog.Error(err, "invalid selector", "selector", selector)
+		os.Exit(1)
+	}
+
+	watchNamespaces := splitList(namespaces)
+	namespaceScoped := len(watchNamespaces) > 0
+
+	cacheOptions := cache.Options{ByObject: selectByObject(labelSelector), SyncPeriod: &syncPeriod}
+	if namespaceScoped {
+		setupLog.Info("watching only some namespaces, cluster-scoped resources are not reconciled", "namespaces", watchNamespaces)
+
+		cacheOptions.DefaultNamespaces = map[string]cache.Config{}
+		for _, namespace := range watchNamespaces {
+			cacheOptions.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+
+	// Secrets and ConfigMaps are only watched as metadata to requeue the resources reading them, and read directly from
+	// the API server, so the data of every Secret in the cluster is not cached in memory
+	clientOptions := client.Options{
+		Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}}},
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOptions,
+		Client:                 clientOptions,
+		NewClient:              controller.NewClient,
 		Metrics:                metricsServerOptions,
 		HealthProbeBindAddress: probeAddr,
+		PprofBindAddress:       pprofAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "eaa58520.stefangenov.site",
+		LeaderElectionID:       leaderElectionID,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -86,31 +306,259 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		LeaderElectionReleaseOnCancel: true,
+		GracefulShutdownTimeout:       &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing := func(context.Context) error { return nil }
+	if enableTracing {
+		if shutdownTracing, err = tracing.Setup(ctx, "go-ddns-controller"); err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+	}
+
+	if err = controller.SetupIndexes(ctx, mgr.GetFieldIndexer(), namespaceScoped); err != nil {
+		setupLog.Error(err, "unable to set up field indexes")
+		os.Exit(1)
+	}
+
+	publicIpCache := network.NewIPCache(network.GetPublicIp, ipCacheTTL)
+	publicIpv6Cache := network.NewIPCache(network.GetPublicIpv6, ipCacheTTL)
+
 	if err = (&controller.ProviderReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		IPProvider:    network.GetPublicIp,
-		ClientFactory: clients.ClientFactory,
+		Client:                          mgr.GetClient(),
+		Scheme:                          mgr.GetScheme(),
+		IPProvider:                      publicIpCache.Get,
+		IPv6Provider:                    publicIpv6Cache.Get,
+		Resolver:                        network.Resolve,
+		ClientFactory:                   clients.ClientFactory,
+		ErrorRetryInterval:              errorRetryInterval,
+		DriftCheckInterval:              driftCheckInterval,
+		RecordsVerifyInterval:           recordsVerifyInterval,
+		SyncTimeout:                     syncTimeout,
+		IPChangeHistoryLimit:            ipChangeHistoryLimit,
+		IPChangeTTL:                     ipChangeTTL,
+		HeartbeatInterval:               heartbeatInterval,
+		RequeueJitter:                   requeueJitter,
+		ControllerOptions:               controllerOptions.For("provider"),
+		Recorder:                        mgr.GetEventRecorderFor("provider-controller"),
+		NamespaceScoped:                 namespaceScoped,
+		AllowCrossNamespaceNotifierRefs: allowCrossNamespaceNotifierRefs,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Provider")
 		os.Exit(1)
 	}
-	if err = (&controller.NotifierReconciler{
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		NotifierFactory: notifiers.NotifierFactory,
-	}).SetupWithManager(mgr); err != nil {
+	// Cluster-scoped resources cannot be watched without cluster-wide RBAC
+	if !namespaceScoped {
+		if err = (&controller.ClusterProviderReconciler{
+			ProviderReconciler: controller.ProviderReconciler{
+				Client:                          mgr.GetClient(),
+				Scheme:                          mgr.GetScheme(),
+				IPProvider:                      publicIpCache.Get,
+				IPv6Provider:                    publicIpv6Cache.Get,
+				Resolver:                        network.Resolve,
+				ClientFactory:                   clients.ClientFactory,
+				ErrorRetryInterval:              errorRetryInterval,
+				DriftCheckInterval:              driftCheckInterval,
+				RecordsVerifyInterval:           recordsVerifyInterval,
+				SyncTimeout:                     syncTimeout,
+				IPChangeHistoryLimit:            ipChangeHistoryLimit,
+				IPChangeTTL:                     ipChangeTTL,
+				HeartbeatInterval:               heartbeatInterval,
+				RequeueJitter:                   requeueJitter,
+				ControllerOptions:               controllerOptions.For("clusterprovider"),
+				Recorder:                        mgr.GetEventRecorderFor("clusterprovider-controller"),
+				AllowCrossNamespaceNotifierRefs: allowCrossNamespaceNotifierRefs,
+			},
+			ClusterResourceNamespace: clusterResourceNamespace,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterProvider")
+			os.Exit(1)
+		}
+	}
+	// The notifier reconcilers are kept for the debug state, which dumps their cached clients
+	notifierReconcilers := map[string]*controller.NotifierReconciler{}
+
+	notifierReconciler := &controller.NotifierReconciler{
+		Client:                     mgr.GetClient(),
+		Scheme:                     mgr.GetScheme(),
+		NotifierFactory:            notifiers.NotifierFactory,
+		ClusterResourceNamespace:   clusterResourceNamespace,
+		AllowCrossNamespaceRefs:    allowCrossNamespaceNotifierRefs,
+		ControllerOptions:          controllerOptions.For("notifier"),
+		NamespaceScoped:            namespaceScoped,
+		MaxConcurrentNotifications: maxConcurrentNotifications,
+		Recorder:                   mgr.GetEventRecorderFor("notifier-controller"),
+	}
+	notifierReconcilers["Notifier"] = notifierReconciler
+	if err = notifierReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Notifier")
 		os.Exit(1)
 	}
+	if !namespaceScoped {
+		clusterNotifierReconciler := &controller.ClusterNotifierReconciler{
+			NotifierReconciler: controller.NotifierReconciler{
+				Client:                     mgr.GetClient(),
+				Scheme:                     mgr.GetScheme(),
+				NotifierFactory:            notifiers.NotifierFactory,
+				ClusterResourceNamespace:   clusterResourceNamespace,
+				ControllerOptions:          controllerOptions.For("clusternotifier"),
+				MaxConcurrentNotifications: maxConcurrentNotifications,
+				Recorder:                   mgr.GetEventRecorderFor("clusternotifier-controller"),
+			},
+		}
+		notifierReconcilers["ClusterNotifier"] = &clusterNotifierReconciler.NotifierReconciler
+		if err = clusterNotifierReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterNotifier")
+			os.Exit(1)
+		}
+	}
+	if err = (&controller.DNSRecordReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ClientFactory:            clients.ClientFactory,
+		ClusterResourceNamespace: clusterResourceNamespace,
+		NamespaceScoped:          namespaceScoped,
+		ControllerOptions:        controllerOptions.For("dnsrecord"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DNSRecord")
+		os.Exit(1)
+	}
+	if err = (&controller.ZoneReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ClientFactory:            clients.ClientFactory,
+		ClusterResourceNamespace: clusterResourceNamespace,
+		NamespaceScoped:          namespaceScoped,
+		ControllerOptions:        controllerOptions.For("zone"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Zone")
+		os.Exit(1)
+	}
+	if err = (&controller.IngressReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Recorder:          mgr.GetEventRecorderFor("ingress-controller"),
+		NamespaceScoped:   namespaceScoped,
+		IngressClasses:    splitList(ingressClasses),
+		ControllerOptions: controllerOptions.For("ingress"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ingress")
+		os.Exit(1)
+	}
+	if enableServiceRecords {
+		if err = (&controller.ServiceReconciler{
+			Client:            mgr.GetClient(),
+			Scheme:            mgr.GetScheme(),
+			Recorder:          mgr.GetEventRecorderFor("service-controller"),
+			ControllerOptions: controllerOptions.For("service"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Service")
+			os.Exit(1)
+		}
+	}
+	if enableDNSEndpoints {
+		if err = (&controller.DNSEndpointReconciler{
+			Client:            mgr.GetClient(),
+			Scheme:            mgr.GetScheme(),
+			Recorder:          mgr.GetEventRecorderFor("dnsendpoint-controller"),
+			ControllerOptions: controllerOptions.For("dnsendpoint"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DNSEndpoint")
+			os.Exit(1)
+		}
+	}
+	// IngressRoutes are reconciled if the CRD of Traefik is installed, e.g. on k3s, which is checked only on start
+	if _, err := mgr.GetRESTMapper().RESTMapping(controller.IngressRouteGVK.GroupKind(), controller.IngressRouteGVK.Version); err != nil {
+		setupLog.Info("the IngressRoute CRD of Traefik is not installed, IngressRoutes are not reconciled", "reason", err.Error())
+	} else if err = (&controller.IngressRouteReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Recorder:          mgr.GetEventRecorderFor("ingressroute-controller"),
+		NamespaceScoped:   namespaceScoped,
+		ControllerOptions: controllerOptions.For("ingressroute"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IngressRoute")
+		os.Exit(1)
+	}
+	if enableIstio {
+		for _, kind := range []schema.GroupVersionKind{controller.IstioGatewayGVK, controller.IstioVirtualServiceGVK} {
+			if err = (&controller.IstioReconciler{
+				Client:            mgr.GetClient(),
+				Scheme:            mgr.GetScheme(),
+				Recorder:          mgr.GetEventRecorderFor("istio-controller"),
+				NamespaceScoped:   namespaceScoped,
+				Kind:              kind,
+				ControllerOptions: controllerOptions.For("istio-" + strings.ToLower(kind.Kind)),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", kind.Kind)
+				os.Exit(1)
+			}
+		}
+	}
+	// The conversion, defaulting and validating webhooks need a serving certificate, so they are only started when explicitly enabled
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		check, err := controller.ParseCredentialCheck(credentialCheck)
+		if err != nil {
+			setupLog.Error(err, "unable to parse the credential check")
+			os.Exit(1)
+		}
+
+		var credentialCheckLimiter *rate.Limiter
+		if credentialCheckQPS > 0 {
+			credentialCheckLimiter = rate.NewLimiter(rate.Limit(credentialCheckQPS), 5)
+		}
+
+		// The validator is registered even if the check is off, as the ValidatingWebhookConfiguration is always installed
+		credentialsValidator := &controller.CredentialsValidator{
+			Reader:         mgr.GetClient(),
+			Check:          check,
+			Timeout:        10 * time.Second,
+			Limiter:        credentialCheckLimiter,
+			VerifyProvider: clients.VerifyCredentials,
+			VerifyNotifier: notifiers.VerifyNotifier,
+		}
+
+		providerValidator := controller.Validators{
+			credentialsValidator,
+			&controller.ConfigWarningsValidator{},
+		}
+
+		if err = (&ddnsv1alpha1.Provider{}).SetupWebhookWithManager(mgr, providerValidator); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Provider")
+			os.Exit(1)
+		}
+		if err = (&ddnsv1alpha1.Notifier{}).SetupWebhookWithManager(mgr, credentialsValidator); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Notifier")
+			os.Exit(1)
+		}
+		if err = (&controller.DeletionProtectionValidator{Reader: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DeletionProtection")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if enableLeaderElection {
+		identity, _ := os.Hostname()
+		if err = mgr.Add(&controller.LeaderElectionNotifier{
+			Recorder: mgr.GetEventRecorderFor("leader-election"),
+			Lease: &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: leaderElectionID, Namespace: defaultClusterResourceNamespace()},
+			},
+			Identity: identity,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up leader election notifier")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -119,10 +567,94 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if unhealthyProvidersThreshold > 0 {
+		if err := mgr.AddReadyzCheck("providers", controller.ProvidersChecker(mgr.GetClient(), unhealthyProvidersThreshold, namespaceScoped)); err != nil {
+			setupLog.Error(err, "unable to set up providers ready check")
+			os.Exit(1)
+		}
+	}
+	if statusAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/providers", controller.ProvidersStatusHandler(mgr.GetClient(), namespaceScoped))
+
+		// Served by every replica, not only the leader, like the probes
+		if err := mgr.Add(&manager.Server{
+			Name:   "status",
+			Server: &http.Server{Addr: statusAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+		}); err != nil {
+			setupLog.Error(err, "unable to set up the status server")
+			os.Exit(1)
+		}
+	}
+	if debugAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/state", &controller.DebugStateHandler{
+			Reader:             mgr.GetClient(),
+			NamespaceScoped:    namespaceScoped,
+			ErrorRetryInterval: errorRetryInterval,
+			IPCaches:           map[string]*network.IPCache{clients.RecordTypeA: publicIpCache, clients.RecordTypeAAAA: publicIpv6Cache},
+			Notifiers:          notifierReconcilers,
+		})
 
-	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		if err := mgr.Add(&manager.Server{
+			Name:   "debug",
+			Server: &http.Server{Addr: debugAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+		}); err != nil {
+			setupLog.Error(err, "unable to set up the debug server")
+			os.Exit(1)
+		}
+	}
+
+	setupLog.Info("starting manager", "version", version.Controller())
+	err = mgr.Start(ctx)
+
+	// The spans that were not exported yet are flushed before exiting
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "unable to flush the spans")
+	}
+
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// defaultClusterResourceNamespace returns the namespace of the controller, as set in the POD_NAMESPACE env variable
+func defaultClusterResourceNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+
+	return "go-ddns-controller-system"
+}
+
+// splitList splits a comma separated list, e.g. of namespaces, ignoring empty entries
+func splitList(list string) []string {
+	split := []string{}
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			split = append(split, entry)
+		}
+	}
+
+	return split
+}
+
+// selectByObject only caches the resources of the controller that match the selector. Secrets and ConfigMaps are not cached
+func selectByObject(selector labels.Selector) map[client.Object]cache.ByObject {
+	if selector.Empty() {
+		return nil
+	}
+
+	byObject := map[client.Object]cache.ByObject{}
+	for _, obj := range []client.Object{
+		&ddnsv1alpha1.Provider{}, &ddnsv1alpha1.ClusterProvider{},
+		&ddnsv1alpha1.Notifier{}, &ddnsv1alpha1.ClusterNotifier{},
+		&ddnsv1alpha1.Zone{}, &ddnsv1alpha1.DNSRecord{},
+		&networkingv1.Ingress{}, &corev1.Service{},
+	} {
+		byObject[obj] = cache.ByObject{Label: selector}
+	}
+
+	return byObject
+}
@@ -0,0 +1,55 @@
+#!/usr/bin/env bash
+# Turns a CRD with a conversion webhook into a template of the Helm chart.
+# The conversion webhook and the CA injection of cert-manager are only set up if webhook.enabled is set, and the versions
+# that need the conversion are only served then, as the default None conversion would drop the fields they renamed.
+# The CRDs in the crds directory of a chart are not templated, so these are installed from the templates directory instead.
+#
+# Usage: hack/chart-crd.sh <crd> <version>... > charts/go-ddns-controller/templates/crds/<crd>
+set -euo pipefail
+
+crd=$1
+shift
+
+awk -v versions="$*" '
+BEGIN {
+	split(versions, list, " ")
+	for (i in list) {
+		converted[list[i]] = 1
+	}
+}
+
+# The version entries start with their additionalPrinterColumns, so the name comes before served
+/^    name: / {
+	version = $2
+}
+
+/^    served: true$/ && (version in converted) {
+	print "    served: {{ .Values.webhook.enabled }}"
+	next
+}
+
+{ print }
+
+/^    controller-gen.kubebuilder.io\/version: / {
+	# The CRDs are kept when the release is uninstalled, like the ones in the crds directory, so the resources are not deleted
+	print "    helm.sh/resource-policy: keep"
+	print "    {{- if .Values.webhook.enabled }}"
+	print "    cert-manager.io/inject-ca-from: {{ .Release.Namespace }}/{{ include \"go-ddns-controller.fullname\" . }}-serving-cert"
+	print "    {{- end }}"
+}
+
+/^spec:$/ {
+	print "  {{- if .Values.webhook.enabled }}"
+	print "  conversion:"
+	print "    strategy: Webhook"
+	print "    webhook:"
+	print "      clientConfig:"
+	print "        service:"
+	print "          namespace: {{ .Release.Namespace }}"
+	print "          name: {{ include \"go-ddns-controller.fullname\" . }}-webhook-service"
+	print "          path: /convert"
+	print "      conversionReviewVersions:"
+	print "      - v1"
+	print "  {{- end }}"
+}
+' "$crd"
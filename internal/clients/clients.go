@@ -1,44 +1,171 @@
 package clients
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Michaelpalacce/go-ddns-controller/internal/version"
 )
 
 var Cloudflare = "Cloudflare"
 
+// libraries are the Go modules the clients talk to their DNS providers with, by the name of the client
+var libraries = map[string]string{
+	Cloudflare: "github.com/cloudflare/cloudflare-go",
+}
+
+// ClientVersion returns the library the client of the given name is built with and its version, e.g.
+// github.com/cloudflare/cloudflare-go@v0.101.0, or an empty string for an unknown client
+func ClientVersion(name string) string {
+	library, ok := libraries[name]
+	if !ok {
+		return ""
+	}
+
+	return library + "@" + version.Module(library)
+}
+
+// Record types that clients know how to manage
+const (
+	RecordTypeA    = "A"
+	RecordTypeAAAA = "AAAA"
+)
+
 // Client is a general interface implemented by all clients
+// recordType is one of RecordTypeA or RecordTypeAAAA and selects which records are read or updated.
 type Client interface {
-	GetIp() ([]string, error)
-	SetIp(ip string) error
+	GetIp(recordType string) ([]string, error)
+	SetIp(ip string, recordType string) error
+	// GetRecords returns every configured record of the given recordType with its current value at the provider.
+	// Records that do not exist at the provider are returned with an empty Content.
+	GetRecords(recordType string) ([]DNSRecord, error)
+	// DeleteRecords deletes the records of the given recordType that are owned by the controller.
+	// Records are owned once the controller has set their IP.
+	DeleteRecords(recordType string) error
 }
 
-// ClientFactory will return an authenticated, fully loaded client
-func ClientFactory(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (Client, error) {
-	var client Client
-	switch name {
-	case Cloudflare:
-		var cloudflareConfig CloudflareConfig
+// DNSRecord is a single record that is managed on its own, independently of the configured zones
+type DNSRecord struct {
+	Zone    string
+	Name    string
+	Type    string
+	Content string
+	TTL     int
+	Proxied bool
+	// ProxiedDrift is true if the proxied setting of the record at the provider differs from Proxied.
+	// It is only reported for records that enforce their proxied setting
+	ProxiedDrift bool
+}
+
+// RecordConfig is a provider-neutral record that should be kept in sync with the public IP.
+// It is read from the `records` key of the ConfigMap and every client maps it to the format of its backend.
+type RecordConfig struct {
+	Zone string `json:"zone"`
+	Name string `json:"name"`
+	// Type restricts the record to one of RecordTypeA or RecordTypeAAAA. If empty, the record is kept in sync for every IP family.
+	Type    string `json:"type,omitempty"`
+	TTL     int    `json:"ttl,omitempty"`
+	Proxied bool   `json:"proxied,omitempty"`
+	// EnforceProxied reports the record as out of sync when its proxied setting at the provider differs from Proxied
+	EnforceProxied bool `json:"enforceProxied,omitempty"`
+}
 
-		if configMap.Data["config"] == "" {
-			return nil, fmt.Errorf("`config` not found in configMap")
+// RecordClient is implemented by clients that can manage single records, e.g. the ones declared as DNSRecord resources
+type RecordClient interface {
+	// GetRecord returns the record at the provider, or nil if it does not exist
+	GetRecord(record DNSRecord) (*DNSRecord, error)
+	// UpsertRecord creates the record or updates it if it already exists
+	UpsertRecord(record DNSRecord) error
+	// DeleteRecord deletes the record, if it is owned by the controller
+	DeleteRecord(record DNSRecord) error
+}
+
+// RecordsUpdater is implemented by clients that can update some of the configured records only, e.g. the ones that drifted,
+// instead of all of them like SetIp does
+type RecordsUpdater interface {
+	// UpdateRecords sets the IP of the given records, as returned by GetRecords. Records that are not configured are ignored.
+	UpdateRecords(ip string, records []DNSRecord) error
+}
+
+// RecordsAdopter is implemented by clients that can take over existing records without changing them
+type RecordsAdopter interface {
+	// AdoptRecords marks the given records, as returned by GetRecords, as owned by the controller, leaving their values as they are.
+	// Records that do not exist are ignored.
+	AdoptRecords(records []DNSRecord) error
+}
+
+// ZoneLister is implemented by clients whose records are grouped in zones
+type ZoneLister interface {
+	// Zones returns the names of the configured zones
+	Zones() []string
+}
+
+// ZoneError is the error of a single zone. Clients keep going with their other zones when one of them fails, e.g. because
+// the token lacks permission for it, and return the ZoneErrors of all the failed zones joined
+type ZoneError struct {
+	Zone string
+	Err  error
+}
+
+func (e *ZoneError) Error() string {
+	return fmt.Sprintf("zone %s: %s", e.Zone, e.Err)
+}
+
+func (e *ZoneError) Unwrap() error {
+	return e.Err
+}
+
+// ZoneErrors returns the errors of the failed zones by zone name, if err only consists of ZoneErrors.
+// It returns false if any part of err is not limited to a zone, in which case the whole call failed
+func ZoneErrors(err error) (map[string]error, bool) {
+	zoneErrs := map[string]error{}
+
+	return zoneErrs, collectZoneErrors(err, zoneErrs)
+}
+
+// collectZoneErrors adds the ZoneErrors in the tree of joined errors to zoneErrs, returning false on any other error
+func collectZoneErrors(err error, zoneErrs map[string]error) bool {
+	switch e := err.(type) {
+	case nil:
+		return true
+	case *ZoneError:
+		zoneErrs[e.Zone] = e.Err
+		return true
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if !collectZoneErrors(err, zoneErrs) {
+				return false
+			}
 		}
 
-		configMap := configMap.Data["config"]
+		return true
+	default:
+		return false
+	}
+}
+
+// OwnershipMarker is attached to the records the controller manages, so it knows which records it may delete.
+const OwnershipMarker = "managed by go-ddns-controller"
 
-		err := json.Unmarshal([]byte(configMap), &cloudflareConfig)
+// ClientFactory will return an authenticated, fully loaded client
+// The calls of the client to the DNS provider are canceled with the given context
+func ClientFactory(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (Client, error) {
+	var client Client
+	switch name {
+	case Cloudflare:
+		config, err := cloudflareConfig(configMap)
 		if err != nil {
-			return nil, fmt.Errorf("could not unmarshal the config: %s", err)
+			return nil, err
 		}
 
 		if secret.Data["apiToken"] == nil {
 			return nil, fmt.Errorf("`apiToken` not found in secret")
 		}
 
-		client, err = NewCloudflareClient(cloudflareConfig, string(secret.Data["apiToken"]), log)
+		client, err = NewCloudflareClient(ctx, config, string(secret.Data["apiToken"]), log)
 		if err != nil {
 			return nil, fmt.Errorf("could not create a Cloudflare client: %s", err)
 		}
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/cloudflare/cloudflare-go"
@@ -16,6 +17,18 @@ type Logger interface {
 type Record struct {
 	Name    string `json:"name"`
 	Proxied bool   `json:"proxied"`
+	// Type restricts the record to a single record type. If empty, the record is managed for every record type.
+	Type string `json:"type,omitempty"`
+	// TTL is set on the record when it is updated. If 0, the TTL at Cloudflare is left untouched.
+	TTL int `json:"ttl,omitempty"`
+	// EnforceProxied reports the record as drifted when its proxied setting at Cloudflare differs from Proxied, e.g.
+	// because it was toggled in the dashboard. Otherwise the setting is only applied when the IP is updated.
+	EnforceProxied bool `json:"enforceProxied,omitempty"`
+}
+
+// hasType returns true if the record should be managed for the given recordType
+func (r Record) hasType(recordType string) bool {
+	return r.Type == "" || r.Type == recordType
 }
 
 // Zone (s) are how Cloudflare separates different DNS endpoints
@@ -31,6 +44,31 @@ type CloudflareConfig struct {
 	} `json:"cloudflare"`
 }
 
+// NewCloudflareConfig maps the provider-neutral records to a CloudflareConfig, grouping them by zone
+func NewCloudflareConfig(records []RecordConfig) CloudflareConfig {
+	config := CloudflareConfig{}
+	zoneIndex := map[string]int{}
+
+	for _, record := range records {
+		index, ok := zoneIndex[record.Zone]
+		if !ok {
+			index = len(config.Cloudflare.Zones)
+			zoneIndex[record.Zone] = index
+			config.Cloudflare.Zones = append(config.Cloudflare.Zones, Zone{Name: record.Zone})
+		}
+
+		config.Cloudflare.Zones[index].Records = append(config.Cloudflare.Zones[index].Records, Record{
+			Name:           record.Name,
+			Proxied:        record.Proxied,
+			Type:           record.Type,
+			TTL:            record.TTL,
+			EnforceProxied: record.EnforceProxied,
+		})
+	}
+
+	return config
+}
+
 type CloudflareSecret struct {
 	APIToken string `json:"apiToken"`
 }
@@ -39,6 +77,8 @@ type cloudflareApi interface {
 	ZoneIDByName(zoneName string) (string, error)
 	ListDNSRecords(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error)
 	UpdateDNSRecord(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error)
+	DeleteDNSRecord(ctx context.Context, zoneID *cloudflare.ResourceContainer, recordID string) error
+	CreateDNSRecord(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.CreateDNSRecordParams) (cloudflare.DNSRecord, error)
 }
 
 // CloudflareClient is the CloudflareClient client that will support Authentication and setting records
@@ -46,67 +86,278 @@ type CloudflareClient struct {
 	API    cloudflareApi
 	Config CloudflareConfig
 	Logger Logger
+	// Ctx cancels the calls to the Cloudflare API. Defaults to context.Background()
+	Ctx context.Context
 }
 
-// NewCloudflareClient creates a new CloudflareClient client
+// NewCloudflareClient creates a new CloudflareClient client, whose calls to the Cloudflare API are canceled with the context
 // It will return an error if the authentication fails
-func NewCloudflareClient(config CloudflareConfig, apiToken string, logger Logger) (*CloudflareClient, error) {
-	api, err := cloudflare.NewWithAPIToken(apiToken)
+func NewCloudflareClient(ctx context.Context, config CloudflareConfig, apiToken string, logger Logger) (*CloudflareClient, error) {
+	api, err := cloudflare.NewWithAPIToken(apiToken, cloudflare.HTTPClient(rateLimitedClient(Cloudflare)))
 	if err != nil {
 		return nil, fmt.Errorf("could not authenticate to Cloudflare with the given token, error was: %s", err)
 	}
 
 	return &CloudflareClient{
 		Config: config,
-		API:    api,
+		API:    contextAPI{API: api, ctx: ctx},
 		Logger: logger,
+		Ctx:    ctx,
 	}, nil
 }
 
+// contextAPI is the Cloudflare API, with the zone lookup canceled with the context as well
+type contextAPI struct {
+	*cloudflare.API
+	ctx context.Context
+}
+
+// ZoneIDByName looks up the ID of the zone like cloudflare.API.ZoneIDByName does, which does not accept a context
+func (a contextAPI) ZoneIDByName(zoneName string) (string, error) {
+	res, err := a.ListZonesContext(a.ctx, cloudflare.WithZoneFilters(zoneName, "", ""))
+	if err != nil {
+		return "", fmt.Errorf("ListZonesContext command failed: %w", err)
+	}
+
+	switch len(res.Result) {
+	case 0:
+		return "", errors.New("zone could not be found")
+	case 1:
+		return res.Result[0].ID, nil
+	default:
+		return "", errors.New("ambiguous zone name; an account ID might help")
+	}
+}
+
+// context returns the context the calls to the Cloudflare API are canceled with
+func (c CloudflareClient) context() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+
+	return c.Ctx
+}
+
 // SetIp sets the IP for the given zones based on the configuration
-func (c CloudflareClient) SetIp(ip string) error {
+// Only records of the given recordType are updated. A failing zone does not stop the others from being updated
+func (c CloudflareClient) SetIp(ip string, recordType string) error {
+	var errs []error
+
+	for _, zone := range c.Config.Cloudflare.Zones {
+		c.Logger.Info("Setting IP for zone", "zone", zone.Name, "type", recordType)
+
+		if err := c.setIpForZone(ip, zone, recordType); err != nil {
+			errs = append(errs, &ZoneError{Zone: zone.Name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// UpdateRecords sets the IP of the given records only, leaving the other configured records untouched
+func (c CloudflareClient) UpdateRecords(ip string, records []DNSRecord) error {
+	var errs []error
+
 	for _, zone := range c.Config.Cloudflare.Zones {
-		c.Logger.Info("Setting IP for zone", "zone", zone.Name)
+		for _, recordType := range []string{RecordTypeA, RecordTypeAAAA} {
+			zoneRecords := []Record{}
+
+			for _, zr := range zone.Records {
+				for _, record := range records {
+					if record.Zone == zone.Name && record.Name == zr.Name && record.Type == recordType && zr.hasType(recordType) {
+						zoneRecords = append(zoneRecords, zr)
+						break
+					}
+				}
+			}
+
+			if len(zoneRecords) == 0 {
+				continue
+			}
+
+			c.Logger.Info("Setting IP for records of zone", "zone", zone.Name, "type", recordType, "records", len(zoneRecords))
+
+			if err := c.setIpForRecords(ip, zone.Name, zoneRecords, recordType); err != nil {
+				errs = append(errs, &ZoneError{Zone: zone.Name, Err: err})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// AdoptRecords attaches the OwnershipMarker comment to the given records, without changing their content or settings
+func (c CloudflareClient) AdoptRecords(records []DNSRecord) error {
+	var errs []error
+
+	for _, zone := range c.Config.Cloudflare.Zones {
+		if err := c.adoptRecordsOfZone(zone.Name, records); err != nil {
+			errs = append(errs, &ZoneError{Zone: zone.Name, Err: err})
+		}
+	}
 
-		if err := c.setIpForZone(ip, zone); err != nil {
-			return err
+	return errors.Join(errs...)
+}
+
+// adoptRecordsOfZone attaches the OwnershipMarker comment to the given records of a specific zone that are not owned yet
+func (c CloudflareClient) adoptRecordsOfZone(zoneName string, records []DNSRecord) error {
+	zoneRecords := []DNSRecord{}
+	for _, record := range records {
+		if record.Zone == zoneName && record.Content != "" {
+			zoneRecords = append(zoneRecords, record)
+		}
+	}
+
+	if len(zoneRecords) == 0 {
+		return nil
+	}
+
+	zoneID, err := c.API.ZoneIDByName(zoneName)
+	if err != nil {
+		return err
+	}
+
+	existing, _, err := c.API.ListDNSRecords(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range zoneRecords {
+		for _, r := range existing {
+			if r.Name != record.Name || r.Type != record.Type || r.Comment == OwnershipMarker {
+				continue
+			}
+
+			c.Logger.Info("Adopting record", "recordName", r.Name, "type", r.Type)
+
+			// Only the comment is sent, so the content, proxied setting and TTL of the record are left as they are
+			if _, err := c.API.UpdateDNSRecord(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.UpdateDNSRecordParams{
+				ID:      r.ID,
+				Comment: cloudflare.StringPtr(OwnershipMarker),
+			}); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-// GetIp returns the public IP from all the zones
-func (c CloudflareClient) GetIp() ([]string, error) {
+// DeleteRecords deletes the records of the given recordType from all the zones
+// Only records that carry the OwnershipMarker comment are deleted
+func (c CloudflareClient) DeleteRecords(recordType string) error {
+	var errs []error
+
+	for _, zone := range c.Config.Cloudflare.Zones {
+		c.Logger.Info("Deleting records for zone", "zone", zone.Name, "type", recordType)
+
+		if err := c.deleteRecordsFromZone(zone, recordType); err != nil {
+			errs = append(errs, &ZoneError{Zone: zone.Name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetIp returns the IPs of the records of the given recordType from all the zones
+// The IPs of the zones that could be read are returned even if others failed
+func (c CloudflareClient) GetIp(recordType string) ([]string, error) {
 	ips := make([]string, 0)
+	var errs []error
 
 	for _, zone := range c.Config.Cloudflare.Zones {
-		var err error
+		zoneIps, err := c.getIpsFromZone(zone, recordType)
+		if err != nil {
+			errs = append(errs, &ZoneError{Zone: zone.Name, Err: err})
+			continue
+		}
+
+		ips = append(ips, zoneIps...)
+	}
+
+	return ips, errors.Join(errs...)
+}
+
+// GetRecords returns the configured records of the given recordType from all the zones
+// The records of the zones that could be read are returned even if others failed
+func (c CloudflareClient) GetRecords(recordType string) ([]DNSRecord, error) {
+	records := make([]DNSRecord, 0)
+	var errs []error
 
-		if ips, err = c.getIpsFromZone(zone); err != nil {
-			return nil, err
+	for _, zone := range c.Config.Cloudflare.Zones {
+		zoneRecords, err := c.getRecordsFromZone(zone, recordType)
+		if err != nil {
+			errs = append(errs, &ZoneError{Zone: zone.Name, Err: err})
+			continue
 		}
+
+		records = append(records, zoneRecords...)
 	}
 
-	return ips, nil
+	return records, errors.Join(errs...)
+}
+
+// Zones returns the names of the configured zones
+func (c CloudflareClient) Zones() []string {
+	zones := make([]string, 0, len(c.Config.Cloudflare.Zones))
+	for _, zone := range c.Config.Cloudflare.Zones {
+		zones = append(zones, zone.Name)
+	}
+
+	return zones
+}
+
+// getRecordsFromZone returns the configured records of a specific zone with their content at Cloudflare
+func (c CloudflareClient) getRecordsFromZone(zone Zone, recordType string) ([]DNSRecord, error) {
+	zoneID, err := c.API.ZoneIDByName(zone.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, _, err := c.API.ListDNSRecords(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{Type: recordType})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]DNSRecord, 0, len(zone.Records))
+	for _, zr := range zone.Records {
+		if !zr.hasType(recordType) {
+			continue
+		}
+
+		record := DNSRecord{Zone: zone.Name, Name: zr.Name, Type: recordType, Proxied: zr.Proxied}
+
+		for _, r := range existing {
+			if r.Type == recordType && r.Name == zr.Name {
+				record.Content = r.Content
+				record.TTL = r.TTL
+				record.ProxiedDrift = zr.EnforceProxied && (r.Proxied != nil && *r.Proxied) != zr.Proxied
+				break
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
 }
 
 // getIpFromZone returns the public IPs for a records in a specific zone
-func (c CloudflareClient) getIpsFromZone(zone Zone) ([]string, error) {
+func (c CloudflareClient) getIpsFromZone(zone Zone, recordType string) ([]string, error) {
 	ips := make([]string, 0)
 	zoneID, err := c.API.ZoneIDByName(zone.Name)
 	if err != nil {
 		return ips, err
 	}
 
-	records, _, err := c.API.ListDNSRecords(context.Background(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{})
+	records, _, err := c.API.ListDNSRecords(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{Type: recordType})
 	if err != nil {
 		return ips, err
 	}
 
 	for _, r := range records {
 		for _, zr := range zone.Records {
-			if r.Type == "A" && r.Name == zr.Name {
+			if r.Type == recordType && r.Name == zr.Name && zr.hasType(recordType) {
 				ips = append(ips, r.Content)
 			}
 		}
@@ -115,38 +366,51 @@ func (c CloudflareClient) getIpsFromZone(zone Zone) ([]string, error) {
 }
 
 // setIpForZone sets the public ip for a specific zone
-func (c CloudflareClient) setIpForZone(ip string, zone Zone) error {
-	zoneID, err := c.API.ZoneIDByName(zone.Name)
-	if err != nil {
-		return err
-	}
-	c.Logger.Info("Found zone", "zoneId", zoneID, "zoneName", zone.Name)
-
+func (c CloudflareClient) setIpForZone(ip string, zone Zone, recordType string) error {
+	zoneRecords := []Record{}
 	for _, r := range zone.Records {
-		c.Logger.Info("Setting IP for record", "record", r)
-		if err := c.setIpForRecord(ip, zoneID, r); err != nil {
-			return err
+		if r.hasType(recordType) {
+			zoneRecords = append(zoneRecords, r)
 		}
 	}
 
-	return nil
+	return c.setIpForRecords(ip, zone.Name, zoneRecords, recordType)
 }
 
-// setIpForRecord will update the specific record
-func (c CloudflareClient) setIpForRecord(ip string, zoneID string, record Record) error {
-	records, _, err := c.API.ListDNSRecords(context.Background(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{})
+// setIpForRecords will update the given records of a zone, listing the existing records of the zone only once
+// Records that already have the IP, settings and ownership marker are not written again
+// Records of other types with the same name (e.g. the AAAA next to an A) are left untouched
+func (c CloudflareClient) setIpForRecords(ip string, zoneName string, zoneRecords []Record, recordType string) error {
+	zoneID, err := c.API.ZoneIDByName(zoneName)
 	if err != nil {
 		return err
 	}
+	c.Logger.Info("Found zone", "zoneId", zoneID, "zoneName", zoneName)
+
+	records, _, err := c.API.ListDNSRecords(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{Type: recordType})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range zoneRecords {
+		for _, r := range records {
+			if r.Name != record.Name || r.Type != recordType {
+				continue
+			}
+
+			if inSync(r, ip, record) {
+				c.Logger.Info("Record is in sync, skipping", "recordName", record.Name)
+				continue
+			}
 
-	for _, r := range records {
-		if r.Name == record.Name {
 			c.Logger.Info("Updating record", "recordName", record.Name)
 
-			_, err := c.API.UpdateDNSRecord(context.Background(), cloudflare.ZoneIdentifier(zoneID), cloudflare.UpdateDNSRecordParams{
+			_, err := c.API.UpdateDNSRecord(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.UpdateDNSRecordParams{
 				ID:      r.ID,
 				Content: ip,
 				Proxied: cloudflare.BoolPtr(record.Proxied),
+				TTL:     record.TTL,
+				Comment: cloudflare.StringPtr(OwnershipMarker),
 			})
 			if err != nil {
 				return err
@@ -156,3 +420,138 @@ func (c CloudflareClient) setIpForRecord(ip string, zoneID string, record Record
 
 	return nil
 }
+
+// inSync returns true if the record at Cloudflare already has the IP and the settings of the configured record and is owned
+func inSync(r cloudflare.DNSRecord, ip string, record Record) bool {
+	return r.Content == ip &&
+		r.Proxied != nil && *r.Proxied == record.Proxied &&
+		(record.TTL == 0 || r.TTL == record.TTL) &&
+		r.Comment == OwnershipMarker
+}
+
+// deleteRecordsFromZone deletes the owned records of a specific zone
+func (c CloudflareClient) deleteRecordsFromZone(zone Zone, recordType string) error {
+	zoneID, err := c.API.ZoneIDByName(zone.Name)
+	if err != nil {
+		return err
+	}
+
+	records, _, err := c.API.ListDNSRecords(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{Type: recordType})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		for _, zr := range zone.Records {
+			if r.Type != recordType || r.Name != zr.Name || !zr.hasType(recordType) {
+				continue
+			}
+
+			if r.Comment != OwnershipMarker {
+				c.Logger.Info("Record is not owned by the controller, skipping", "recordName", r.Name)
+				continue
+			}
+
+			c.Logger.Info("Deleting record", "recordName", r.Name)
+
+			if err := c.API.DeleteDNSRecord(c.context(), cloudflare.ZoneIdentifier(zoneID), r.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetRecord returns the record with the same name and type from the zone, or nil if it does not exist
+func (c CloudflareClient) GetRecord(record DNSRecord) (*DNSRecord, error) {
+	_, existing, err := c.findRecord(record)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	return &DNSRecord{
+		Zone:    record.Zone,
+		Name:    existing.Name,
+		Type:    existing.Type,
+		Content: existing.Content,
+		TTL:     existing.TTL,
+		Proxied: existing.Proxied != nil && *existing.Proxied,
+	}, nil
+}
+
+// UpsertRecord creates the record in the zone, or updates it if one with the same name and type already exists
+func (c CloudflareClient) UpsertRecord(record DNSRecord) error {
+	zoneID, existing, err := c.findRecord(record)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		c.Logger.Info("Creating record", "recordName", record.Name, "type", record.Type)
+
+		_, err = c.API.CreateDNSRecord(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.CreateDNSRecordParams{
+			Type:    record.Type,
+			Name:    record.Name,
+			Content: record.Content,
+			TTL:     record.TTL,
+			Proxied: cloudflare.BoolPtr(record.Proxied),
+			Comment: OwnershipMarker,
+		})
+
+		return err
+	}
+
+	c.Logger.Info("Updating record", "recordName", record.Name, "type", record.Type)
+
+	_, err = c.API.UpdateDNSRecord(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.UpdateDNSRecordParams{
+		ID:      existing.ID,
+		Content: record.Content,
+		TTL:     record.TTL,
+		Proxied: cloudflare.BoolPtr(record.Proxied),
+		Comment: cloudflare.StringPtr(OwnershipMarker),
+	})
+
+	return err
+}
+
+// DeleteRecord deletes the record from the zone if it carries the OwnershipMarker comment
+func (c CloudflareClient) DeleteRecord(record DNSRecord) error {
+	zoneID, existing, err := c.findRecord(record)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	if existing.Comment != OwnershipMarker {
+		c.Logger.Info("Record is not owned by the controller, skipping", "recordName", record.Name)
+		return nil
+	}
+
+	c.Logger.Info("Deleting record", "recordName", record.Name, "type", record.Type)
+
+	return c.API.DeleteDNSRecord(c.context(), cloudflare.ZoneIdentifier(zoneID), existing.ID)
+}
+
+// findRecord returns the zone ID and the record with the same name and type, if it exists
+func (c CloudflareClient) findRecord(record DNSRecord) (string, *cloudflare.DNSRecord, error) {
+	zoneID, err := c.API.ZoneIDByName(record.Zone)
+	if err != nil {
+		return "", nil, err
+	}
+
+	records, _, err := c.API.ListDNSRecords(c.context(), cloudflare.ZoneIdentifier(zoneID), cloudflare.ListDNSRecordsParams{
+		Type: record.Type,
+		Name: record.Name,
+	})
+	if err != nil {
+		return zoneID, nil, err
+	}
+
+	for i := range records {
+		if records[i].Name == record.Name && records[i].Type == record.Type {
+			return zoneID, &records[i], nil
+		}
+	}
+
+	return zoneID, nil, nil
+}
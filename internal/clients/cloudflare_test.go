@@ -19,6 +19,8 @@ func (m *MockLogger) Error(err error, msg string, keysAndValues ...interface{})
 type MockAPI struct {
 	ListDNSRecordsFunc  func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error)
 	UpdateDNSRecordFunc func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error)
+	DeleteDNSRecordFunc func(ctx context.Context, zoneID *cloudflare.ResourceContainer, recordID string) error
+	CreateDNSRecordFunc func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.CreateDNSRecordParams) (cloudflare.DNSRecord, error)
 	ZoneIDByNameFunc    func(zoneName string) (string, error)
 }
 
@@ -46,6 +48,22 @@ func (m *MockAPI) UpdateDNSRecord(ctx context.Context, zoneID *cloudflare.Resour
 	return cloudflare.DNSRecord{}, nil
 }
 
+func (m *MockAPI) DeleteDNSRecord(ctx context.Context, zoneID *cloudflare.ResourceContainer, recordID string) error {
+	if m.DeleteDNSRecordFunc != nil {
+		return m.DeleteDNSRecordFunc(ctx, zoneID, recordID)
+	}
+
+	return nil
+}
+
+func (m *MockAPI) CreateDNSRecord(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.CreateDNSRecordParams) (cloudflare.DNSRecord, error) {
+	if m.CreateDNSRecordFunc != nil {
+		return m.CreateDNSRecordFunc(ctx, zoneID, params)
+	}
+
+	return cloudflare.DNSRecord{}, nil
+}
+
 var _ = Describe("Cloudflare Client", func() {
 	var cloudflareClient clients.CloudflareClient
 	var cloudflareConfig clients.CloudflareConfig
@@ -100,15 +118,82 @@ var _ = Describe("Cloudflare Client", func() {
 					return "test", nil
 				},
 			}
-			ip, err := cloudflareClient.GetIp()
+			ip, err := cloudflareClient.GetIp(clients.RecordTypeA)
 			Expect(err).To(BeNil())
 			Expect(ip).To(Equal([]string{dummyIp, dummyIp + "1"}))
 		})
+
+		It("Should only return the IPs of the requested record type", func() {
+			dummyIpv6 := "::1"
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{
+							Name:    "test",
+							Content: "127.0.0.1",
+							Type:    "A",
+						},
+						{
+							Name:    "test",
+							Content: dummyIpv6,
+							Type:    "AAAA",
+						},
+					}, nil, nil
+				},
+			}
+			ip, err := cloudflareClient.GetIp(clients.RecordTypeAAAA)
+			Expect(err).To(BeNil())
+			Expect(ip).To(Equal([]string{dummyIpv6}))
+		})
+	})
+
+	Describe("GetRecords", func() {
+		It("Should return every configured record with its current value", func() {
+			dummyIp := "127.0.0.1"
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{
+							Name:    "test",
+							Content: dummyIp,
+							Type:    "A",
+						},
+					}, nil, nil
+				},
+			}
+			records, err := cloudflareClient.GetRecords(clients.RecordTypeA)
+			Expect(err).To(BeNil())
+			Expect(records).To(Equal([]clients.DNSRecord{
+				{Zone: "example.com", Name: "test", Type: "A", Content: dummyIp},
+				{Zone: "example.com", Name: "test2", Type: "A"},
+			}))
+		})
+
+		It("Should report the records whose enforced proxied setting was changed", func() {
+			cloudflareClient.Config.Cloudflare.Zones[0].Records = []clients.Record{
+				{Name: "test", Proxied: false, EnforceProxied: true},
+				{Name: "test2", Proxied: false},
+			}
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{Name: "test", Content: "127.0.0.1", Type: "A", Proxied: cloudflare.BoolPtr(true)},
+						{Name: "test2", Content: "127.0.0.1", Type: "A", Proxied: cloudflare.BoolPtr(true)},
+					}, nil, nil
+				},
+			}
+			records, err := cloudflareClient.GetRecords(clients.RecordTypeA)
+			Expect(err).To(BeNil())
+			Expect(records).To(Equal([]clients.DNSRecord{
+				{Zone: "example.com", Name: "test", Type: "A", Content: "127.0.0.1", ProxiedDrift: true},
+				{Zone: "example.com", Name: "test2", Type: "A", Content: "127.0.0.1"},
+			}))
+		})
 	})
 
 	Describe("SetIP", func() {
 		It("Should set the IP in all the zones with no records", func() {
-			err := cloudflareClient.SetIp("127.0.0.1")
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
 			Expect(err).To(BeNil())
 		})
 
@@ -120,6 +205,7 @@ var _ = Describe("Cloudflare Client", func() {
 						{
 							Name:    "test",
 							Content: "",
+							Type:    "A",
 						},
 					}, nil, nil
 				},
@@ -130,7 +216,7 @@ var _ = Describe("Cloudflare Client", func() {
 				},
 			}
 
-			err := cloudflareClient.SetIp("127.0.0.1")
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
 			Expect(err).To(BeNil())
 			Expect(callCount).To(Equal(1))
 		})
@@ -143,10 +229,12 @@ var _ = Describe("Cloudflare Client", func() {
 						{
 							Name:    "test",
 							Content: "",
+							Type:    "A",
 						},
 						{
 							Name:    "test2",
 							Content: "",
+							Type:    "A",
 						},
 					}, nil, nil
 				},
@@ -157,7 +245,7 @@ var _ = Describe("Cloudflare Client", func() {
 				},
 			}
 
-			err := cloudflareClient.SetIp("127.0.0.1")
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
 			Expect(err).To(BeNil())
 			Expect(callCount).To(Equal(2))
 		})
@@ -170,10 +258,12 @@ var _ = Describe("Cloudflare Client", func() {
 						{
 							Name:    "test",
 							Content: "",
+							Type:    "A",
 						},
 						{
 							Name:    "does-not-exist",
 							Content: "",
+							Type:    "A",
 						},
 					}, nil, nil
 				},
@@ -184,20 +274,134 @@ var _ = Describe("Cloudflare Client", func() {
 				},
 			}
 
-			err := cloudflareClient.SetIp("127.0.0.1")
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
 			Expect(err).To(BeNil())
 			Expect(callCount).To(Equal(1))
 		})
 
+		It("Should only update records of the requested record type", func() {
+			updated := []string{}
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{
+							ID:      "a-record",
+							Name:    "test",
+							Content: "",
+							Type:    "A",
+						},
+						{
+							ID:      "aaaa-record",
+							Name:    "test",
+							Content: "",
+							Type:    "AAAA",
+						},
+					}, nil, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					updated = append(updated, params.ID)
+
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.SetIp("::1", clients.RecordTypeAAAA)
+			Expect(err).To(BeNil())
+			Expect(updated).To(Equal([]string{"aaaa-record"}))
+		})
+
+		It("Should skip records restricted to another type and set their TTL", func() {
+			ttls := map[string]int{}
+			cloudflareClient.Config = clients.NewCloudflareConfig([]clients.RecordConfig{
+				{Zone: "example.com", Name: "test", Type: clients.RecordTypeA, TTL: 300},
+				{Zone: "example.com", Name: "test2", Type: clients.RecordTypeAAAA},
+			})
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{ID: "test", Name: "test", Type: "A"},
+						{ID: "test2", Name: "test2", Type: "A"},
+					}, nil, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					ttls[params.ID] = params.TTL
+
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
+			Expect(err).To(BeNil())
+			Expect(ttls).To(Equal(map[string]int{"test": 300}))
+		})
+
+		It("Should not rewrite records that are already in sync", func() {
+			updated := []string{}
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{ID: "test", Name: "test", Type: "A", Content: "127.0.0.1", Proxied: cloudflare.BoolPtr(false), Comment: clients.OwnershipMarker},
+						{ID: "test2", Name: "test2", Type: "A", Content: "127.0.0.1", Proxied: cloudflare.BoolPtr(true), Comment: clients.OwnershipMarker},
+					}, nil, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					updated = append(updated, params.ID)
+
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
+			Expect(err).To(BeNil())
+			Expect(updated).To(Equal([]string{"test2"}))
+		})
+
 		It("Should return err if ZoneByIP returns an err", func() {
 			cloudflareClient.API = &MockAPI{
 				ZoneIDByNameFunc: func(zoneName string) (string, error) {
 					return "", fmt.Errorf("zone not found")
 				},
 			}
-			err := cloudflareClient.SetIp("127.0.0.1")
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
 			Expect(err).NotTo(BeNil())
-			Expect(err.Error()).To(Equal("zone not found"))
+			Expect(err.Error()).To(Equal("zone example.com: zone not found"))
+		})
+
+		It("Should keep updating the other zones when one of them fails", func() {
+			cloudflareClient.Config.Cloudflare.Zones = append(cloudflareClient.Config.Cloudflare.Zones, clients.Zone{
+				Name:    "example.org",
+				Records: []clients.Record{{Name: "test3"}},
+			})
+			updated := []string{}
+			cloudflareClient.API = &MockAPI{
+				ZoneIDByNameFunc: func(zoneName string) (string, error) {
+					if zoneName == "example.com" {
+						return "", fmt.Errorf("permission denied")
+					}
+
+					return zoneName, nil
+				},
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{{ID: "test3", Name: "test3", Type: "A", Content: "127.0.0.2"}}, nil, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					updated = append(updated, params.ID)
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
+			Expect(err).To(MatchError("zone example.com: permission denied"))
+			Expect(updated).To(Equal([]string{"test3"}))
+
+			zoneErrs, partial := clients.ZoneErrors(err)
+			Expect(partial).To(BeTrue())
+			Expect(zoneErrs).To(HaveKey("example.com"))
+
+			ips, err := cloudflareClient.GetIp(clients.RecordTypeA)
+			Expect(err).To(HaveOccurred())
+			Expect(ips).To(Equal([]string{"127.0.0.2"}))
+			Expect(cloudflareClient.Zones()).To(Equal([]string{"example.com", "example.org"}))
 		})
 
 		It("Should return err if listing dns records returns an err", func() {
@@ -206,9 +410,9 @@ var _ = Describe("Cloudflare Client", func() {
 					return nil, nil, fmt.Errorf("error listing dns records")
 				},
 			}
-			err := cloudflareClient.SetIp("127.0.0.1")
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
 			Expect(err).NotTo(BeNil())
-			Expect(err.Error()).To(Equal("error listing dns records"))
+			Expect(err.Error()).To(Equal("zone example.com: error listing dns records"))
 		})
 
 		It("Should return err if UpdateDNSRecord returns an err", func() {
@@ -218,6 +422,7 @@ var _ = Describe("Cloudflare Client", func() {
 						{
 							Name:    "test",
 							Content: "",
+							Type:    "A",
 						},
 					}, nil, nil
 				},
@@ -225,9 +430,229 @@ var _ = Describe("Cloudflare Client", func() {
 					return cloudflare.DNSRecord{}, fmt.Errorf("error updating dns record")
 				},
 			}
-			err := cloudflareClient.SetIp("127.0.0.1")
+			err := cloudflareClient.SetIp("127.0.0.1", clients.RecordTypeA)
 			Expect(err).NotTo(BeNil())
-			Expect(err.Error()).To(Equal("error updating dns record"))
+			Expect(err.Error()).To(Equal("zone example.com: error updating dns record"))
+		})
+	})
+
+	Describe("UpdateRecords", func() {
+		It("Should only update the given records, listing the records of the zone once", func() {
+			listCount := 0
+			updated := []string{}
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					listCount++
+
+					return []cloudflare.DNSRecord{
+						{ID: "test", Name: "test", Type: "A", Content: "127.0.0.1"},
+						{ID: "test2", Name: "test2", Type: "A", Content: "127.0.0.2"},
+					}, nil, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					updated = append(updated, params.ID)
+
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.UpdateRecords("127.0.0.1", []clients.DNSRecord{
+				{Zone: "example.com", Name: "test2", Type: clients.RecordTypeA, Content: "127.0.0.2"},
+				{Zone: "example.com", Name: "unknown", Type: clients.RecordTypeA, Content: "127.0.0.2"},
+			})
+			Expect(err).To(BeNil())
+			Expect(listCount).To(Equal(1))
+			Expect(updated).To(Equal([]string{"test2"}))
+		})
+
+		It("Should not call the API if none of the given records are configured", func() {
+			cloudflareClient.API = &MockAPI{
+				ZoneIDByNameFunc: func(zoneName string) (string, error) {
+					return "", fmt.Errorf("zone not found")
+				},
+			}
+
+			err := cloudflareClient.UpdateRecords("127.0.0.1", []clients.DNSRecord{
+				{Zone: "example.org", Name: "test", Type: clients.RecordTypeA},
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Describe("AdoptRecords", func() {
+		It("Should only mark the records that are not owned yet, leaving their values untouched", func() {
+			updates := []cloudflare.UpdateDNSRecordParams{}
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{ID: "test", Name: "test", Type: "A", Content: "127.0.0.1"},
+						{ID: "test2", Name: "test2", Type: "A", Content: "127.0.0.2", Comment: clients.OwnershipMarker},
+					}, nil, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					updates = append(updates, params)
+
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.AdoptRecords([]clients.DNSRecord{
+				{Zone: "example.com", Name: "test", Type: clients.RecordTypeA, Content: "127.0.0.1"},
+				{Zone: "example.com", Name: "test2", Type: clients.RecordTypeA, Content: "127.0.0.2"},
+			})
+			Expect(err).To(BeNil())
+			Expect(updates).To(HaveLen(1))
+			Expect(updates[0].ID).To(Equal("test"))
+			Expect(updates[0].Content).To(BeEmpty())
+			Expect(updates[0].Proxied).To(BeNil())
+			Expect(*updates[0].Comment).To(Equal(clients.OwnershipMarker))
+		})
+
+		It("Should not call the API for records that do not exist", func() {
+			cloudflareClient.API = &MockAPI{
+				ZoneIDByNameFunc: func(zoneName string) (string, error) {
+					return "", fmt.Errorf("zone not found")
+				},
+			}
+
+			err := cloudflareClient.AdoptRecords([]clients.DNSRecord{
+				{Zone: "example.com", Name: "test", Type: clients.RecordTypeA},
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Describe("DeleteRecords", func() {
+		It("Should only delete records owned by the controller", func() {
+			deleted := []string{}
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{
+							ID:      "owned",
+							Name:    "test",
+							Type:    "A",
+							Comment: clients.OwnershipMarker,
+						},
+						{
+							ID:   "not-owned",
+							Name: "test2",
+							Type: "A",
+						},
+						{
+							ID:      "not-managed",
+							Name:    "does-not-exist",
+							Type:    "A",
+							Comment: clients.OwnershipMarker,
+						},
+					}, nil, nil
+				},
+				DeleteDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, recordID string) error {
+					deleted = append(deleted, recordID)
+
+					return nil
+				},
+			}
+
+			err := cloudflareClient.DeleteRecords(clients.RecordTypeA)
+			Expect(err).To(BeNil())
+			Expect(deleted).To(Equal([]string{"owned"}))
+		})
+
+		It("Should return err if DeleteDNSRecord returns an err", func() {
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{
+							ID:      "owned",
+							Name:    "test",
+							Type:    "A",
+							Comment: clients.OwnershipMarker,
+						},
+					}, nil, nil
+				},
+				DeleteDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, recordID string) error {
+					return fmt.Errorf("error deleting dns record")
+				},
+			}
+
+			err := cloudflareClient.DeleteRecords(clients.RecordTypeA)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("zone example.com: error deleting dns record"))
+		})
+	})
+
+	Describe("UpsertRecord", func() {
+		record := clients.DNSRecord{Zone: "example.com", Name: "www.example.com", Type: "CNAME", Content: "example.com", TTL: 1}
+
+		It("Should create the record if it does not exist", func() {
+			var created cloudflare.CreateDNSRecordParams
+			cloudflareClient.API = &MockAPI{
+				CreateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.CreateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					created = params
+
+					return cloudflare.DNSRecord{}, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					Fail("UpdateDNSRecord should not be called")
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.UpsertRecord(record)
+			Expect(err).To(BeNil())
+			Expect(created.Name).To(Equal(record.Name))
+			Expect(created.Content).To(Equal(record.Content))
+			Expect(created.Comment).To(Equal(clients.OwnershipMarker))
+		})
+
+		It("Should update the record if it exists", func() {
+			var updated cloudflare.UpdateDNSRecordParams
+			cloudflareClient.API = &MockAPI{
+				ListDNSRecordsFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.ListDNSRecordsParams) ([]cloudflare.DNSRecord, *cloudflare.ResultInfo, error) {
+					return []cloudflare.DNSRecord{
+						{ID: "existing", Name: record.Name, Type: record.Type, Content: "old.example.com"},
+					}, nil, nil
+				},
+				CreateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.CreateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					Fail("CreateDNSRecord should not be called")
+					return cloudflare.DNSRecord{}, nil
+				},
+				UpdateDNSRecordFunc: func(ctx context.Context, zoneID *cloudflare.ResourceContainer, params cloudflare.UpdateDNSRecordParams) (cloudflare.DNSRecord, error) {
+					updated = params
+
+					return cloudflare.DNSRecord{}, nil
+				},
+			}
+
+			err := cloudflareClient.UpsertRecord(record)
+			Expect(err).To(BeNil())
+			Expect(updated.ID).To(Equal("existing"))
+			Expect(updated.Content).To(Equal(record.Content))
+		})
+	})
+
+	Describe("NewCloudflareConfig", func() {
+		It("Should group the records by zone", func() {
+			config := clients.NewCloudflareConfig([]clients.RecordConfig{
+				{Zone: "example.com", Name: "example.com", Proxied: true},
+				{Zone: "example.org", Name: "example.org", Type: clients.RecordTypeAAAA},
+				{Zone: "example.com", Name: "www.example.com", TTL: 300},
+			})
+
+			Expect(config.Cloudflare.Zones).To(Equal([]clients.Zone{
+				{
+					Name: "example.com",
+					Records: []clients.Record{
+						{Name: "example.com", Proxied: true},
+						{Name: "www.example.com", TTL: 300},
+					},
+				},
+				{
+					Name:    "example.org",
+					Records: []clients.Record{{Name: "example.org", Type: clients.RecordTypeAAAA}},
+				},
+			}))
 		})
 	})
 })
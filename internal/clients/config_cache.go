@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxParsedConfigs bounds the number of parsed configs that are kept. The cache starts over once it is full
+const maxParsedConfigs = 256
+
+var (
+	parsedConfigsMu sync.Mutex
+	// parsedConfigs are the CloudflareConfigs by the content of the ConfigMap they were parsed from, so the same config is
+	// not unmarshalled again on every reconciliation. The configs are shared and must not be modified
+	parsedConfigs = map[string]CloudflareConfig{}
+)
+
+// cloudflareConfig returns the CloudflareConfig of the `records` or, if there are none, of the `config` key of the ConfigMap
+func cloudflareConfig(configMap *corev1.ConfigMap) (CloudflareConfig, error) {
+	key := "records"
+	if configMap.Data[key] == "" {
+		key = "config"
+	}
+
+	content := configMap.Data[key]
+	if content == "" {
+		return CloudflareConfig{}, fmt.Errorf("`config` or `records` not found in configMap")
+	}
+
+	cacheKey := key + "/" + content
+
+	parsedConfigsMu.Lock()
+	config, ok := parsedConfigs[cacheKey]
+	parsedConfigsMu.Unlock()
+
+	if ok {
+		return config, nil
+	}
+
+	if key == "records" {
+		var recordConfigs []RecordConfig
+
+		if err := json.Unmarshal([]byte(content), &recordConfigs); err != nil {
+			return CloudflareConfig{}, fmt.Errorf("could not unmarshal the records: %s", err)
+		}
+
+		config = NewCloudflareConfig(recordConfigs)
+	} else if err := json.Unmarshal([]byte(content), &config); err != nil {
+		return CloudflareConfig{}, fmt.Errorf("could not unmarshal the config: %s", err)
+	}
+
+	parsedConfigsMu.Lock()
+	defer parsedConfigsMu.Unlock()
+
+	if len(parsedConfigs) >= maxParsedConfigs {
+		parsedConfigs = map[string]CloudflareConfig{}
+	}
+
+	parsedConfigs[cacheKey] = config
+
+	return config, nil
+}
@@ -0,0 +1,79 @@
+package clients
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Config cache", func() {
+	configMap := func(key, content string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{Data: map[string]string{key: content}}
+	}
+
+	It("should parse the same config only once", func() {
+		content := `{"cloudflare":{"zones":[{"name":"example.com","records":[{"name":"www.example.com"}]}]}}`
+
+		first, err := cloudflareConfig(configMap("config", content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.Cloudflare.Zones).To(Equal([]Zone{{Name: "example.com", Records: []Record{{Name: "www.example.com"}}}}))
+
+		second, err := cloudflareConfig(configMap("config", content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(&second.Cloudflare.Zones[0]).To(BeIdenticalTo(&first.Cloudflare.Zones[0]))
+	})
+
+	It("should tell the records and the config apart", func() {
+		content := `[{"zone":"example.com","name":"www.example.com"}]`
+
+		config, err := cloudflareConfig(configMap("records", content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Cloudflare.Zones).To(Equal([]Zone{{Name: "example.com", Records: []Record{{Name: "www.example.com"}}}}))
+
+		_, err = cloudflareConfig(configMap("config", content))
+		Expect(err).To(MatchError(ContainSubstring("could not unmarshal the config")))
+	})
+
+	It("should not cache configs that cannot be parsed", func() {
+		_, err := cloudflareConfig(configMap("records", "not json"))
+		Expect(err).To(MatchError(ContainSubstring("could not unmarshal the records")))
+
+		parsedConfigsMu.Lock()
+		defer parsedConfigsMu.Unlock()
+		Expect(parsedConfigs).NotTo(HaveKey("records/not json"))
+	})
+
+	It("should be bounded", func() {
+		for i := range maxParsedConfigs + 1 {
+			_, err := cloudflareConfig(configMap("config", fmt.Sprintf(`{"cloudflare":{"zones":[{"name":"example-%d.com"}]}}`, i)))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		parsedConfigsMu.Lock()
+		defer parsedConfigsMu.Unlock()
+		Expect(len(parsedConfigs)).To(BeNumerically("<=", maxParsedConfigs))
+	})
+})
+
+func BenchmarkCloudflareConfig(b *testing.B) {
+	records := "["
+	for i := range 20 {
+		if i > 0 {
+			records += ","
+		}
+
+		records += fmt.Sprintf(`{"zone":"example.com","name":"host-%d.example.com","ttl":300,"proxied":true}`, i)
+	}
+
+	configMap := &corev1.ConfigMap{Data: map[string]string{"records": records + "]"}}
+
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := cloudflareConfig(configMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
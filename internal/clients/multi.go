@@ -0,0 +1,141 @@
+package clients
+
+import (
+	"errors"
+	"slices"
+)
+
+// MultiClient manages the records of several clients as if they were one, e.g. the backends of a Provider
+// Every call is made on all the clients, even if some of them fail, and the errors are joined.
+type MultiClient struct {
+	Clients []Client
+}
+
+// NewMultiClient returns a client that manages the records of all the given clients
+func NewMultiClient(clients ...Client) *MultiClient {
+	return &MultiClient{Clients: clients}
+}
+
+// GetIp returns the IPs of the records of all the clients, including the ones of the zones that did not fail
+func (c *MultiClient) GetIp(recordType string) ([]string, error) {
+	var (
+		ips  []string
+		errs []error
+	)
+
+	for _, client := range c.Clients {
+		clientIps, err := client.GetIp(recordType)
+		if err != nil {
+			errs = append(errs, err)
+
+			if _, partial := ZoneErrors(err); !partial {
+				continue
+			}
+		}
+
+		ips = append(ips, clientIps...)
+	}
+
+	return ips, errors.Join(errs...)
+}
+
+// SetIp sets the IP of the records of all the clients
+func (c *MultiClient) SetIp(ip string, recordType string) error {
+	var errs []error
+
+	for _, client := range c.Clients {
+		errs = append(errs, client.SetIp(ip, recordType))
+	}
+
+	return errors.Join(errs...)
+}
+
+// UpdateRecords sets the IP of the given records in all the clients that can update single records.
+// The other clients update all of their records of the types of the given records instead
+func (c *MultiClient) UpdateRecords(ip string, records []DNSRecord) error {
+	var errs []error
+
+	for _, client := range c.Clients {
+		if updater, ok := client.(RecordsUpdater); ok {
+			errs = append(errs, updater.UpdateRecords(ip, records))
+			continue
+		}
+
+		for _, recordType := range recordTypes(records) {
+			errs = append(errs, client.SetIp(ip, recordType))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// AdoptRecords adopts the given records in all the clients that can adopt records
+func (c *MultiClient) AdoptRecords(records []DNSRecord) error {
+	var errs []error
+
+	for _, client := range c.Clients {
+		if adopter, ok := client.(RecordsAdopter); ok {
+			errs = append(errs, adopter.AdoptRecords(records))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetRecords returns the records of all the clients, including the ones of the zones that did not fail
+func (c *MultiClient) GetRecords(recordType string) ([]DNSRecord, error) {
+	var (
+		records []DNSRecord
+		errs    []error
+	)
+
+	for _, client := range c.Clients {
+		clientRecords, err := client.GetRecords(recordType)
+		if err != nil {
+			errs = append(errs, err)
+
+			if _, partial := ZoneErrors(err); !partial {
+				continue
+			}
+		}
+
+		records = append(records, clientRecords...)
+	}
+
+	return records, errors.Join(errs...)
+}
+
+// DeleteRecords deletes the owned records of all the clients
+func (c *MultiClient) DeleteRecords(recordType string) error {
+	var errs []error
+
+	for _, client := range c.Clients {
+		errs = append(errs, client.DeleteRecords(recordType))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Zones returns the zones of all the clients that group their records in zones
+func (c *MultiClient) Zones() []string {
+	zones := []string{}
+	for _, client := range c.Clients {
+		if lister, ok := client.(ZoneLister); ok {
+			zones = append(zones, lister.Zones()...)
+		}
+	}
+
+	return zones
+}
+
+// recordTypes returns the distinct types of the records, in the order they are first seen
+func recordTypes(records []DNSRecord) []string {
+	types := []string{}
+	for _, record := range records {
+		if !slices.Contains(types, record.Type) {
+			types = append(types, record.Type)
+		}
+	}
+
+	return types
+}
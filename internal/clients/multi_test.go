@@ -0,0 +1,91 @@
+package clients_test
+
+import (
+	"fmt"
+
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// staticClient is a client with a single record
+type staticClient struct {
+	record  clients.DNSRecord
+	err     error
+	setIps  *[]string
+	deletes *int
+}
+
+func (c staticClient) GetIp(recordType string) ([]string, error) {
+	return []string{c.record.Content}, c.err
+}
+
+func (c staticClient) SetIp(ip string, recordType string) error {
+	*c.setIps = append(*c.setIps, ip)
+	return c.err
+}
+
+func (c staticClient) GetRecords(recordType string) ([]clients.DNSRecord, error) {
+	return []clients.DNSRecord{c.record}, c.err
+}
+
+func (c staticClient) DeleteRecords(recordType string) error {
+	*c.deletes++
+	return c.err
+}
+
+var _ = Describe("MultiClient", func() {
+	var (
+		setIps  []string
+		deletes int
+		first   staticClient
+		second  staticClient
+	)
+
+	BeforeEach(func() {
+		setIps = nil
+		deletes = 0
+		first = staticClient{record: clients.DNSRecord{Name: "example.com", Content: "127.0.0.1"}, setIps: &setIps, deletes: &deletes}
+		second = staticClient{record: clients.DNSRecord{Name: "example.org", Content: "127.0.0.2"}, setIps: &setIps, deletes: &deletes}
+	})
+
+	It("should combine the records of all the clients", func() {
+		client := clients.NewMultiClient(first, second)
+
+		ips, err := client.GetIp(clients.RecordTypeA)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"127.0.0.1", "127.0.0.2"}))
+
+		records, err := client.GetRecords(clients.RecordTypeA)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+
+		Expect(client.SetIp("127.0.0.3", clients.RecordTypeA)).To(Succeed())
+		Expect(setIps).To(Equal([]string{"127.0.0.3", "127.0.0.3"}))
+
+		Expect(client.DeleteRecords(clients.RecordTypeA)).To(Succeed())
+		Expect(deletes).To(Equal(2))
+	})
+
+	It("should fall back to setting the IP of all the records of clients that cannot update single records", func() {
+		client := clients.NewMultiClient(first, second)
+
+		Expect(client.UpdateRecords("127.0.0.3", []clients.DNSRecord{
+			{Name: "example.com", Type: clients.RecordTypeA},
+			{Name: "example.org", Type: clients.RecordTypeA},
+		})).To(Succeed())
+		Expect(setIps).To(Equal([]string{"127.0.0.3", "127.0.0.3"}))
+	})
+
+	It("should call every client even if one fails", func() {
+		first.err = fmt.Errorf("first failed")
+		client := clients.NewMultiClient(first, second)
+
+		ips, err := client.GetIp(clients.RecordTypeA)
+		Expect(err).To(MatchError("first failed"))
+		Expect(ips).To(Equal([]string{"127.0.0.2"}))
+
+		Expect(client.SetIp("127.0.0.3", clients.RecordTypeA)).To(MatchError("first failed"))
+		Expect(setIps).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	rateLimitersMu sync.Mutex
+	// rateLimiters are the token buckets of the backends by name, shared by all the clients of the process
+	rateLimiters = map[string]*rate.Limiter{}
+	rateLimit    = rate.Inf
+	rateBurst    = 0
+)
+
+// SetRateLimit limits the calls to the API of every backend, e.g. of all the Cloudflare clients together, to qps calls
+// per second with bursts of up to burst calls. The limits of the DNS providers are usually per account rather than per
+// client, so many Providers sharing an account would trip them otherwise. A qps of 0 disables the limit
+func SetRateLimit(qps float64, burst int) {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	rateLimit, rateBurst = rate.Inf, 0
+	if qps > 0 {
+		rateLimit, rateBurst = rate.Limit(qps), max(burst, 1)
+	}
+
+	// The backends start over with full buckets of the new size
+	rateLimiters = map[string]*rate.Limiter{}
+}
+
+// rateLimiter returns the token bucket of the backend of the given name
+func rateLimiter(name string) *rate.Limiter {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	limiter, ok := rateLimiters[name]
+	if !ok {
+		limiter = rate.NewLimiter(rateLimit, rateBurst)
+		rateLimiters[name] = limiter
+	}
+
+	return limiter
+}
+
+// rateLimitedTransport waits for a token of the rate limiter of the backend before every call, or until the context of the
+// call is canceled
+type rateLimitedTransport struct {
+	backend string
+	next    http.RoundTripper
+}
+
+func (t rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := rateLimiter(t.backend).Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// rateLimitedClient returns a client over the shared transport whose calls wait for the rate limiter of the backend
+func rateLimitedClient(name string) *http.Client {
+	return &http.Client{
+		Transport: rateLimitedTransport{backend: name, next: httpClient.Transport},
+		Timeout:   httpClient.Timeout,
+	}
+}
@@ -0,0 +1,56 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rate limit", func() {
+	BeforeEach(func() {
+		DeferCleanup(SetRateLimit, 0.0, 0)
+	})
+
+	It("should share the rate limiter of a backend between its clients", func() {
+		Expect(rateLimiter(Cloudflare)).To(BeIdenticalTo(rateLimiter(Cloudflare)))
+		Expect(rateLimiter(Cloudflare)).NotTo(BeIdenticalTo(rateLimiter("other")))
+	})
+
+	It("should wait for a token before every call", func() {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		DeferCleanup(server.Close)
+
+		SetRateLimit(0.1, 1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		DeferCleanup(cancel)
+
+		call := func() error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := rateLimitedClient(Cloudflare).Do(req)
+			if err == nil {
+				res.Body.Close()
+			}
+
+			return err
+		}
+
+		Expect(call()).To(Succeed())
+		Expect(call()).To(HaveOccurred())
+		Expect(calls).To(Equal(1))
+
+		By("Not limiting the calls once the limit is disabled")
+		SetRateLimit(0, 0)
+		Expect(call()).To(Succeed())
+		Expect(calls).To(Equal(2))
+	})
+})
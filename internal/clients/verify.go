@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Michaelpalacce/go-ddns-controller/internal/network"
+)
+
+// ErrInvalidCredentials is returned when a provider or webhook rejected the credentials it was called with, as opposed
+// to it not being reachable
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// httpClient makes the calls to the DNS providers over the shared transport
+var httpClient = network.NewHTTPClient(30 * time.Second)
+
+// cloudflareTokenVerifyURL is the endpoint of the Cloudflare API that reports whether an API token is active
+var cloudflareTokenVerifyURL = "https://api.cloudflare.com/client/v4/user/tokens/verify"
+
+// VerifyCredentials checks the credentials in the secret with the provider of the given name, without reading or
+// changing any records. The error wraps ErrInvalidCredentials if the provider rejected them
+func VerifyCredentials(ctx context.Context, name string, secret *corev1.Secret) error {
+	switch name {
+	case Cloudflare:
+		if secret.Data["apiToken"] == nil {
+			return fmt.Errorf("%w: `apiToken` not found in secret", ErrInvalidCredentials)
+		}
+
+		return verifyCloudflareToken(ctx, string(secret.Data["apiToken"]))
+	default:
+		return fmt.Errorf("could not verify the credentials of a provider of type: %s", name)
+	}
+}
+
+// verifyCloudflareToken checks that the API token is known to Cloudflare and active, i.e. neither disabled nor expired
+func verifyCloudflareToken(ctx context.Context, apiToken string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cloudflareTokenVerifyURL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+
+	// The verifications are rate limited by the admission webhook, apart from the syncs
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not verify the Cloudflare API token: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: the Cloudflare API token was rejected with %s", ErrInvalidCredentials, res.Status)
+	default:
+		return fmt.Errorf("could not verify the Cloudflare API token: %s", res.Status)
+	}
+
+	var body struct {
+		Result struct {
+			Status string `json:"status"`
+		} `json:"result"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return fmt.Errorf("could not read the verification of the Cloudflare API token: %w", err)
+	}
+
+	if body.Result.Status != "active" {
+		return fmt.Errorf("%w: the Cloudflare API token is %s", ErrInvalidCredentials, body.Result.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("VerifyCredentials", func() {
+	var (
+		status int
+		body   string
+	)
+
+	BeforeEach(func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer token"))
+
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		DeferCleanup(server.Close)
+
+		DeferCleanup(func(url string) { cloudflareTokenVerifyURL = url }, cloudflareTokenVerifyURL)
+		cloudflareTokenVerifyURL = server.URL
+	})
+
+	secret := &corev1.Secret{Data: map[string][]byte{"apiToken": []byte("token")}}
+
+	It("should accept an active Cloudflare API token", func() {
+		status, body = http.StatusOK, `{"success":true,"result":{"id":"1","status":"active"}}`
+
+		Expect(VerifyCredentials(context.Background(), Cloudflare, secret)).To(Succeed())
+	})
+
+	It("should reject a Cloudflare API token that is unknown or no longer active", func() {
+		status, body = http.StatusUnauthorized, `{"success":false}`
+		Expect(VerifyCredentials(context.Background(), Cloudflare, secret)).To(MatchError(ErrInvalidCredentials))
+
+		status, body = http.StatusOK, `{"success":true,"result":{"id":"1","status":"expired"}}`
+		Expect(VerifyCredentials(context.Background(), Cloudflare, secret)).To(MatchError(ErrInvalidCredentials))
+
+		Expect(VerifyCredentials(context.Background(), Cloudflare, &corev1.Secret{})).To(MatchError(ErrInvalidCredentials))
+	})
+
+	It("should not report the credentials as invalid if Cloudflare cannot be reached", func() {
+		status, body = http.StatusServiceUnavailable, ""
+
+		err := VerifyCredentials(context.Background(), Cloudflare, secret)
+		Expect(err).To(HaveOccurred())
+		Expect(err).NotTo(MatchError(ErrInvalidCredentials))
+	})
+})
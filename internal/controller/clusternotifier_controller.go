@@ -0,0 +1,123 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// ClusterNotifierReconciler reconciles a ClusterNotifier object
+// It shares the reconciliation logic of the NotifierReconciler, the only difference being
+// that the secret and config map are read from the ClusterResourceNamespace
+type ClusterNotifierReconciler struct {
+	NotifierReconciler
+}
+
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusternotifiers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusternotifiers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusternotifiers/finalizers,verbs=update
+
+// Reconcile will reconcile the ClusterNotifier object
+func (r *ClusterNotifierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	notifier := &ddnsv1alpha1.ClusterNotifier{}
+
+	if err := r.Get(ctx, req.NamespacedName, notifier); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return r.reconcileNotifier(ctx, r.ClusterResourceNamespace, notifier)
+}
+
+// ============================================ SETUP FUNCTIONS ============================================
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterNotifierReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.clients = &notifierClients{}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ddnsv1alpha1.ClusterNotifier{}).
+		WithOptions(r.ControllerOptions.options()).
+		Watches(
+			&ddnsv1alpha1.Provider{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForProvider),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&ddnsv1alpha1.ClusterProvider{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForProvider),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.clusterNotifiersForResource)).
+		WatchesMetadata(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.clusterNotifiersForResource)).
+		Complete(r)
+}
+
+// clusterNotifiersForResource returns a list of requests for the ClusterNotifiers that read the Secret or ConfigMap,
+// which is only possible if it is in the ClusterResourceNamespace
+func (r *ClusterNotifierReconciler) clusterNotifiersForResource(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != r.ClusterResourceNamespace {
+		return nil
+	}
+
+	notifiers := &ddnsv1alpha1.ClusterNotifierList{}
+	if err := r.List(ctx, notifiers); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ClusterNotifiers")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, notifier := range notifiers.Items {
+		if notifierUsesResource(&notifier.Spec, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&notifier)})
+		}
+	}
+
+	return requests
+}
+
+// clusterNotifierNamespace returns an empty namespace, as ClusterNotifiers are cluster-scoped
+func clusterNotifierNamespace(ddnsv1alpha1.ResourceRef) (string, bool) {
+	return "", true
+}
+
+// findObjectsForProvider returns a list of requests for ClusterNotifiers that are referenced by Providers or ClusterProviders
+// ClusterNotifiers that select the provider with their providerSelector are returned as well
+func (r *ClusterNotifierReconciler) findObjectsForProvider(ctx context.Context, provider client.Object) []reconcile.Request {
+	notifierList := &ddnsv1alpha1.ClusterNotifierList{}
+	if err := r.List(ctx, notifierList); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ClusterNotifiers")
+		return notifierRequests(provider, true, clusterNotifierNamespace)
+	}
+
+	notifiers := make([]ddnsv1alpha1.NotifierObject, 0, len(notifierList.Items))
+	for i := range notifierList.Items {
+		notifiers = append(notifiers, &notifierList.Items[i])
+	}
+
+	return append(notifierRequests(provider, true, clusterNotifierNamespace), selectingNotifierRequests(ctx, provider, notifiers)...)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+)
+
+var _ = Describe("ClusterNotifier Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+		dummyIp := "127.0.0.1"
+		var (
+			controllerReconciler    *ClusterNotifierReconciler
+			sendNotificationCounter int
+			mu                      sync.Mutex
+		)
+
+		notifierNamespacedName := types.NamespacedName{
+			Name: "test-cluster-notifier",
+		}
+
+		secretNamespacedName := types.NamespacedName{
+			Name:      "test-cluster-notifier-secret",
+			Namespace: "default",
+		}
+
+		configMapNamespacedName := types.NamespacedName{
+			Name:      "test-cluster-notifier-config",
+			Namespace: "default",
+		}
+
+		providerNamespacedName := types.NamespacedName{
+			Name:      "test-cluster-notifier-provider",
+			Namespace: "kube-public",
+		}
+
+		BeforeEach(func() {
+			var err error
+
+			By("creating the Secret and ConfigMap for the ClusterNotifier in the cluster resource namespace")
+			err = k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})
+			if err != nil && errors.IsNotFound(err) {
+				Expect(k8sClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: secretNamespacedName.Name, Namespace: secretNamespacedName.Namespace},
+					StringData: map[string]string{"url": "https://dummy.url"},
+				})).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err = k8sClient.Get(ctx, configMapNamespacedName, &corev1.ConfigMap{})
+			if err != nil && errors.IsNotFound(err) {
+				Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: configMapNamespacedName.Name, Namespace: configMapNamespacedName.Namespace},
+					Data:       map[string]string{"config": ""},
+				})).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the custom resource for the Kind ClusterNotifier")
+			err = k8sClient.Get(ctx, notifierNamespacedName, &ddnsv1alpha1.ClusterNotifier{})
+			if err != nil && errors.IsNotFound(err) {
+				Expect(k8sClient.Create(ctx, &ddnsv1alpha1.ClusterNotifier{
+					ObjectMeta: metav1.ObjectMeta{Name: notifierNamespacedName.Name},
+					Spec: ddnsv1alpha1.NotifierSpec{
+						Name:       "Webhook",
+						SecretName: secretNamespacedName.Name,
+						ConfigMap:  configMapNamespacedName.Name,
+					},
+				})).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating a Provider in another namespace that references the ClusterNotifier")
+			err = k8sClient.Get(ctx, providerNamespacedName, &ddnsv1alpha1.Provider{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &ddnsv1alpha1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      providerNamespacedName.Name,
+						Namespace: providerNamespacedName.Namespace,
+					},
+					Spec: ddnsv1alpha1.ProviderSpec{
+						Name:       "Cloudflare",
+						SecretName: "unused",
+						Config: &ddnsv1alpha1.ProviderConfig{
+							Zones: []ddnsv1alpha1.ZoneConfig{{
+								Name:    "example.com",
+								Records: []ddnsv1alpha1.RecordConfig{{Name: "example.com"}},
+							}},
+						},
+						NotifierRefs: []ddnsv1alpha1.ResourceRef{
+							{Kind: ddnsv1alpha1.ClusterNotifierKind, Name: notifierNamespacedName.Name},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+				resource.Status.PublicIP = dummyIp
+				resource.Status.ProviderIP = dummyIp
+				Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the ClusterNotifierReconciler")
+			sendNotificationCounter = 0
+			controllerReconciler = &ClusterNotifierReconciler{
+				NotifierReconciler: NotifierReconciler{
+					Client: k8sClient,
+					Scheme: k8sClient.Scheme(),
+					NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+						return &MockNotifier{
+							SendNotificationInterceptor: func(message any) {
+								defer GinkgoRecover()
+								mu.Lock()
+								defer mu.Unlock()
+								Expect(message).To(Equal(fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", dummyIp, providerNamespacedName.Name)))
+								sendNotificationCounter++
+							},
+						}, nil
+					},
+					ClusterResourceNamespace: secretNamespacedName.Namespace,
+				},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance ClusterNotifier and related resources")
+			deleteProvider(ctx, &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: providerNamespacedName.Name, Namespace: providerNamespacedName.Namespace},
+			})
+			deleteNotifier(ctx, &ddnsv1alpha1.ClusterNotifier{
+				ObjectMeta: metav1.ObjectMeta{Name: notifierNamespacedName.Name},
+			})
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretNamespacedName.Name, Namespace: secretNamespacedName.Namespace},
+			})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configMapNamespacedName.Name, Namespace: configMapNamespacedName.Namespace},
+			})).To(Succeed())
+		})
+
+		It("should notify of changes of Providers in any namespace", func() {
+			By("Marking the ClusterNotifier as ready")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			notifier := &ddnsv1alpha1.ClusterNotifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.IsReady).To(BeTrue())
+
+			By("Sending a notification due to a change")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sendNotificationCounter).To(Equal(1))
+
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.NotifiedProviders).To(Equal([]ddnsv1alpha1.NotifiedProvider{{
+				Kind:      ddnsv1alpha1.ProviderKind,
+				Name:      providerNamespacedName.Name,
+				Namespace: providerNamespacedName.Namespace,
+				Value:     dummyIp,
+			}}))
+
+			By("not notifying again if the IP has not changed")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sendNotificationCounter).To(Equal(1))
+		})
+
+		It("should fail if the secret is not in the cluster resource namespace", func() {
+			controllerReconciler.ClusterResourceNamespace = "kube-system"
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).To(HaveOccurred())
+			Expect(sendNotificationCounter).To(Equal(0))
+		})
+	})
+})
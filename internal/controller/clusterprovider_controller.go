@@ -0,0 +1,132 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// ClusterProviderReconciler reconciles a ClusterProvider object
+// It shares the reconciliation logic of the ProviderReconciler, the only difference being
+// that the secret and config map are read from the ClusterResourceNamespace
+type ClusterProviderReconciler struct {
+	ProviderReconciler
+	// ClusterResourceNamespace is the namespace the secrets and config maps of ClusterProviders are read from
+	ClusterResourceNamespace string
+}
+
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders/finalizers,verbs=update
+
+// Reconcile will reconcile the ClusterProvider object
+func (r *ClusterProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	provider := &ddnsv1alpha1.ClusterProvider{}
+	if err := r.Get(ctx, req.NamespacedName, provider); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return r.reconcileProvider(ctx, r.ClusterResourceNamespace, provider)
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.records = &providerRecords{}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ddnsv1alpha1.ClusterProvider{}, builder.WithPredicates(providerEventFilter())).
+		WithOptions(r.ControllerOptions.options()).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.clusterProvidersForResource)).
+		WatchesMetadata(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.clusterProvidersForResource)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.clusterProvidersForNode), builder.WithPredicates(nodeEventFilter())).
+		Watches(&ddnsv1alpha1.Notifier{}, handler.EnqueueRequestsFromMapFunc(r.clusterProvidersForNotifier),
+			builder.WithPredicates(notifierEventFilter())).
+		Watches(&ddnsv1alpha1.ClusterNotifier{}, handler.EnqueueRequestsFromMapFunc(r.clusterProvidersForNotifier),
+			builder.WithPredicates(notifierEventFilter())).
+		Complete(r)
+}
+
+// clusterProvidersForResource returns a list of requests for the ClusterProviders that read the Secret or ConfigMap,
+// which is only possible if it is in the ClusterResourceNamespace
+func (r *ClusterProviderReconciler) clusterProvidersForResource(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != r.ClusterResourceNamespace {
+		return nil
+	}
+
+	providers := &ddnsv1alpha1.ClusterProviderList{}
+	if err := r.List(ctx, providers); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ClusterProviders")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, provider := range providers.Items {
+		if providerUsesResource(&provider.Spec, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&provider)})
+		}
+	}
+
+	return requests
+}
+
+// clusterProvidersForNode returns a list of requests for the ClusterProviders that take their public IP from the
+// addresses of the Node
+func (r *ClusterProviderReconciler) clusterProvidersForNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	providers := &ddnsv1alpha1.ClusterProviderList{}
+	if err := r.List(ctx, providers); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ClusterProviders")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, provider := range providers.Items {
+		if selectsNode(&provider.Spec, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&provider)})
+		}
+	}
+
+	return requests
+}
+
+// clusterProvidersForNotifier returns a list of requests for the ClusterProviders with a notifierRef of the name of the
+// notifier
+func (r *ClusterProviderReconciler) clusterProvidersForNotifier(ctx context.Context, obj client.Object) []reconcile.Request {
+	providers := &ddnsv1alpha1.ClusterProviderList{}
+	if err := r.List(ctx, providers, client.MatchingFields{notifierRefsNameIndex: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ClusterProviders")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, provider := range providers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&provider)})
+	}
+
+	return requests
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+var _ = Describe("ClusterProvider Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+		dummyIp := "127.0.0.1"
+		var controllerReconciler *ClusterProviderReconciler
+
+		providerNamespacedName := types.NamespacedName{
+			Name: "test-cluster-provider",
+		}
+
+		secretNamespacedName := types.NamespacedName{
+			Name:      "test-cluster-provider-secret",
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			var err error
+
+			By("creating the Secret for the ClusterProvider in the cluster resource namespace")
+			err = k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      secretNamespacedName.Name,
+						Namespace: secretNamespacedName.Namespace,
+					},
+					StringData: map[string]string{
+						"apiToken": "test-token",
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the custom resource for the Kind ClusterProvider")
+			err = k8sClient.Get(ctx, providerNamespacedName, &ddnsv1alpha1.ClusterProvider{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &ddnsv1alpha1.ClusterProvider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: providerNamespacedName.Name,
+					},
+					Spec: ddnsv1alpha1.ProviderSpec{
+						Name:          "Cloudflare",
+						SecretName:    secretNamespacedName.Name,
+						RetryInterval: ptr.To(intstr.FromInt32(123)),
+						Config: &ddnsv1alpha1.ProviderConfig{
+							Zones: []ddnsv1alpha1.ZoneConfig{{
+								Name:    "example.com",
+								Records: []ddnsv1alpha1.RecordConfig{{Name: "example.com"}},
+							}},
+						},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the ClusterProviderReconciler")
+			controllerReconciler = &ClusterProviderReconciler{
+				ProviderReconciler: ProviderReconciler{
+					Client: k8sClient,
+					Scheme: k8sClient.Scheme(),
+					IPProvider: func(ctx context.Context, c string) (string, error) {
+						return dummyIp, nil
+					},
+					ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+						return MockClient{IP: dummyIp}, nil
+					},
+				},
+				ClusterResourceNamespace: secretNamespacedName.Namespace,
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance ClusterProvider and related resources")
+			provider := &ddnsv1alpha1.ClusterProvider{}
+			if err := k8sClient.Get(ctx, providerNamespacedName, provider); err == nil {
+				Expect(k8sClient.Delete(ctx, provider)).To(Succeed())
+
+				patch := client.MergeFrom(provider.DeepCopy())
+				provider.SetFinalizers(nil)
+				Expect(client.IgnoreNotFound(k8sClient.Patch(ctx, provider, patch))).To(Succeed())
+			}
+
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretNamespacedName.Name, Namespace: secretNamespacedName.Namespace},
+			})).To(Succeed())
+		})
+
+		It("should reconcile the ClusterProvider with the secret from the cluster resource namespace", func() {
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Second * 123))
+
+			provider := &ddnsv1alpha1.ClusterProvider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Finalizers).To(ContainElement(ddnsv1alpha1.ProviderFinalizer))
+			Expect(provider.Status.PublicIP).To(Equal(dummyIp))
+			Expect(provider.Status.ProviderIP).To(Equal(dummyIp))
+			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, ddnsv1alpha1.ProviderConditionTypeSecret)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, ddnsv1alpha1.ProviderConditionTypeClient)).To(BeTrue())
+		})
+
+		It("should fail if the secret is not in the cluster resource namespace", func() {
+			controllerReconciler.ClusterResourceNamespace = "kube-system"
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			provider := &ddnsv1alpha1.ClusterProvider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, ddnsv1alpha1.ProviderConditionTypeSecret)).To(BeTrue())
+		})
+
+		It("should be usable from a namespaced DNSRecord", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			record := &ddnsv1alpha1.DNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster-provider-record",
+					Namespace: "kube-public",
+				},
+				Spec: ddnsv1alpha1.DNSRecordSpec{
+					ProviderRef: ddnsv1alpha1.ProviderRef{Kind: ddnsv1alpha1.ClusterProviderKind, Name: providerNamespacedName.Name},
+					Zone:        "example.com",
+					Name:        "home.example.com",
+				},
+			}
+			Expect(k8sClient.Create(ctx, record)).To(Succeed())
+
+			var upserted clients.DNSRecord
+			recordReconciler := &DNSRecordReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockRecordClient{
+						UpsertInterceptor: func(record clients.DNSRecord) {
+							upserted = record
+						},
+					}, nil
+				},
+				ClusterResourceNamespace: secretNamespacedName.Namespace,
+			}
+
+			_, err = recordReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(record)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(upserted.Content).To(Equal(dummyIp))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(record), record)).To(Succeed())
+			patch := client.MergeFrom(record.DeepCopy())
+			record.SetFinalizers(nil)
+			Expect(k8sClient.Patch(ctx, record, patch)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, record)).To(Succeed())
+		})
+	})
+})
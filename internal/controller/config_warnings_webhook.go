@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// ConfigWarningsValidator returns a warning for every risky setting of a Provider when it is created or updated, e.g. a
+// custom IP provider over plain HTTP, so users learn about them at apply time. It never rejects a Provider
+type ConfigWarningsValidator struct{}
+
+var _ admission.CustomValidator = &ConfigWarningsValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *ConfigWarningsValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.warnings(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *ConfigWarningsValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return v.warnings(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *ConfigWarningsValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *ConfigWarningsValidator) warnings(obj runtime.Object) (admission.Warnings, error) {
+	provider, ok := obj.(*ddnsv1alpha1.Provider)
+	if !ok {
+		return nil, fmt.Errorf("expected a Provider but got a %T", obj)
+	}
+
+	if provider.Spec.Suspend {
+		return nil, nil
+	}
+
+	return provider.Spec.Warnings(), nil
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// CredentialCheck is what the admission webhooks do about the credentials of Providers and Notifiers
+type CredentialCheck string
+
+const (
+	// CredentialCheckOff does not verify the credentials
+	CredentialCheckOff CredentialCheck = "off"
+
+	// CredentialCheckWarn returns a warning for credentials that could not be verified
+	CredentialCheckWarn CredentialCheck = "warn"
+
+	// CredentialCheckReject rejects credentials that the DNS provider or webhook rejected. Credentials that could not be
+	// verified for other reasons, e.g. a Secret that does not exist yet or a provider that cannot be reached, only return a warning
+	CredentialCheckReject CredentialCheck = "reject"
+)
+
+// ParseCredentialCheck returns the CredentialCheck of the given name
+func ParseCredentialCheck(value string) (CredentialCheck, error) {
+	switch check := CredentialCheck(value); check {
+	case CredentialCheckOff, CredentialCheckWarn, CredentialCheckReject:
+		return check, nil
+	default:
+		return "", fmt.Errorf("unknown credential check %q, expected off, warn or reject", value)
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-ddns-stefangenov-site-v1alpha1-provider,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups=ddns.stefangenov.site,resources=providers,verbs=create;update,versions=v1alpha1,name=vprovider.kb.io,admissionReviewVersions=v1,timeoutSeconds=15
+// +kubebuilder:webhook:path=/validate-ddns-stefangenov-site-v1alpha1-notifier,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups=ddns.stefangenov.site,resources=notifiers,verbs=create;update,versions=v1alpha1,name=vnotifier.kb.io,admissionReviewVersions=v1,timeoutSeconds=15
+
+// CredentialsValidator verifies the credentials of Providers and Notifiers when they are created or updated, e.g. the
+// Cloudflare API token or the URL of a webhook, so typos are caught before they silently break the syncs.
+// Suspended Providers and Notifiers are not verified. Verifying calls the DNS provider or webhook, so server-side dry runs
+// are not verified and only return a warning
+type CredentialsValidator struct {
+	Reader client.Reader
+	Check  CredentialCheck
+	// Timeout limits how long the credentials of a single Provider or Notifier are verified for. If 0, only the deadline of
+	// the admission request applies
+	Timeout time.Duration
+	// Limiter limits the verifications, apart from the rate limits of the syncs, so a burst of admissions does not delay
+	// the syncs. Credentials that cannot be verified before the Timeout only return a warning. Not limited if nil
+	Limiter *rate.Limiter
+	// VerifyProvider verifies the credentials in the secret with the provider of the given name
+	VerifyProvider func(ctx context.Context, name string, secret *corev1.Secret) error
+	// VerifyNotifier verifies the credentials in the secret of the notifier
+	VerifyNotifier func(ctx context.Context, notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret) error
+}
+
+var _ admission.CustomValidator = &CredentialsValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *CredentialsValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *CredentialsValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *CredentialsValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate verifies the credentials of the Provider or Notifier. It returns an error for the credentials that were
+// rejected if the Check is CredentialCheckReject, and a warning for every other credential that could not be verified
+func (v *CredentialsValidator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	if v.Check == "" || v.Check == CredentialCheckOff {
+		return nil, nil
+	}
+
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.DryRun != nil && *req.DryRun {
+		return admission.Warnings{"credentials are not verified on dry runs"}, nil
+	}
+
+	if v.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.Timeout)
+		defer cancel()
+	}
+
+	var errs []error
+	switch o := obj.(type) {
+	case *ddnsv1alpha1.Provider:
+		errs = v.verifyProvider(ctx, o)
+	case *ddnsv1alpha1.Notifier:
+		errs = v.verifyNotifier(ctx, o)
+	default:
+		return nil, fmt.Errorf("expected a Provider or a Notifier but got a %T", obj)
+	}
+
+	warnings := admission.Warnings{}
+	rejected := []error{}
+	for _, err := range errs {
+		if v.Check == CredentialCheckReject && errors.Is(err, clients.ErrInvalidCredentials) {
+			rejected = append(rejected, err)
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	return warnings, errors.Join(rejected...)
+}
+
+// verifyProvider verifies the credentials of the Provider and of its backends
+func (v *CredentialsValidator) verifyProvider(ctx context.Context, provider *ddnsv1alpha1.Provider) []error {
+	if provider.Spec.Suspend {
+		return nil
+	}
+
+	errs := []error{}
+	verify := func(name string, secretRef ddnsv1alpha1.SecretRef) {
+		secret, err := v.secret(ctx, provider.Namespace, secretRef)
+		if err == nil {
+			err = v.wait(ctx)
+		}
+
+		if err == nil {
+			err = v.VerifyProvider(ctx, name, secret)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", secretRef.Name, err))
+		}
+	}
+
+	verify(provider.Spec.Name, provider.Spec.GetSecretRef())
+	for _, backend := range provider.Spec.Backends {
+		verify(backend.Client, backend.SecretRef)
+	}
+
+	return errs
+}
+
+// verifyNotifier verifies the credentials of the Notifier
+func (v *CredentialsValidator) verifyNotifier(ctx context.Context, notifier *ddnsv1alpha1.Notifier) []error {
+	if notifier.Spec.Suspend {
+		return nil
+	}
+
+	secretRef := notifier.Spec.GetSecretRef()
+
+	secret, err := v.secret(ctx, notifier.Namespace, secretRef)
+	if err == nil {
+		err = v.wait(ctx)
+	}
+
+	if err == nil {
+		err = v.VerifyNotifier(ctx, notifier, secret)
+	}
+
+	if err != nil {
+		return []error{fmt.Errorf("secret %s: %w", secretRef.Name, err)}
+	}
+
+	return nil
+}
+
+// wait waits for the Limiter to let a verification through
+func (v *CredentialsValidator) wait(ctx context.Context) error {
+	if v.Limiter == nil {
+		return nil
+	}
+
+	if err := v.Limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("too many credentials are verified at once, not verified: %w", err)
+	}
+
+	return nil
+}
+
+// secret reads the referenced Secret, with its keys mapped like the controllers do
+func (v *CredentialsValidator) secret(ctx context.Context, namespace string, secretRef ddnsv1alpha1.SecretRef) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := v.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretRef.Name}, secret); err != nil {
+		return nil, err
+	}
+
+	return mapSecretKeys(secret, secretRef), nil
+}
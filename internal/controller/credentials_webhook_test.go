@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/time/rate"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+var _ = Describe("Credentials validator", func() {
+	ctx := context.Background()
+
+	var verifyErr error
+	var verified []string
+
+	validator := func(check CredentialCheck) *CredentialsValidator {
+		return &CredentialsValidator{
+			Reader: k8sClient,
+			Check:  check,
+			VerifyProvider: func(ctx context.Context, name string, secret *corev1.Secret) error {
+				verified = append(verified, name+"/"+string(secret.Data["apiToken"]))
+				return verifyErr
+			},
+			VerifyNotifier: func(ctx context.Context, notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret) error {
+				verified = append(verified, notifier.GetName()+"/"+string(secret.Data["url"]))
+				return verifyErr
+			},
+		}
+	}
+
+	provider := &ddnsv1alpha1.Provider{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-credentials-provider", Namespace: "default"},
+		Spec: ddnsv1alpha1.ProviderSpec{
+			Name: "Cloudflare",
+			SecretRef: &ddnsv1alpha1.SecretRef{
+				Name: "test-credentials-secret",
+				Keys: map[string]string{"apiToken": "token"},
+			},
+		},
+	}
+
+	notifier := &ddnsv1alpha1.Notifier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-credentials-notifier", Namespace: "default"},
+		Spec:       ddnsv1alpha1.NotifierSpec{Name: "Webhook", SecretName: "test-credentials-secret"},
+	}
+
+	BeforeEach(func() {
+		verifyErr = nil
+		verified = nil
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-credentials-secret", Namespace: "default"},
+			StringData: map[string]string{"token": "abc", "url": "https://example.com/hook"},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, secret)
+	})
+
+	It("should verify the credentials with the keys of the Secret mapped", func() {
+		warnings, err := validator(CredentialCheckReject).ValidateCreate(ctx, provider)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+
+		_, err = validator(CredentialCheckReject).ValidateUpdate(ctx, notifier, notifier)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(verified).To(Equal([]string{"Cloudflare/abc", "test-credentials-notifier/https://example.com/hook"}))
+	})
+
+	It("should reject or warn about credentials that were rejected", func() {
+		verifyErr = fmt.Errorf("%w: the token is expired", clients.ErrInvalidCredentials)
+
+		_, err := validator(CredentialCheckReject).ValidateCreate(ctx, provider)
+		Expect(err).To(MatchError(ContainSubstring("secret test-credentials-secret: invalid credentials: the token is expired")))
+
+		warnings, err := validator(CredentialCheckWarn).ValidateCreate(ctx, notifier)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("the token is expired")))
+	})
+
+	It("should not verify the credentials of dry runs", func() {
+		verifyErr = clients.ErrInvalidCredentials
+
+		dryRunCtx := admission.NewContextWithRequest(ctx, admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(true)},
+		})
+
+		warnings, err := validator(CredentialCheckReject).ValidateCreate(dryRunCtx, provider)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("not verified on dry runs")))
+		Expect(verified).To(BeEmpty())
+	})
+
+	It("should only warn about the credentials that the limiter did not let through in time", func() {
+		verifyErr = clients.ErrInvalidCredentials
+
+		limited := validator(CredentialCheckReject)
+		limited.Timeout = 10 * time.Millisecond
+		limited.Limiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+
+		_, err := limited.ValidateCreate(ctx, provider)
+		Expect(err).To(MatchError(clients.ErrInvalidCredentials))
+
+		warnings, err := limited.ValidateCreate(ctx, notifier)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("too many credentials are verified at once")))
+		Expect(verified).To(HaveLen(1))
+	})
+
+	It("should only warn about credentials that could not be verified", func() {
+		verifyErr = fmt.Errorf("could not verify the Cloudflare API token: 503 Service Unavailable")
+
+		warnings, err := validator(CredentialCheckReject).ValidateCreate(ctx, provider)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("503 Service Unavailable")))
+
+		By("Warning about a Secret that does not exist yet")
+		missing := notifier.DeepCopy()
+		missing.Spec.SecretName = "test-credentials-missing"
+
+		warnings, err = validator(CredentialCheckReject).ValidateCreate(ctx, missing)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("not found")))
+	})
+
+	It("should not verify anything if the check is off or the resource is suspended", func() {
+		verifyErr = clients.ErrInvalidCredentials
+
+		_, err := validator(CredentialCheckOff).ValidateCreate(ctx, provider)
+		Expect(err).NotTo(HaveOccurred())
+
+		suspended := provider.DeepCopy()
+		suspended.Spec.Suspend = true
+		_, err = validator(CredentialCheckReject).ValidateCreate(ctx, suspended)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(verified).To(BeEmpty())
+	})
+})
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/network"
+)
+
+// DebugState is the internal state of the controller, dumped to aid support without a debugger. It holds no credentials:
+// the custom IP providers are stripped of their credentials and query and the notifier clients are only described
+type DebugState struct {
+	// PublicIPs are the cached public IPs by record type
+	PublicIPs map[string][]network.CachedIP `json:"publicIPs"`
+	// IPServices is the last IP returned by every service the public IP was fetched from
+	IPServices map[string]string `json:"ipServices"`
+	// NotifierClients are the cached notifier clients by kind
+	NotifierClients map[string][]NotifierClientState `json:"notifierClients"`
+	// Providers is the backoff state of the providers
+	Providers []ProviderBackoffState `json:"providers"`
+}
+
+// ProviderBackoffState is how soon a Provider or ClusterProvider is retried, as dumped for debugging
+type ProviderBackoffState struct {
+	Kind                string `json:"kind"`
+	Namespace           string `json:"namespace,omitempty"`
+	Name                string `json:"name"`
+	ConsecutiveFailures int64  `json:"consecutiveFailures,omitempty"`
+	// ErrorRetryBackoff is how long the provider waits before retrying its last failure, if it failed
+	ErrorRetryBackoff *metav1.Duration `json:"errorRetryBackoff,omitempty"`
+	OutOfSyncSince    *metav1.Time     `json:"outOfSyncSince,omitempty"`
+	// OutOfSyncBackoff is how long the provider waits before verifying its records again, if they are out of sync
+	OutOfSyncBackoff *metav1.Duration `json:"outOfSyncBackoff,omitempty"`
+}
+
+// DebugStateHandler serves the DebugState as JSON
+type DebugStateHandler struct {
+	Reader client.Reader
+	// NamespaceScoped leaves out the ClusterProviders
+	NamespaceScoped bool
+	// ErrorRetryInterval is the ErrorRetryInterval of the ProviderReconciler
+	ErrorRetryInterval time.Duration
+	// IPCaches are the caches of the public IPs by record type
+	IPCaches map[string]*network.IPCache
+	// Notifiers are the reconcilers of the notifiers by kind
+	Notifiers map[string]*NotifierReconciler
+}
+
+// ServeHTTP dumps the DebugState of the controller
+func (h *DebugStateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	providers, err := listProviders(req.Context(), h.Reader, h.NamespaceScoped)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	state := DebugState{
+		PublicIPs:       map[string][]network.CachedIP{},
+		IPServices:      network.LastIPs(),
+		NotifierClients: map[string][]NotifierClientState{},
+		Providers:       providerBackoffStates(providers, h.ErrorRetryInterval),
+	}
+
+	for recordType, cache := range h.IPCaches {
+		state.PublicIPs[recordType] = cache.Entries()
+	}
+
+	for kind, notifierReconciler := range h.Notifiers {
+		state.NotifierClients[kind] = notifierReconciler.CachedClients()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(state)
+}
+
+// providerBackoffStates returns the backoff state of every provider, ordered by kind, namespace and name
+func providerBackoffStates(providers []ddnsv1alpha1.ProviderObject, errorRetryInterval time.Duration) []ProviderBackoffState {
+	states := []ProviderBackoffState{}
+
+	for _, provider := range providers {
+		status := provider.GetProviderStatus()
+		state := ProviderBackoffState{
+			Kind:                providerKind(provider),
+			Namespace:           provider.GetNamespace(),
+			Name:                provider.GetName(),
+			ConsecutiveFailures: status.ConsecutiveFailures,
+			OutOfSyncSince:      status.OutOfSyncSince,
+		}
+
+		if status.ConsecutiveFailures > 0 {
+			state.ErrorRetryBackoff = &metav1.Duration{Duration: errorRetryBackoff(provider, errorRetryInterval)}
+		}
+
+		if status.OutOfSyncSince != nil {
+			state.OutOfSyncBackoff = &metav1.Duration{Duration: outOfSyncBackoff(provider.GetProviderSpec(), status.OutOfSyncSince.Time)}
+		}
+
+		states = append(states, state)
+	}
+
+	slices.SortFunc(states, func(a, b ProviderBackoffState) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	return states
+}
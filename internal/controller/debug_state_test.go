@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+)
+
+var _ = Describe("Debug state", func() {
+	It("should report the backoff of the failing and out of sync providers", func() {
+		outOfSyncSince := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+
+		states := providerBackoffStates([]ddnsv1alpha1.ProviderObject{
+			&ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "out-of-sync"},
+				Status:     ddnsv1alpha1.ProviderStatus{OutOfSyncSince: &outOfSyncSince},
+			},
+			&ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failing"},
+				Status:     ddnsv1alpha1.ProviderStatus{ConsecutiveFailures: 3},
+			},
+		}, time.Minute)
+
+		Expect(states).To(HaveLen(2))
+		Expect(states[0].Name).To(Equal("failing"))
+		Expect(states[0].ErrorRetryBackoff.Duration).To(Equal(4 * time.Minute))
+		Expect(states[0].OutOfSyncBackoff).To(BeNil())
+
+		Expect(states[1].Name).To(Equal("out-of-sync"))
+		Expect(states[1].ErrorRetryBackoff).To(BeNil())
+		Expect(states[1].OutOfSyncBackoff.Duration).To(BeNumerically("~", 10*time.Minute, time.Second))
+	})
+
+	It("should describe the cached notifier clients without their credentials", func() {
+		cache := &notifierClients{}
+		cache.set("uid", types.NamespacedName{Namespace: "default", Name: "webhook"}, "1/secret/config",
+			&notifiers.WebhookNotifier{Url: "https://example.com/hook?token=secret"})
+
+		Expect(cache.states()).To(Equal([]NotifierClientState{
+			{Namespace: "default", Name: "webhook", Key: "1/secret/config", Type: "*notifiers.WebhookNotifier"},
+		}))
+
+		var nilCache *notifierClients
+		Expect(nilCache.states()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,232 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// DNSEndpointGVK is the kind of the DNSEndpoints of external-dns. Its types are not imported, so the controller does not
+// depend on external-dns, and the DNSEndpoints are read as unstructured objects
+var DNSEndpointGVK = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// dnsEndpointProxiedProperty is the provider specific property of external-dns that proxies a record at Cloudflare
+const dnsEndpointProxiedProperty = "external-dns.alpha.kubernetes.io/cloudflare-proxied"
+
+// dnsEndpointSpec is the part of the spec of a DNSEndpoint that is reconciled
+type dnsEndpointSpec struct {
+	Endpoints []dnsEndpoint `json:"endpoints,omitempty"`
+}
+
+// dnsEndpoint is a single record of a DNSEndpoint
+type dnsEndpoint struct {
+	DNSName          string                `json:"dnsName"`
+	Targets          []string              `json:"targets,omitempty"`
+	RecordType       string                `json:"recordType,omitempty"`
+	RecordTTL        int64                 `json:"recordTTL,omitempty"`
+	ProviderSpecific []dnsEndpointProperty `json:"providerSpecific,omitempty"`
+}
+
+type dnsEndpointProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// DNSEndpointReconciler keeps a DNSRecord for every endpoint of the external-dns DNSEndpoints with the
+// IngressProviderAnnotation, so tooling that creates DNSEndpoints can manage the records through the Providers
+type DNSEndpointReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder reports misconfigured DNSEndpoints as events. No events are recorded if it is nil
+	Recorder record.EventRecorder
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+}
+
+// +kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile creates or updates the DNSRecords of the endpoints of the DNSEndpoint and deletes the ones it no longer needs
+// The DNSRecords are owned by the DNSEndpoint, so they are garbage collected with it
+func (r *DNSEndpointReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+	if err := r.Get(ctx, req.NamespacedName, endpoint); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	desired, err := r.desiredRecords(ctx, endpoint)
+	if err != nil {
+		// The records are left as they are until the DNSEndpoint is fixed, instead of removing them from the provider
+		log.FromContext(ctx).Error(err, "DNSEndpoint is misconfigured, leaving its DNSRecords as they are")
+		r.event(endpoint, "InvalidDNSEndpoint", err.Error())
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncOwnedRecords(ctx, r.Client, r.Scheme, endpoint, ddnsv1alpha1.DNSEndpointLabel, desired); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, r.patchObservedGeneration(ctx, endpoint)
+}
+
+// =================================================== PRIVATE FUNCTIONS ===================================================
+
+// desiredRecords returns the DNSRecords the DNSEndpoint needs: one for every endpoint of a type a DNSRecord supports.
+// Endpoints of A and AAAA records without targets follow the public IP. DNSEndpoints without the
+// IngressProviderAnnotation need none
+func (r *DNSEndpointReconciler) desiredRecords(ctx context.Context, endpoint *unstructured.Unstructured) ([]ddnsv1alpha1.DNSRecord, error) {
+	log := log.FromContext(ctx)
+
+	annotations := endpoint.GetAnnotations()
+	if annotations[ddnsv1alpha1.IngressProviderAnnotation] == "" {
+		return nil, nil
+	}
+
+	ref, err := annotatedProviderRef(annotations[ddnsv1alpha1.IngressProviderAnnotation])
+	if err != nil {
+		return nil, err
+	}
+
+	zone, err := annotatedZone(annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := dnsEndpointSpec{}
+	if rawSpec, ok := endpoint.Object["spec"].(map[string]interface{}); ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, &spec); err != nil {
+			return nil, fmt.Errorf("unable to read the endpoints: %w", err)
+		}
+	}
+
+	records := []ddnsv1alpha1.DNSRecord{}
+	for _, e := range spec.Endpoints {
+		host := strings.TrimSuffix(strings.ToLower(e.DNSName), ".")
+		recordType := strings.ToUpper(e.RecordType)
+		if recordType == "" {
+			recordType = clients.RecordTypeA
+		}
+
+		switch {
+		case !slices.Contains([]string{clients.RecordTypeA, clients.RecordTypeAAAA, recordTypeCNAME, "TXT"}, recordType):
+			log.Info("Record type is not supported, skipping the endpoint", "host", host, "type", recordType)
+			continue
+		case !inZone(host, zone):
+			log.Info("Host is not in the zone of the DNSEndpoint, skipping the endpoint", "host", host, "zone", zone)
+			continue
+		case len(e.Targets) == 0 && recordType != clients.RecordTypeA && recordType != clients.RecordTypeAAAA:
+			log.Info("Endpoint has no targets, skipping it", "host", host, "type", recordType)
+			continue
+		}
+
+		record := ownedRecord(endpoint, ddnsv1alpha1.DNSEndpointLabel, ddnsv1alpha1.DNSRecordSpec{
+			ProviderRef: ref,
+			Zone:        zone,
+			Name:        host,
+			Type:        recordType,
+			TTL:         max(int(e.RecordTTL), 1),
+			Proxied:     dnsEndpointProxied(e, annotations),
+		})
+
+		// A DNSRecord holds a single value, so only the first target is kept in sync, as is the first endpoint of a record
+		if len(e.Targets) > 0 {
+			record.Spec.Value = e.Targets[0]
+		}
+		if len(e.Targets) > 1 {
+			log.Info("Endpoint has several targets, only the first one is used", "host", host, "type", recordType, "target", e.Targets[0])
+		}
+
+		if slices.ContainsFunc(records, func(d ddnsv1alpha1.DNSRecord) bool { return d.Name == record.Name }) {
+			log.Info("Record is listed in several endpoints, only the first one is used", "host", host, "type", recordType)
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// dnsEndpointProxied returns true if the records of the endpoint are proxied, through the provider specific property of
+// external-dns, or the IngressProxiedAnnotation of the DNSEndpoint otherwise
+func dnsEndpointProxied(e dnsEndpoint, annotations map[string]string) bool {
+	for _, property := range e.ProviderSpecific {
+		if property.Name == dnsEndpointProxiedProperty {
+			return property.Value == "true"
+		}
+	}
+
+	return annotations[ddnsv1alpha1.IngressProxiedAnnotation] == "true"
+}
+
+// patchObservedGeneration reports in the status of the DNSEndpoint that its current generation was reconciled, as
+// external-dns does
+func (r *DNSEndpointReconciler) patchObservedGeneration(ctx context.Context, endpoint *unstructured.Unstructured) error {
+	observed, _, _ := unstructured.NestedInt64(endpoint.Object, "status", "observedGeneration")
+	if observed == endpoint.GetGeneration() {
+		return nil
+	}
+
+	patch := client.MergeFrom(endpoint.DeepCopy())
+	if err := unstructured.SetNestedField(endpoint.Object, endpoint.GetGeneration(), "status", "observedGeneration"); err != nil {
+		return err
+	}
+
+	return client.IgnoreNotFound(r.Status().Patch(ctx, endpoint, patch))
+}
+
+// event records an event on the DNSEndpoint, if there is a Recorder
+func (r *DNSEndpointReconciler) event(endpoint *unstructured.Unstructured, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(endpoint, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+// The DNSEndpoint CRD of external-dns has to be installed
+func (r *DNSEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(endpoint).
+		WithOptions(r.ControllerOptions.options()).
+		Owns(&ddnsv1alpha1.DNSRecord{}).
+		Complete(r)
+}
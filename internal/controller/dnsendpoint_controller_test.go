@@ -0,0 +1,111 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// The DNSEndpoint CRD of external-dns is not installed in the test environment, so only the mapping of the endpoints to
+// DNSRecords is tested
+var _ = Describe("DNSEndpoint Controller", func() {
+	Context("When mapping the endpoints to DNSRecords", func() {
+		ctx := context.Background()
+		var endpoint *unstructured.Unstructured
+
+		controllerReconciler := &DNSEndpointReconciler{}
+
+		BeforeEach(func() {
+			endpoint = &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":      "test-dnsendpoint",
+					"namespace": "default",
+					"annotations": map[string]interface{}{
+						ddnsv1alpha1.IngressProviderAnnotation: "cloudflare",
+						ddnsv1alpha1.IngressZoneAnnotation:     "example.com",
+					},
+				},
+				"spec": map[string]interface{}{
+					"endpoints": []interface{}{
+						map[string]interface{}{"dnsName": "home.example.com", "recordType": "A"},
+						map[string]interface{}{
+							"dnsName":    "www.example.com",
+							"recordType": "CNAME",
+							"targets":    []interface{}{"home.example.com"},
+							"recordTTL":  int64(300),
+							"providerSpecific": []interface{}{
+								map[string]interface{}{"name": dnsEndpointProxiedProperty, "value": "true"},
+							},
+						},
+						map[string]interface{}{"dnsName": "mail.example.com", "recordType": "MX", "targets": []interface{}{"10 mx.example.com"}},
+						map[string]interface{}{"dnsName": "other.org", "recordType": "A", "targets": []interface{}{"127.0.0.1"}},
+						map[string]interface{}{"dnsName": "txt.example.com", "recordType": "TXT"},
+					},
+				},
+			}}
+			endpoint.SetGroupVersionKind(DNSEndpointGVK)
+		})
+
+		It("should create a DNSRecord for every supported endpoint in the zone", func() {
+			records, err := controllerReconciler.desiredRecords(ctx, endpoint)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+
+			Expect(records[0].Labels).To(HaveKeyWithValue(ddnsv1alpha1.DNSEndpointLabel, "test-dnsendpoint"))
+			Expect(records[0].Spec).To(Equal(ddnsv1alpha1.DNSRecordSpec{
+				ProviderRef:    ddnsv1alpha1.ProviderRef{Kind: ddnsv1alpha1.ProviderKind, Name: "cloudflare"},
+				Zone:           "example.com",
+				Name:           "home.example.com",
+				Type:           "A",
+				TTL:            1,
+				DeletionPolicy: ddnsv1alpha1.DeletionPolicyDelete,
+			}))
+
+			Expect(records[1].Spec).To(Equal(ddnsv1alpha1.DNSRecordSpec{
+				ProviderRef:    ddnsv1alpha1.ProviderRef{Kind: ddnsv1alpha1.ProviderKind, Name: "cloudflare"},
+				Zone:           "example.com",
+				Name:           "www.example.com",
+				Type:           "CNAME",
+				Value:          "home.example.com",
+				TTL:            300,
+				Proxied:        true,
+				DeletionPolicy: ddnsv1alpha1.DeletionPolicyDelete,
+			}))
+		})
+
+		It("should need no DNSRecords without the provider annotation", func() {
+			endpoint.SetAnnotations(nil)
+
+			records, err := controllerReconciler.desiredRecords(ctx, endpoint)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+
+		It("should require the zone annotation", func() {
+			endpoint.SetAnnotations(map[string]string{ddnsv1alpha1.IngressProviderAnnotation: "cloudflare"})
+
+			_, err := controllerReconciler.desiredRecords(ctx, endpoint)
+			Expect(err).To(MatchError(ContainSubstring(ddnsv1alpha1.IngressZoneAnnotation)))
+		})
+	})
+})
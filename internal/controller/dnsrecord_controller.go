@@ -0,0 +1,351 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// DNSRecordReconciler reconciles a DNSRecord object
+type DNSRecordReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	ClientFactory ClientFactory
+	// ClusterResourceNamespace is the namespace the secrets and config maps of ClusterProviders are read from
+	ClusterResourceNamespace string
+	// NamespaceScoped leaves out the ClusterProviders, which cannot be read without cluster-wide RBAC
+	NamespaceScoped bool
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+}
+
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders,verbs=get;list;watch
+
+// Reconcile will reconcile the DNSRecord object
+func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		err          error
+		provider     ddnsv1alpha1.ProviderObject
+		recordClient clients.RecordClient
+		existing     *clients.DNSRecord
+	)
+
+	record := &ddnsv1alpha1.DNSRecord{}
+	if err = r.Get(ctx, req.NamespacedName, record); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !record.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, req, record)
+	}
+
+	if err = r.ensureFinalizer(ctx, record); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	record.Conditions().FillConditions()
+
+	if provider, err = r.fetchProvider(ctx, req, record); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requeue := ctrl.Result{
+		Requeue:      true,
+		RequeueAfter: provider.GetProviderSpec().GetRetryInterval(),
+	}
+
+	if recordClient, err = r.fetchRecordClient(ctx, provider); err != nil {
+		_ = r.patchSynced(ctx, record, "ClientCreated", err.Error(), false)
+		return ctrl.Result{}, err
+	}
+
+	desired := r.desiredRecord(record, provider)
+	if desired.Content == "" {
+		log.FromContext(ctx).Info("Provider has not detected a public IP yet, waiting")
+
+		return requeue, r.patchSynced(ctx, record, "WaitingForPublicIP",
+			fmt.Sprintf("Provider %s has not detected a public IP yet", provider.GetName()), false)
+	}
+
+	if existing, err = recordClient.GetRecord(desired); err != nil {
+		_ = r.patchSynced(ctx, record, "RecordFetched", err.Error(), false)
+		return ctrl.Result{}, err
+	}
+
+	if existing == nil || *existing != desired {
+		log.FromContext(ctx).Info("Record desynced, updating provider", "record", desired.Name, "type", desired.Type)
+
+		if err = recordClient.UpsertRecord(desired); err != nil {
+			_ = r.patchSynced(ctx, record, "RecordUpdated", err.Error(), false)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err = r.patchStatus(ctx, record, r.patchValue(desired.Content)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.patchSynced(ctx, record, "RecordSynced", fmt.Sprintf("Record %s is in sync", desired.Name), true); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.patchStatus(ctx, record, r.patchObservedGeneration()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return requeue, nil
+}
+
+// =================================================== PRIVATE FUNCTIONS ===================================================
+
+// ensureFinalizer adds the finalizer to the DNSRecord, so the record can be removed from the provider on deletion
+func (r *DNSRecordReconciler) ensureFinalizer(ctx context.Context, record *ddnsv1alpha1.DNSRecord) error {
+	if controllerutil.ContainsFinalizer(record, ddnsv1alpha1.DNSRecordFinalizer) {
+		return nil
+	}
+
+	patch := finalizersPatch(record)
+	controllerutil.AddFinalizer(record, ddnsv1alpha1.DNSRecordFinalizer)
+
+	return r.Patch(ctx, record, patch)
+}
+
+// finalize removes the record from the provider before removing the finalizer, unless its DeletionPolicy is Orphan
+// If the Provider no longer exists, there are no credentials to remove the record with, so it is left as is
+func (r *DNSRecordReconciler) finalize(ctx context.Context, req ctrl.Request, record *ddnsv1alpha1.DNSRecord) error {
+	if !controllerutil.ContainsFinalizer(record, ddnsv1alpha1.DNSRecordFinalizer) {
+		return nil
+	}
+
+	provider, err := getProvider(ctx, r.Client, r.NamespaceScoped, record.Spec.ProviderRef, req.Namespace)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if record.Spec.DeletionPolicy == ddnsv1alpha1.DeletionPolicyOrphan {
+		log.FromContext(ctx).Info("DNSRecord is being deleted, leaving the record at the provider as its deletionPolicy is Orphan")
+	} else if err == nil {
+		log.FromContext(ctx).Info("DNSRecord is being deleted, deleting record from provider")
+
+		recordClient, err := r.fetchRecordClient(ctx, provider)
+		if err != nil {
+			return err
+		}
+
+		if err := recordClient.DeleteRecord(r.desiredRecord(record, provider)); err != nil {
+			return fmt.Errorf("unable to delete record %s: %w", record.Spec.Name, err)
+		}
+	} else {
+		log.FromContext(ctx).Info("Provider not found, leaving the record in place", "provider", record.Spec.ProviderRef.Name)
+	}
+
+	patch := finalizersPatch(record)
+	controllerutil.RemoveFinalizer(record, ddnsv1alpha1.DNSRecordFinalizer)
+
+	return r.Patch(ctx, record, patch)
+}
+
+// fetchProvider will fetch the referenced Provider or ClusterProvider and set the status of the DNSRecord
+func (r *DNSRecordReconciler) fetchProvider(
+	ctx context.Context,
+	req ctrl.Request,
+	record *ddnsv1alpha1.DNSRecord,
+) (ddnsv1alpha1.ProviderObject, error) {
+	condOptions := []conditions.ConditionOption{}
+
+	provider, err := getProvider(ctx, r.Client, r.NamespaceScoped, record.Spec.ProviderRef, req.Namespace)
+	if err != nil {
+		condOptions = append(condOptions,
+			conditions.WithReasonAndMessage("ProviderFound", err.Error()),
+			conditions.False(),
+		)
+	} else {
+		condOptions = append(condOptions,
+			conditions.WithReasonAndMessage("ProviderFound", fmt.Sprintf("Provider %s found", provider.GetName())),
+			conditions.True(),
+		)
+	}
+
+	_ = conditions.PatchConditions(ctx, r.Client, record, ddnsv1alpha1.DNSRecordConditionTypeProvider, condOptions...)
+
+	return provider, err
+}
+
+// fetchRecordClient builds the client of the provider, using its secret and config,
+// and checks that it can manage single records
+func (r *DNSRecordReconciler) fetchRecordClient(
+	ctx context.Context,
+	provider ddnsv1alpha1.ProviderObject,
+) (clients.RecordClient, error) {
+	spec := provider.GetProviderSpec()
+	namespace := resourceNamespace(provider, r.ClusterResourceNamespace)
+
+	secret, err := fetchProviderSecret(ctx, r.Client, namespace, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := fetchProviderConfig(ctx, r.Client, namespace, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	providerClient, err := r.ClientFactory(ctx, spec.Name, secret, configMap, log.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	recordClient, ok := providerClient.(clients.RecordClient)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support managing single records", spec.Name)
+	}
+
+	return recordClient, nil
+}
+
+// desiredRecord builds the record as it should be at the provider
+// A and AAAA records without a value fall back to the public IP detected by the Provider
+func (r *DNSRecordReconciler) desiredRecord(record *ddnsv1alpha1.DNSRecord, provider ddnsv1alpha1.ProviderObject) clients.DNSRecord {
+	content := record.Spec.Value
+	if content == "" {
+		switch record.Spec.Type {
+		case clients.RecordTypeA:
+			content = provider.GetProviderStatus().PublicIP
+		case clients.RecordTypeAAAA:
+			content = provider.GetProviderStatus().PublicIPv6
+		}
+	}
+
+	return clients.DNSRecord{
+		Zone:    record.Spec.Zone,
+		Name:    record.Spec.Name,
+		Type:    record.Spec.Type,
+		Content: content,
+		TTL:     record.Spec.TTL,
+		Proxied: record.Spec.Proxied,
+	}
+}
+
+// patchSynced sets the Synced condition of the DNSRecord
+func (r *DNSRecordReconciler) patchSynced(
+	ctx context.Context,
+	record *ddnsv1alpha1.DNSRecord,
+	reason, message string,
+	synced bool,
+) error {
+	status := conditions.False()
+	if synced {
+		status = conditions.True()
+	}
+
+	return conditions.PatchConditions(ctx, r.Client, record, ddnsv1alpha1.DNSRecordConditionTypeSynced,
+		conditions.WithReasonAndMessage(reason, message),
+		status,
+	)
+}
+
+func (r *DNSRecordReconciler) patchStatus(
+	ctx context.Context,
+	record *ddnsv1alpha1.DNSRecord,
+	apply func(*ddnsv1alpha1.DNSRecord) bool,
+) error {
+	if apply(record) {
+		return conditions.ApplyStatus(ctx, r.Client, record)
+	}
+
+	return nil
+}
+
+// recordsForProvider maps a Provider or ClusterProvider to the DNSRecords that reference it,
+// so records that follow the public IP are updated as soon as it changes
+// ClusterProviders are not namespaced, so the DNSRecords of all namespaces are listed for them
+func (r *DNSRecordReconciler) recordsForProvider(ctx context.Context, obj client.Object) []reconcile.Request {
+	records := &ddnsv1alpha1.DNSRecordList{}
+	if err := r.List(ctx, records, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list DNSRecords")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, record := range records.Items {
+		if refersTo(record.Spec.ProviderRef, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&record)})
+		}
+	}
+
+	return requests
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DNSRecordReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&ddnsv1alpha1.DNSRecord{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(r.ControllerOptions.options()).
+		Watches(&ddnsv1alpha1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.recordsForProvider))
+
+	if !r.NamespaceScoped {
+		controllerBuilder = controllerBuilder.Watches(&ddnsv1alpha1.ClusterProvider{}, handler.EnqueueRequestsFromMapFunc(r.recordsForProvider))
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+// =================================================== PATCH FUNCTIONS ===================================================
+
+func (r *DNSRecordReconciler) patchValue(value string) func(record *ddnsv1alpha1.DNSRecord) bool {
+	return func(record *ddnsv1alpha1.DNSRecord) bool {
+		if record.Status.Value == value {
+			return false
+		}
+
+		record.Status.Value = value
+
+		return true
+	}
+}
+
+func (r *DNSRecordReconciler) patchObservedGeneration() func(record *ddnsv1alpha1.DNSRecord) bool {
+	return func(record *ddnsv1alpha1.DNSRecord) bool {
+		if record.Status.ObservedGeneration == record.GetGeneration() {
+			return false
+		}
+		record.Status.ObservedGeneration = record.GetGeneration()
+		return true
+	}
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+var _ = Describe("DNSRecord Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+		publicIp := "127.0.0.1"
+		var (
+			controllerReconciler *DNSRecordReconciler
+			recordClient         *MockRecordClient
+		)
+
+		recordNamespacedName := types.NamespacedName{
+			Name:      "test-dnsrecord",
+			Namespace: "default",
+		}
+
+		providerNamespacedName := types.NamespacedName{
+			Name:      "test-dnsrecord-provider",
+			Namespace: "default",
+		}
+
+		secretNamespacedName := types.NamespacedName{
+			Name:      "test-dnsrecord-secret",
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			var err error
+
+			By("creating the Secret for the Provider")
+			err = k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      secretNamespacedName.Name,
+						Namespace: secretNamespacedName.Namespace,
+					},
+					StringData: map[string]string{
+						"apiToken": "test-token",
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the Provider for the DNSRecord")
+			err = k8sClient.Get(ctx, providerNamespacedName, &ddnsv1alpha1.Provider{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &ddnsv1alpha1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      providerNamespacedName.Name,
+						Namespace: providerNamespacedName.Namespace,
+					},
+					Spec: ddnsv1alpha1.ProviderSpec{
+						Name:          "Cloudflare",
+						SecretName:    secretNamespacedName.Name,
+						RetryInterval: ptr.To(intstr.FromInt32(123)),
+						Config: &ddnsv1alpha1.ProviderConfig{
+							Zones: []ddnsv1alpha1.ZoneConfig{{
+								Name:    "example.com",
+								Records: []ddnsv1alpha1.RecordConfig{{Name: "example.com"}},
+							}},
+						},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+				resource.Status.PublicIP = publicIp
+				Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the custom resource for the Kind DNSRecord")
+			err = k8sClient.Get(ctx, recordNamespacedName, &ddnsv1alpha1.DNSRecord{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &ddnsv1alpha1.DNSRecord{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      recordNamespacedName.Name,
+						Namespace: recordNamespacedName.Namespace,
+					},
+					Spec: ddnsv1alpha1.DNSRecordSpec{
+						ProviderRef: ddnsv1alpha1.ProviderRef{Name: providerNamespacedName.Name},
+						Zone:        "example.com",
+						Name:        "home.example.com",
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the DNSRecordReconciler")
+			recordClient = &MockRecordClient{}
+			controllerReconciler = &DNSRecordReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return recordClient, nil
+				},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance DNSRecord and related resources")
+			record := &ddnsv1alpha1.DNSRecord{}
+			if err := k8sClient.Get(ctx, recordNamespacedName, record); err == nil {
+				Expect(k8sClient.Delete(ctx, record)).To(Succeed())
+
+				patch := client.MergeFrom(record.DeepCopy())
+				record.SetFinalizers(nil)
+				Expect(client.IgnoreNotFound(k8sClient.Patch(ctx, record, patch))).To(Succeed())
+			}
+
+			deleteProvider(ctx, &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: providerNamespacedName.Name, Namespace: providerNamespacedName.Namespace},
+			})
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretNamespacedName.Name, Namespace: secretNamespacedName.Namespace},
+			})).To(Succeed())
+		})
+
+		It("should create the record with the public IP of the Provider", func() {
+			var upserted clients.DNSRecord
+			recordClient.UpsertInterceptor = func(record clients.DNSRecord) {
+				upserted = record
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Second * 123))
+
+			Expect(upserted).To(Equal(clients.DNSRecord{
+				Zone:    "example.com",
+				Name:    "home.example.com",
+				Type:    clients.RecordTypeA,
+				Content: publicIp,
+				TTL:     1,
+			}))
+
+			record := &ddnsv1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			Expect(record.Finalizers).To(ContainElement(ddnsv1alpha1.DNSRecordFinalizer))
+			Expect(record.Status.Value).To(Equal(publicIp))
+			Expect(record.Status.ObservedGeneration).To(Equal(record.GetGeneration()))
+			Expect(meta.IsStatusConditionTrue(record.Status.Conditions, ddnsv1alpha1.DNSRecordConditionTypeProvider)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(record.Status.Conditions, ddnsv1alpha1.DNSRecordConditionTypeSynced)).To(BeTrue())
+		})
+
+		It("should not update the record if it is in sync", func() {
+			recordClient.Record = &clients.DNSRecord{
+				Zone:    "example.com",
+				Name:    "home.example.com",
+				Type:    clients.RecordTypeA,
+				Content: publicIp,
+				TTL:     1,
+			}
+			recordClient.UpsertInterceptor = func(record clients.DNSRecord) {
+				Fail("UpsertRecord should not be called")
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the Provider does not exist", func() {
+			record := &ddnsv1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			record.Spec.ProviderRef.Name = "missing-provider"
+			Expect(k8sClient.Update(ctx, record)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(record.Status.Conditions, ddnsv1alpha1.DNSRecordConditionTypeProvider)).To(BeTrue())
+		})
+
+		It("should not use a ClusterProvider if the controller is namespace scoped", func() {
+			record := &ddnsv1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			record.Spec.ProviderRef.Kind = "ClusterProvider"
+			Expect(k8sClient.Update(ctx, record)).To(Succeed())
+
+			controllerReconciler.NamespaceScoped = true
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			condition := meta.FindStatusCondition(record.Status.Conditions, ddnsv1alpha1.DNSRecordConditionTypeProvider)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("the controller only watches some namespaces"))
+		})
+
+		It("should fail if the provider client cannot manage single records", func() {
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{}, nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			record := &ddnsv1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(record.Status.Conditions, ddnsv1alpha1.DNSRecordConditionTypeSynced)).To(BeTrue())
+		})
+
+		It("should fail if the record cannot be updated", func() {
+			recordClient.UpsertError = fmt.Errorf("upsert error")
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			record := &ddnsv1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			condition := meta.FindStatusCondition(record.Status.Conditions, ddnsv1alpha1.DNSRecordConditionTypeSynced)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("upsert error"))
+		})
+
+		It("should delete the record from the provider when deleted", func() {
+			var deleted clients.DNSRecord
+			recordClient.DeleteRecordInterceptor = func(record clients.DNSRecord) {
+				deleted = record
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			record := &ddnsv1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, record)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted.Name).To(Equal("home.example.com"))
+
+			err = k8sClient.Get(ctx, recordNamespacedName, record)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should leave the record at the provider when deleted with the Orphan policy", func() {
+			recordClient.DeleteRecordInterceptor = func(record clients.DNSRecord) {
+				Fail("DeleteRecord should not be called with the Orphan policy")
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			record := &ddnsv1alpha1.DNSRecord{}
+			Expect(k8sClient.Get(ctx, recordNamespacedName, record)).To(Succeed())
+			record.Spec.DeletionPolicy = ddnsv1alpha1.DeletionPolicyOrphan
+			Expect(k8sClient.Update(ctx, record)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, record)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: recordNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, recordNamespacedName, record)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// ProvidersChecker returns a health check that fails if all the Providers and ClusterProviders that are not suspended
+// have been failing for longer than the threshold, e.g. because the egress of the controller is blocked.
+// A single broken Provider does not fail it, neither does having no Providers at all. ClusterProviders are left out if namespaceScoped
+func ProvidersChecker(reader client.Reader, threshold time.Duration, namespaceScoped bool) healthz.Checker {
+	return func(req *http.Request) error {
+		providers, err := listProviders(req.Context(), reader, namespaceScoped)
+		if err != nil {
+			return err
+		}
+
+		return providersHealthy(providers, threshold, time.Now())
+	}
+}
+
+// Provider states served by the ProvidersStatusHandler
+const (
+	ProviderStateSynced    = "Synced"
+	ProviderStateFailing   = "Failing"
+	ProviderStatePending   = "Pending"
+	ProviderStateSuspended = "Suspended"
+)
+
+// ProviderState is the sync state of a Provider or ClusterProvider, as served by the ProvidersStatusHandler
+type ProviderState struct {
+	Kind                string       `json:"kind"`
+	Namespace           string       `json:"namespace,omitempty"`
+	Name                string       `json:"name"`
+	State               string       `json:"state"`
+	LastSyncTime        *metav1.Time `json:"lastSyncTime,omitempty"`
+	LastError           string       `json:"lastError,omitempty"`
+	ConsecutiveFailures int64        `json:"consecutiveFailures,omitempty"`
+}
+
+// ProvidersStatus is the body served by the ProvidersStatusHandler
+type ProvidersStatus struct {
+	Healthy   bool            `json:"healthy"`
+	Providers []ProviderState `json:"providers"`
+}
+
+// ProvidersStatusHandler returns a handler that serves the sync state, last sync time and last error of every Provider
+// and ClusterProvider as JSON, for external uptime monitors that cannot query the Kubernetes API.
+// It responds with 503 Service Unavailable if any of them is failing. ClusterProviders are left out if namespaceScoped
+func ProvidersStatusHandler(reader client.Reader, namespaceScoped bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		providers, err := listProviders(req.Context(), reader, namespaceScoped)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		status := providersStatus(providers)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_ = json.NewEncoder(w).Encode(status)
+	})
+}
+
+// providersStatus returns the state of every provider, ordered by kind, namespace and name. It is healthy as long as
+// none of them is failing
+func providersStatus(providers []ddnsv1alpha1.ProviderObject) ProvidersStatus {
+	status := ProvidersStatus{Healthy: true, Providers: []ProviderState{}}
+
+	for _, provider := range providers {
+		providerStatus := provider.GetProviderStatus()
+		state := ProviderState{
+			Kind:                providerKind(provider),
+			Namespace:           provider.GetNamespace(),
+			Name:                provider.GetName(),
+			State:               ProviderStateSynced,
+			LastSyncTime:        providerStatus.LastSyncTime,
+			LastError:           providerStatus.LastError,
+			ConsecutiveFailures: providerStatus.ConsecutiveFailures,
+		}
+
+		switch {
+		case provider.GetProviderSpec().Suspend:
+			state.State = ProviderStateSuspended
+		case providerStatus.ConsecutiveFailures > 0:
+			state.State = ProviderStateFailing
+			status.Healthy = false
+		case providerStatus.LastSyncTime == nil:
+			state.State = ProviderStatePending
+		}
+
+		status.Providers = append(status.Providers, state)
+	}
+
+	slices.SortFunc(status.Providers, func(a, b ProviderState) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	return status
+}
+
+// listProviders lists the Providers and, unless namespaceScoped, the ClusterProviders
+func listProviders(ctx context.Context, reader client.Reader, namespaceScoped bool) ([]ddnsv1alpha1.ProviderObject, error) {
+	providers := []ddnsv1alpha1.ProviderObject{}
+
+	providerList := &ddnsv1alpha1.ProviderList{}
+	if err := reader.List(ctx, providerList); err != nil {
+		return nil, fmt.Errorf("unable to list Providers: %w", err)
+	}
+
+	for i := range providerList.Items {
+		providers = append(providers, &providerList.Items[i])
+	}
+
+	clusterProviderList := &ddnsv1alpha1.ClusterProviderList{}
+	if !namespaceScoped {
+		if err := reader.List(ctx, clusterProviderList); err != nil {
+			return nil, fmt.Errorf("unable to list ClusterProviders: %w", err)
+		}
+	}
+
+	for i := range clusterProviderList.Items {
+		providers = append(providers, &clusterProviderList.Items[i])
+	}
+
+	return providers, nil
+}
+
+// providersHealthy returns an error if all the providers that are not suspended have been failing for longer than the threshold
+func providersHealthy(providers []ddnsv1alpha1.ProviderObject, threshold time.Duration, now time.Time) error {
+	failing := 0
+
+	for _, provider := range providers {
+		if provider.GetProviderSpec().Suspend {
+			continue
+		}
+
+		since, ok := failingSince(provider)
+		if !ok || now.Sub(since) <= threshold {
+			return nil
+		}
+
+		failing++
+	}
+
+	if failing == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("all %d Providers have been failing for longer than %s", failing, threshold)
+}
+
+// failingSince returns since when the provider has been failing, i.e. its last successful sync or its creation if it never
+// synced. It returns false if the last reconciliation of the provider succeeded
+func failingSince(provider ddnsv1alpha1.ProviderObject) (time.Time, bool) {
+	status := provider.GetProviderStatus()
+	if status.ConsecutiveFailures == 0 {
+		return time.Time{}, false
+	}
+
+	if status.LastSyncTime != nil {
+		return status.LastSyncTime.Time, true
+	}
+
+	return provider.GetCreationTimestamp().Time, true
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+var _ = Describe("Providers health check", func() {
+	now := time.Now()
+
+	provider := func(failures int64, lastSync time.Duration, suspend bool) ddnsv1alpha1.ProviderObject {
+		return &ddnsv1alpha1.Provider{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour))},
+			Spec:       ddnsv1alpha1.ProviderSpec{Suspend: suspend},
+			Status: ddnsv1alpha1.ProviderStatus{
+				ConsecutiveFailures: failures,
+				LastSyncTime:        &metav1.Time{Time: now.Add(-lastSync)},
+			},
+		}
+	}
+
+	It("should be healthy without any Providers", func() {
+		Expect(providersHealthy(nil, time.Hour, now)).To(Succeed())
+	})
+
+	It("should be healthy as long as a single Provider is not failing for longer than the threshold", func() {
+		Expect(providersHealthy([]ddnsv1alpha1.ProviderObject{
+			provider(5, 2*time.Hour, false),
+			provider(1, 10*time.Minute, false),
+		}, time.Hour, now)).To(Succeed())
+
+		Expect(providersHealthy([]ddnsv1alpha1.ProviderObject{
+			provider(5, 2*time.Hour, false),
+			provider(0, 2*time.Hour, false),
+		}, time.Hour, now)).To(Succeed())
+	})
+
+	It("should be unhealthy if all the Providers are failing for longer than the threshold", func() {
+		Expect(providersHealthy([]ddnsv1alpha1.ProviderObject{
+			provider(5, 2*time.Hour, false),
+			provider(3, 90*time.Minute, false),
+			provider(0, 0, true),
+		}, time.Hour, now)).To(MatchError("all 2 Providers have been failing for longer than 1h0m0s"))
+	})
+
+	It("should count the failures of a Provider that never synced from its creation", func() {
+		never := provider(5, 0, false)
+		never.GetProviderStatus().LastSyncTime = nil
+
+		Expect(providersHealthy([]ddnsv1alpha1.ProviderObject{never}, time.Hour, now)).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Providers status", func() {
+	lastSync := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	It("should list the state of every Provider ordered by kind, namespace and name", func() {
+		status := providersStatus([]ddnsv1alpha1.ProviderObject{
+			&ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "synced"},
+				Status:     ddnsv1alpha1.ProviderStatus{LastSyncTime: &lastSync},
+			},
+			&ddnsv1alpha1.ClusterProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "suspended"},
+				Spec:       ddnsv1alpha1.ProviderSpec{Suspend: true},
+				Status:     ddnsv1alpha1.ProviderStatus{ConsecutiveFailures: 1, LastError: "timeout"},
+			},
+			&ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pending"},
+			},
+		})
+
+		Expect(status.Healthy).To(BeTrue())
+		Expect(status.Providers).To(Equal([]ProviderState{
+			{Kind: "ClusterProvider", Name: "suspended", State: ProviderStateSuspended, LastError: "timeout", ConsecutiveFailures: 1},
+			{Kind: "Provider", Namespace: "default", Name: "pending", State: ProviderStatePending},
+			{Kind: "Provider", Namespace: "default", Name: "synced", State: ProviderStateSynced, LastSyncTime: &lastSync},
+		}))
+	})
+
+	It("should be unhealthy if any Provider is failing", func() {
+		status := providersStatus([]ddnsv1alpha1.ProviderObject{
+			&ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "synced"},
+				Status:     ddnsv1alpha1.ProviderStatus{LastSyncTime: &lastSync},
+			},
+			&ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failing"},
+				Status: ddnsv1alpha1.ProviderStatus{
+					LastSyncTime:        &lastSync,
+					LastError:           "unable to reach the provider",
+					ConsecutiveFailures: 3,
+				},
+			},
+		})
+
+		Expect(status.Healthy).To(BeFalse())
+		Expect(status.Providers[0].State).To(Equal(ProviderStateFailing))
+		Expect(status.Providers[0].LastError).To(Equal("unable to reach the provider"))
+	})
+
+	It("should serve an empty list without any Providers", func() {
+		Expect(providersStatus(nil)).To(Equal(ProvidersStatus{Healthy: true, Providers: []ProviderState{}}))
+	})
+})
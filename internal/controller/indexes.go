@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// notifierRefsNameIndex indexes Providers and ClusterProviders by the names of the Notifiers in their notifierRefs
+const notifierRefsNameIndex = ".spec.notifierRefs.name"
+
+// SetupIndexes registers the field indexes that the controllers look resources up by.
+// It must be called once per manager, before the controllers are started. ClusterProviders are not indexed if namespaceScoped
+func SetupIndexes(ctx context.Context, indexer client.FieldIndexer, namespaceScoped bool) error {
+	objs := []client.Object{&ddnsv1alpha1.Provider{}}
+	if !namespaceScoped {
+		objs = append(objs, &ddnsv1alpha1.ClusterProvider{})
+	}
+
+	for _, obj := range objs {
+		if err := indexer.IndexField(ctx, obj, notifierRefsNameIndex, notifierRefNames); err != nil {
+			return fmt.Errorf("unable to index %T by %s: %w", obj, notifierRefsNameIndex, err)
+		}
+	}
+
+	return nil
+}
+
+// notifierRefNames returns the names of the Notifiers and ClusterNotifiers in the notifierRefs of a provider
+func notifierRefNames(obj client.Object) []string {
+	provider, ok := obj.(ddnsv1alpha1.ProviderObject)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(provider.GetProviderSpec().NotifierRefs))
+	for _, ref := range provider.GetProviderSpec().NotifierRefs {
+		names = append(names, ref.Name)
+	}
+
+	return names
+}
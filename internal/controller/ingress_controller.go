@@ -0,0 +1,232 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// IngressReconciler keeps a DNSRecord for every host of the Ingresses with the IngressProviderAnnotation
+type IngressReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder reports misconfigured Ingresses as events. No events are recorded if it is nil
+	Recorder record.EventRecorder
+	// NamespaceScoped leaves out the ClusterProviders, which cannot be read without cluster-wide RBAC
+	NamespaceScoped bool
+	// IngressClasses restricts the Ingresses to the ones of these classes, e.g. the internet-facing one. All Ingresses are
+	// reconciled if it is empty
+	IngressClasses []string
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+}
+
+// ingressClassAnnotation is the deprecated annotation naming the class of an Ingress, still used by some controllers
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses/finalizers,verbs=update
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile creates or updates the DNSRecords of the hosts of the Ingress and deletes the ones it no longer needs
+// The DNSRecords are owned by the Ingress, so they are garbage collected with it
+func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ingress := &networkingv1.Ingress{}
+	if err := r.Get(ctx, req.NamespacedName, ingress); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	desired, err := r.desiredRecords(ctx, ingress)
+	if err != nil {
+		// The records are left as they are until the Ingress is fixed, instead of removing them from the provider
+		log.FromContext(ctx).Error(err, "Ingress is misconfigured, leaving its DNSRecords as they are")
+		r.event(ingress, "InvalidAnnotations", err.Error())
+
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, syncOwnedRecords(ctx, r.Client, r.Scheme, ingress, ddnsv1alpha1.IngressLabel, desired)
+}
+
+// =================================================== PRIVATE FUNCTIONS ===================================================
+
+// desiredRecords returns the DNSRecords the Ingress needs: one for every host in its rules and every IP family managed by
+// the Provider it names. Ingresses without the IngressProviderAnnotation, or of another class than the IngressClasses,
+// need none
+func (r *IngressReconciler) desiredRecords(ctx context.Context, ingress *networkingv1.Ingress) ([]ddnsv1alpha1.DNSRecord, error) {
+	if len(r.IngressClasses) > 0 {
+		class, err := r.ingressClass(ctx, ingress)
+		if err != nil {
+			return nil, err
+		}
+
+		// The DNSRecords of an Ingress moved to another class are deleted, as if it was not annotated anymore
+		if !slices.Contains(r.IngressClasses, class) {
+			return nil, nil
+		}
+	}
+
+	return publicIPRecords(ctx, r.Client, r.NamespaceScoped, ingress, ddnsv1alpha1.IngressLabel, ingressHosts(ingress))
+}
+
+// ingressClass returns the class of the Ingress: its ingressClassName, the deprecated ingressClassAnnotation, or the
+// default IngressClass of the cluster otherwise. IngressClasses are cluster-scoped, so there is no default one if the
+// controller only watches some namespaces
+func (r *IngressReconciler) ingressClass(ctx context.Context, ingress *networkingv1.Ingress) (string, error) {
+	if ingress.Spec.IngressClassName != nil {
+		return *ingress.Spec.IngressClassName, nil
+	}
+
+	if class := ingress.GetAnnotations()[ingressClassAnnotation]; class != "" {
+		return class, nil
+	}
+
+	if r.NamespaceScoped {
+		return "", nil
+	}
+
+	classes := &networkingv1.IngressClassList{}
+	if err := r.List(ctx, classes); err != nil {
+		return "", fmt.Errorf("unable to list IngressClasses: %w", err)
+	}
+
+	for _, class := range classes.Items {
+		if class.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == "true" {
+			return class.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// event records an event on the Ingress, if there is a Recorder
+func (r *IngressReconciler) event(ingress *networkingv1.Ingress, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(ingress, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// ingressRecordTypes returns the types of the records of the hosts: one for every IP family the Provider keeps in sync,
+// restricted to the ones in the IngressRecordTypeAnnotation, if set
+func ingressRecordTypes(annotations map[string]string, ipVersion ddnsv1alpha1.IPVersion) ([]string, error) {
+	recordTypes := []string{}
+	if ipVersion.IPv4Enabled() {
+		recordTypes = append(recordTypes, clients.RecordTypeA)
+	}
+	if ipVersion.IPv6Enabled() {
+		recordTypes = append(recordTypes, clients.RecordTypeAAAA)
+	}
+
+	value, ok := annotations[ddnsv1alpha1.IngressRecordTypeAnnotation]
+	if !ok {
+		return recordTypes, nil
+	}
+
+	requested := []string{}
+	for _, recordType := range strings.Split(value, ",") {
+		recordType = strings.ToUpper(strings.TrimSpace(recordType))
+		if recordType != clients.RecordTypeA && recordType != clients.RecordTypeAAAA {
+			return nil, fmt.Errorf("invalid %s annotation %q, expected A, AAAA or A,AAAA", ddnsv1alpha1.IngressRecordTypeAnnotation, value)
+		}
+
+		if !slices.Contains(recordTypes, recordType) {
+			return nil, fmt.Errorf("%s records are requested, but the Provider does not keep its ipVersion %s in sync",
+				recordType, ipVersion)
+		}
+
+		if !slices.Contains(requested, recordType) {
+			requested = append(requested, recordType)
+		}
+	}
+
+	return requested, nil
+}
+
+// ingressHosts returns the distinct hosts of the rules of the Ingress, in the order they are first seen
+func ingressHosts(ingress *networkingv1.Ingress) []string {
+	hosts := []string{}
+	for _, rule := range ingress.Spec.Rules {
+		host := strings.ToLower(rule.Host)
+		if host != "" && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// ingressesForProvider maps a Provider or ClusterProvider to the Ingresses that name it in their IngressProviderAnnotation,
+// so their records follow a change of its IP families
+// ClusterProviders are not namespaced, so the Ingresses of all namespaces are listed for them
+func (r *IngressReconciler) ingressesForProvider(ctx context.Context, obj client.Object) []reconcile.Request {
+	ingresses := &networkingv1.IngressList{}
+	if err := r.List(ctx, ingresses, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Ingresses")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, ingress := range ingresses.Items {
+		ref, err := annotatedProviderRef(ingress.GetAnnotations()[ddnsv1alpha1.IngressProviderAnnotation])
+		if err != nil || ref.Name == "" {
+			continue
+		}
+
+		if refersTo(ref, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&ingress)})
+		}
+	}
+
+	return requests
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1.Ingress{}).
+		WithOptions(r.ControllerOptions.options()).
+		Owns(&ddnsv1alpha1.DNSRecord{}).
+		Watches(&ddnsv1alpha1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.ingressesForProvider))
+
+	if !r.NamespaceScoped {
+		controllerBuilder = controllerBuilder.Watches(&ddnsv1alpha1.ClusterProvider{}, handler.EnqueueRequestsFromMapFunc(r.ingressesForProvider))
+	}
+
+	return controllerBuilder.Complete(r)
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+var _ = Describe("Ingress Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+		var controllerReconciler *IngressReconciler
+
+		ingressNamespacedName := types.NamespacedName{
+			Name:      "test-ingress",
+			Namespace: "default",
+		}
+
+		providerNamespacedName := types.NamespacedName{
+			Name:      "test-ingress-provider",
+			Namespace: "default",
+		}
+
+		// ingressRecords returns the DNSRecords created for the Ingress
+		ingressRecords := func() []ddnsv1alpha1.DNSRecord {
+			records := &ddnsv1alpha1.DNSRecordList{}
+			Expect(k8sClient.List(ctx, records,
+				client.InNamespace(ingressNamespacedName.Namespace),
+				client.MatchingLabels{ddnsv1alpha1.IngressLabel: ingressNamespacedName.Name},
+			)).To(Succeed())
+
+			return records.Items
+		}
+
+		// updateIngress changes the Ingress with the given function
+		updateIngress := func(update func(ingress *networkingv1.Ingress)) {
+			ingress := &networkingv1.Ingress{}
+			Expect(k8sClient.Get(ctx, ingressNamespacedName, ingress)).To(Succeed())
+			update(ingress)
+			Expect(k8sClient.Update(ctx, ingress)).To(Succeed())
+		}
+
+		reconcileIngress := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: ingressNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			var err error
+
+			By("creating the Provider for the Ingress")
+			err = k8sClient.Get(ctx, providerNamespacedName, &ddnsv1alpha1.Provider{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &ddnsv1alpha1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      providerNamespacedName.Name,
+						Namespace: providerNamespacedName.Namespace,
+					},
+					Spec: ddnsv1alpha1.ProviderSpec{
+						Name:       "Cloudflare",
+						SecretName: "test-ingress-secret",
+						Config: &ddnsv1alpha1.ProviderConfig{
+							Zones: []ddnsv1alpha1.ZoneConfig{{
+								Name:    "example.com",
+								Records: []ddnsv1alpha1.RecordConfig{{Name: "example.com"}},
+							}},
+						},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the annotated Ingress")
+			err = k8sClient.Get(ctx, ingressNamespacedName, &networkingv1.Ingress{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      ingressNamespacedName.Name,
+						Namespace: ingressNamespacedName.Namespace,
+						Labels:    map[string]string{"app": "test"},
+						Annotations: map[string]string{
+							ddnsv1alpha1.IngressProviderAnnotation: providerNamespacedName.Name,
+							ddnsv1alpha1.IngressZoneAnnotation:     "example.com",
+							ddnsv1alpha1.IngressProxiedAnnotation:  "true",
+						},
+					},
+					Spec: networkingv1.IngressSpec{
+						Rules: []networkingv1.IngressRule{
+							{Host: "app.example.com"},
+							{Host: "api.example.com"},
+							{Host: "app.example.com"},
+							{Host: "other.org"},
+						},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			controllerReconciler = &IngressReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the Ingress, its DNSRecords and the Provider")
+			ingress := &networkingv1.Ingress{}
+			if err := k8sClient.Get(ctx, ingressNamespacedName, ingress); err == nil {
+				Expect(k8sClient.Delete(ctx, ingress)).To(Succeed())
+			}
+
+			// There is no garbage collector in the test environment to delete the owned records
+			for _, record := range ingressRecords() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &record))).To(Succeed())
+			}
+
+			deleteProvider(ctx, &ddnsv1alpha1.Provider{ObjectMeta: metav1.ObjectMeta{
+				Name:      providerNamespacedName.Name,
+				Namespace: providerNamespacedName.Namespace,
+			}})
+		})
+
+		It("should create a DNSRecord for every distinct host in the zone", func() {
+			reconcileIngress()
+
+			records := ingressRecords()
+			Expect(records).To(HaveLen(2))
+
+			ingress := &networkingv1.Ingress{}
+			Expect(k8sClient.Get(ctx, ingressNamespacedName, ingress)).To(Succeed())
+
+			hosts := []string{}
+			for _, record := range records {
+				hosts = append(hosts, record.Spec.Name)
+
+				Expect(record.Spec.ProviderRef).To(Equal(ddnsv1alpha1.ProviderRef{
+					Kind: ddnsv1alpha1.ProviderKind,
+					Name: providerNamespacedName.Name,
+				}))
+				Expect(record.Spec.Zone).To(Equal("example.com"))
+				Expect(record.Spec.Type).To(Equal("A"))
+				Expect(record.Spec.Value).To(BeEmpty())
+				Expect(record.Spec.Proxied).To(BeTrue())
+				Expect(record.Labels).To(HaveKeyWithValue("app", "test"))
+				Expect(metav1.IsControlledBy(&record, ingress)).To(BeTrue())
+			}
+
+			Expect(hosts).To(ConsistOf("app.example.com", "api.example.com"))
+		})
+
+		It("should create AAAA records if the Provider manages IPv6", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.IPVersion = ddnsv1alpha1.IPVersionDualStack
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			reconcileIngress()
+
+			recordTypes := []string{}
+			for _, record := range ingressRecords() {
+				recordTypes = append(recordTypes, record.Spec.Type)
+			}
+
+			Expect(recordTypes).To(ConsistOf("A", "A", "AAAA", "AAAA"))
+		})
+
+		It("should delete the DNSRecords of removed hosts", func() {
+			reconcileIngress()
+			Expect(ingressRecords()).To(HaveLen(2))
+
+			updateIngress(func(ingress *networkingv1.Ingress) {
+				ingress.Spec.Rules = []networkingv1.IngressRule{{Host: "app.example.com"}}
+			})
+			reconcileIngress()
+
+			records := ingressRecords()
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Spec.Name).To(Equal("app.example.com"))
+		})
+
+		It("should delete all DNSRecords once the annotation is removed", func() {
+			reconcileIngress()
+			Expect(ingressRecords()).To(HaveLen(2))
+
+			updateIngress(func(ingress *networkingv1.Ingress) {
+				delete(ingress.Annotations, ddnsv1alpha1.IngressProviderAnnotation)
+			})
+			reconcileIngress()
+
+			Expect(ingressRecords()).To(BeEmpty())
+		})
+
+		It("should leave the DNSRecords as they are if the Ingress is misconfigured", func() {
+			reconcileIngress()
+			Expect(ingressRecords()).To(HaveLen(2))
+
+			updateIngress(func(ingress *networkingv1.Ingress) {
+				delete(ingress.Annotations, ddnsv1alpha1.IngressZoneAnnotation)
+			})
+			reconcileIngress()
+
+			Expect(ingressRecords()).To(HaveLen(2))
+		})
+
+		It("should apply the ttl and record-type annotations to the DNSRecords", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.IPVersion = ddnsv1alpha1.IPVersionDualStack
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			updateIngress(func(ingress *networkingv1.Ingress) {
+				ingress.Annotations[ddnsv1alpha1.IngressTTLAnnotation] = "300"
+				ingress.Annotations[ddnsv1alpha1.IngressRecordTypeAnnotation] = "AAAA"
+			})
+			reconcileIngress()
+
+			records := ingressRecords()
+			Expect(records).To(HaveLen(2))
+			for _, record := range records {
+				Expect(record.Spec.Type).To(Equal("AAAA"))
+				Expect(record.Spec.TTL).To(Equal(300))
+			}
+		})
+
+		It("should orphan the records of the DNSRecords with the deletion-policy annotation", func() {
+			updateIngress(func(ingress *networkingv1.Ingress) {
+				ingress.Annotations[ddnsv1alpha1.IngressDeletionPolicyAnnotation] = "Orphan"
+			})
+			reconcileIngress()
+
+			records := ingressRecords()
+			Expect(records).To(HaveLen(2))
+			for _, record := range records {
+				Expect(record.Spec.DeletionPolicy).To(Equal(ddnsv1alpha1.DeletionPolicyOrphan))
+			}
+
+			_, err := annotatedDeletionPolicy(map[string]string{ddnsv1alpha1.IngressDeletionPolicyAnnotation: "Retain"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should only create DNSRecords for the Ingresses of the reconciled classes", func() {
+			controllerReconciler.IngressClasses = []string{"public"}
+
+			updateIngress(func(ingress *networkingv1.Ingress) {
+				ingress.Annotations[ingressClassAnnotation] = "public"
+			})
+			reconcileIngress()
+			Expect(ingressRecords()).To(HaveLen(2))
+
+			updateIngress(func(ingress *networkingv1.Ingress) {
+				ingress.Spec.IngressClassName = ptr.To("internal")
+			})
+			reconcileIngress()
+			Expect(ingressRecords()).To(BeEmpty())
+		})
+
+		It("should reject record types the Provider does not keep in sync", func() {
+			_, err := ingressRecordTypes(map[string]string{ddnsv1alpha1.IngressRecordTypeAnnotation: "AAAA"}, ddnsv1alpha1.IPVersionIPv4)
+			Expect(err).To(MatchError(ContainSubstring("does not keep")))
+
+			_, err = ingressRecordTypes(map[string]string{ddnsv1alpha1.IngressRecordTypeAnnotation: "CNAME"}, ddnsv1alpha1.IPVersionIPv4)
+			Expect(err).To(HaveOccurred())
+
+			_, err = annotatedTTL(map[string]string{ddnsv1alpha1.IngressTTLAnnotation: "0"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should parse the provider annotation", func() {
+			ref, err := annotatedProviderRef("ClusterProvider/cloudflare")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref).To(Equal(ddnsv1alpha1.ProviderRef{Kind: ddnsv1alpha1.ClusterProviderKind, Name: "cloudflare"}))
+
+			_, err = annotatedProviderRef("Secret/cloudflare")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
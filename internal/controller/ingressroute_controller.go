@@ -0,0 +1,151 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// IngressRouteGVK is the kind of the IngressRoutes of Traefik. Its types are not imported, so the controller does not
+// depend on Traefik, and the IngressRoutes are read as unstructured objects
+var IngressRouteGVK = schema.GroupVersionKind{Group: "traefik.io", Version: "v1alpha1", Kind: "IngressRoute"}
+
+var (
+	// ingressRouteHostMatcher matches the Host matchers of a rule, e.g. Host(`app.example.com`, `www.example.com`).
+	// HostHeader is the name of the same matcher in older versions of Traefik. HostRegexp is not matched
+	ingressRouteHostMatcher = regexp.MustCompile(`\bHost(?:Header)?\(([^)]*)\)`)
+	// ingressRouteHostValue matches the quoted hosts in the arguments of a Host matcher
+	ingressRouteHostValue = regexp.MustCompile("[`\"]([^`\"]+)[`\"]")
+)
+
+// IngressRouteReconciler keeps a DNSRecord for every host matched by the routes of the Traefik IngressRoutes with the
+// IngressProviderAnnotation
+type IngressRouteReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder reports misconfigured IngressRoutes as events. No events are recorded if it is nil
+	Recorder record.EventRecorder
+	// NamespaceScoped leaves out the ClusterProviders, which cannot be read without cluster-wide RBAC
+	NamespaceScoped bool
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+}
+
+// +kubebuilder:rbac:groups=traefik.io,resources=ingressroutes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=traefik.io,resources=ingressroutes/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile creates or updates the DNSRecords of the hosts of the IngressRoute and deletes the ones it no longer needs
+// The DNSRecords are owned by the IngressRoute, so they are garbage collected with it
+func (r *IngressRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(IngressRouteGVK)
+	if err := r.Get(ctx, req.NamespacedName, route); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	desired, err := publicIPRecords(ctx, r.Client, r.NamespaceScoped, route, ddnsv1alpha1.IngressRouteLabel, ingressRouteHosts(route))
+	if err != nil {
+		// The records are left as they are until the IngressRoute is fixed, instead of removing them from the provider
+		log.FromContext(ctx).Error(err, "IngressRoute is misconfigured, leaving its DNSRecords as they are")
+		r.event(route, "InvalidAnnotations", err.Error())
+
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, syncOwnedRecords(ctx, r.Client, r.Scheme, route, ddnsv1alpha1.IngressRouteLabel, desired)
+}
+
+// =================================================== PRIVATE FUNCTIONS ===================================================
+
+// event records an event on the IngressRoute, if there is a Recorder
+func (r *IngressRouteReconciler) event(route *unstructured.Unstructured, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(route, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// ingressRouteHosts returns the distinct hosts of the Host matchers in the rules of the routes of the IngressRoute, in
+// the order they are first seen
+func ingressRouteHosts(route *unstructured.Unstructured) []string {
+	routes, _, _ := unstructured.NestedSlice(route.Object, "spec", "routes")
+
+	hosts := []string{}
+	for _, r := range routes {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		match, _, _ := unstructured.NestedString(rule, "match")
+		for _, matcher := range ingressRouteHostMatcher.FindAllStringSubmatch(match, -1) {
+			for _, value := range ingressRouteHostValue.FindAllStringSubmatch(matcher[1], -1) {
+				host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value[1])), ".")
+				if host != "" && !slices.Contains(hosts, host) {
+					hosts = append(hosts, host)
+				}
+			}
+		}
+	}
+
+	return hosts
+}
+
+// ingressRoutesForProvider maps a Provider or ClusterProvider to the IngressRoutes that name it
+func (r *IngressRouteReconciler) ingressRoutesForProvider(ctx context.Context, obj client.Object) []reconcile.Request {
+	return annotatedForProvider(ctx, r.Client, IngressRouteGVK, obj)
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+// The IngressRoute CRD of Traefik has to be installed
+func (r *IngressRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(IngressRouteGVK)
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(route).
+		WithOptions(r.ControllerOptions.options()).
+		Owns(&ddnsv1alpha1.DNSRecord{}).
+		Watches(&ddnsv1alpha1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.ingressRoutesForProvider))
+
+	if !r.NamespaceScoped {
+		controllerBuilder = controllerBuilder.Watches(&ddnsv1alpha1.ClusterProvider{}, handler.EnqueueRequestsFromMapFunc(r.ingressRoutesForProvider))
+	}
+
+	return controllerBuilder.Complete(r)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The IngressRoute CRD of Traefik is not installed in the test environment, so only the hosts read from the rules are tested
+var _ = Describe("IngressRoute Controller", func() {
+	Context("When reading the hosts of an IngressRoute", func() {
+		It("should read the hosts of the Host matchers of all routes", func() {
+			route := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"routes": []interface{}{
+						map[string]interface{}{"match": "Host(`App.example.com`) && PathPrefix(`/api`)"},
+						map[string]interface{}{"match": "Host(`www.example.com`, `app.example.com`) || HostHeader(\"old.example.com\")"},
+						map[string]interface{}{"match": "HostRegexp(`^.+\\.example\\.com$`)"},
+						map[string]interface{}{"match": "PathPrefix(`/`)"},
+					},
+				},
+			}}
+			route.SetGroupVersionKind(IngressRouteGVK)
+
+			Expect(ingressRouteHosts(route)).To(Equal([]string{"app.example.com", "www.example.com", "old.example.com"}))
+		})
+	})
+})
@@ -0,0 +1,163 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// The kinds of Istio whose hosts are kept in sync. Their types are not imported, so the controller does not depend on
+// Istio, and they are read as unstructured objects
+var (
+	IstioGatewayGVK        = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "Gateway"}
+	IstioVirtualServiceGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}
+)
+
+// IstioReconciler keeps a DNSRecord for every host of the Istio Gateways or VirtualServices with the
+// IngressProviderAnnotation, for clusters where Istio terminates the ingress traffic
+type IstioReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder reports misconfigured resources as events. No events are recorded if it is nil
+	Recorder record.EventRecorder
+	// NamespaceScoped leaves out the ClusterProviders, which cannot be read without cluster-wide RBAC
+	NamespaceScoped bool
+	// Kind is the kind reconciled, IstioGatewayGVK or IstioVirtualServiceGVK
+	Kind schema.GroupVersionKind
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+}
+
+// +kubebuilder:rbac:groups=networking.istio.io,resources=gateways;virtualservices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.istio.io,resources=gateways/finalizers;virtualservices/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile creates or updates the DNSRecords of the hosts of the Gateway or VirtualService and deletes the ones it no
+// longer needs. The DNSRecords are owned by the resource, so they are garbage collected with it
+func (r *IstioReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.Kind)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	desired, err := publicIPRecords(ctx, r.Client, r.NamespaceScoped, obj, r.ownerLabel(), istioHosts(obj))
+	if err != nil {
+		// The records are left as they are until the resource is fixed, instead of removing them from the provider
+		log.FromContext(ctx).Error(err, r.Kind.Kind+" is misconfigured, leaving its DNSRecords as they are")
+		r.event(obj, "InvalidAnnotations", err.Error())
+
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, syncOwnedRecords(ctx, r.Client, r.Scheme, obj, r.ownerLabel(), desired)
+}
+
+// =================================================== PRIVATE FUNCTIONS ===================================================
+
+// ownerLabel returns the label set on the DNSRecords of the reconciled kind
+func (r *IstioReconciler) ownerLabel() string {
+	if r.Kind.Kind == IstioGatewayGVK.Kind {
+		return ddnsv1alpha1.IstioGatewayLabel
+	}
+
+	return ddnsv1alpha1.IstioVirtualServiceLabel
+}
+
+// event records an event on the resource, if there is a Recorder
+func (r *IstioReconciler) event(obj *unstructured.Unstructured, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(obj, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// istioHosts returns the distinct hosts of the servers of a Gateway, or of a VirtualService, in the order they are first
+// seen. Wildcards matching every host are skipped, as are the namespaces prefixed to the hosts of Gateways
+func istioHosts(obj *unstructured.Unstructured) []string {
+	hostValues := []string{}
+	if obj.GetKind() == IstioGatewayGVK.Kind {
+		servers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "servers")
+		for _, server := range servers {
+			if server, ok := server.(map[string]interface{}); ok {
+				serverHosts, _, _ := unstructured.NestedStringSlice(server, "hosts")
+				hostValues = append(hostValues, serverHosts...)
+			}
+		}
+	} else {
+		hostValues, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "hosts")
+	}
+
+	hosts := []string{}
+	for _, host := range hostValues {
+		// Hosts of Gateways may name the namespace of the VirtualServices they are bound to, e.g. prod/app.example.com
+		if _, name, found := strings.Cut(host, "/"); found {
+			host = name
+		}
+
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if host != "" && host != "*" && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// resourcesForProvider maps a Provider or ClusterProvider to the Gateways or VirtualServices that name it
+func (r *IstioReconciler) resourcesForProvider(ctx context.Context, obj client.Object) []reconcile.Request {
+	return annotatedForProvider(ctx, r.Client, r.Kind, obj)
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+// The CRD of the Kind has to be installed
+func (r *IstioReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.Kind)
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		Named("istio-"+strings.ToLower(r.Kind.Kind)).
+		For(obj).
+		WithOptions(r.ControllerOptions.options()).
+		Owns(&ddnsv1alpha1.DNSRecord{}).
+		Watches(&ddnsv1alpha1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.resourcesForProvider))
+
+	if !r.NamespaceScoped {
+		controllerBuilder = controllerBuilder.Watches(&ddnsv1alpha1.ClusterProvider{}, handler.EnqueueRequestsFromMapFunc(r.resourcesForProvider))
+	}
+
+	return controllerBuilder.Complete(r)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The CRDs of Istio are not installed in the test environment, so only the hosts read from the resources are tested
+var _ = Describe("Istio Controller", func() {
+	Context("When reading the hosts of a resource", func() {
+		It("should read the hosts of all servers of a Gateway", func() {
+			gateway := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"hosts": []interface{}{"App.example.com", "prod/api.example.com"}},
+						map[string]interface{}{"hosts": []interface{}{"*", "*/app.example.com", "*.example.com."}},
+					},
+				},
+			}}
+			gateway.SetGroupVersionKind(IstioGatewayGVK)
+
+			Expect(istioHosts(gateway)).To(Equal([]string{"app.example.com", "api.example.com", "*.example.com"}))
+		})
+
+		It("should read the hosts of a VirtualService", func() {
+			virtualService := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"hosts":    []interface{}{"reviews", "app.example.com", "*"},
+					"gateways": []interface{}{"istio-system/ingress"},
+				},
+			}}
+			virtualService.SetGroupVersionKind(IstioVirtualServiceGVK)
+
+			Expect(istioHosts(virtualService)).To(Equal([]string{"reviews", "app.example.com"}))
+		})
+	})
+})
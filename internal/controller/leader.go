@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// LeaderElectionNotifier records an event on the leader election Lease when this replica of the controller becomes
+// the leader and when it stops leading, so handovers between the replicas of a deployment can be followed with
+// `kubectl get events`. It is only started once the replica is elected
+type LeaderElectionNotifier struct {
+	Recorder record.EventRecorder
+	// Lease is the leader election Lease the events are recorded on
+	Lease client.Object
+	// Identity identifies the replica in the events, e.g. the name of its Pod
+	Identity string
+}
+
+var _ manager.LeaderElectionRunnable = &LeaderElectionNotifier{}
+
+// NeedLeaderElection makes sure the notifier is only started after the replica became the leader
+func (n *LeaderElectionNotifier) NeedLeaderElection() bool {
+	return true
+}
+
+// Start records that the replica became the leader, then waits until it stops leading, i.e. the controller is stopped
+// or lost the Lease, and records that too. The second event is best effort, as the controller is shutting down by then
+func (n *LeaderElectionNotifier) Start(ctx context.Context) error {
+	log.FromContext(ctx).Info("Became the leader", "identity", n.Identity)
+	n.Recorder.Eventf(n.Lease, corev1.EventTypeNormal, "LeaderElected", "%s became the leader", n.Identity)
+
+	<-ctx.Done()
+
+	log.FromContext(ctx).Info("Stopped leading, finishing the syncs in progress", "identity", n.Identity)
+	n.Recorder.Eventf(n.Lease, corev1.EventTypeNormal, "LeaderStepDown",
+		"%s stopped leading, the syncs in progress are finished before it exits", n.Identity)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Leader election notifier", func() {
+	It("should record an event when becoming the leader and when stepping down", func() {
+		recorder := record.NewFakeRecorder(10)
+		notifier := &LeaderElectionNotifier{
+			Recorder: recorder,
+			Lease:    &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "lease", Namespace: "default"}},
+			Identity: "controller-0",
+		}
+		Expect(notifier.NeedLeaderElection()).To(BeTrue())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- notifier.Start(ctx) }()
+
+		Eventually(recorder.Events).Should(Receive(Equal("Normal LeaderElected controller-0 became the leader")))
+		Consistently(done).ShouldNot(Receive())
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(recorder.Events).To(Receive(ContainSubstring("LeaderStepDown controller-0 stopped leading")))
+	})
+})
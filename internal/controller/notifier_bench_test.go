@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// benchProviders is how many Providers the notifier benchmarks run against. Only a few of them concern the notifier
+const benchProviders = 1000
+
+// benchNotifierReconciler returns a NotifierReconciler reading from the informer cache of a test environment with
+// benchProviders Providers, like the one of the manager. Every 100th Provider references the notifier in its notifierRefs and
+// every 100th, offset by 50, is labeled for its providerSelector. The benchmark is skipped if the test environment cannot
+// be started, e.g. because its binaries were not set up with `make test`
+func benchNotifierReconciler(b *testing.B) *NotifierReconciler {
+	env := newTestEnvironment()
+
+	cfg, err := env.Start()
+	if err != nil {
+		b.Skipf("unable to start the test environment: %s", err)
+	}
+	b.Cleanup(func() { _ = env.Stop() })
+
+	scheme := runtime.NewScheme()
+	if err := ddnsv1alpha1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Cleanup(cancel)
+
+	directClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for i := range benchProviders {
+		provider := &ddnsv1alpha1.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("provider-%d", i), Namespace: "default"},
+			Spec:       ddnsv1alpha1.ProviderSpec{Name: "Cloudflare", SecretName: "cloudflare", ConfigMap: "cloudflare-config"},
+		}
+
+		if i%100 == 0 {
+			provider.Spec.NotifierRefs = []ddnsv1alpha1.ResourceRef{{Name: "notifier"}}
+		}
+
+		if i%100 == 50 {
+			provider.Labels = map[string]string{"team": "a"}
+		}
+
+		if err := directClient.Create(ctx, provider); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	informerCache, err := cache.New(cfg, cache.Options{Scheme: scheme})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := SetupIndexes(ctx, informerCache, false); err != nil {
+		b.Fatal(err)
+	}
+
+	go func() { _ = informerCache.Start(ctx) }()
+
+	cachedClient, err := client.New(cfg, client.Options{Scheme: scheme, Cache: &client.CacheOptions{Reader: informerCache}})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// The informers are started by the first read and the reads block until they synced
+	if err := cachedClient.List(ctx, &ddnsv1alpha1.ProviderList{}); err != nil {
+		b.Fatal(err)
+	}
+
+	if err := cachedClient.List(ctx, &ddnsv1alpha1.ClusterProviderList{}); err != nil {
+		b.Fatal(err)
+	}
+
+	return &NotifierReconciler{Client: cachedClient}
+}
+
+func BenchmarkListProviders(b *testing.B) {
+	r := benchNotifierReconciler(b)
+	ctx := context.Background()
+
+	notifiers := map[string]*ddnsv1alpha1.Notifier{
+		"notifierRefs": {},
+		"refs": {Spec: ddnsv1alpha1.NotifierSpec{ProviderSelector: &ddnsv1alpha1.ProviderSelector{
+			Refs: []ddnsv1alpha1.ProviderRef{{Name: "provider-1"}, {Name: "provider-2"}},
+		}}},
+		"selector": {Spec: ddnsv1alpha1.NotifierSpec{ProviderSelector: &ddnsv1alpha1.ProviderSelector{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		}}},
+	}
+
+	for name, notifier := range notifiers {
+		notifier.Name = "notifier"
+		notifier.Namespace = "default"
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				if _, err := r.listProviders(ctx, notifier); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	// Listing all the Providers of the namespace is what the refs of a providerSelector took before they were looked up
+	b.Run("all", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if err := r.List(ctx, &ddnsv1alpha1.ProviderList{}, client.InNamespace("default")); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
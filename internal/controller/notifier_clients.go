@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+)
+
+// notifierClients caches the clients of the notifiers by UID, as building them may be expensive, e.g. for backends that
+// set up a session. A client is reused until the spec, the Secret or the ConfigMap of its notifier change.
+// A nil notifierClients caches nothing
+type notifierClients struct {
+	mu      sync.Mutex
+	clients map[types.UID]cachedNotifierClient
+}
+
+// cachedNotifierClient is a client together with the name and key of the notifier it was built for
+type cachedNotifierClient struct {
+	name   types.NamespacedName
+	key    string
+	client notifiers.Notifier
+}
+
+// NotifierClientState is a cached notifier client, as dumped for debugging. The client itself is only described by its type
+type NotifierClientState struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Key is the generation of the notifier and the hashes of its Secret and ConfigMap the client was built from
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+// notifierClientKey identifies what a client of the notifier was built from: its spec and the Secret and ConfigMap it read
+func notifierClientKey(notifier ddnsv1alpha1.NotifierObject, observed ddnsv1alpha1.ObservedResources) string {
+	return fmt.Sprintf("%d/%s/%s", notifier.GetGeneration(), observed.SecretHash, observed.ConfigMapHash)
+}
+
+// get returns the client cached for the notifier, if it was built for the same key
+func (c *notifierClients) get(uid types.UID, key string) (notifiers.Notifier, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.clients[uid]
+	if !ok || cached.key != key {
+		return nil, false
+	}
+
+	return cached.client, true
+}
+
+// set caches the client of the notifier, closing the client it replaces
+func (c *notifierClients) set(uid types.UID, name types.NamespacedName, key string, client notifiers.Notifier) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.clients == nil {
+		c.clients = map[types.UID]cachedNotifierClient{}
+	}
+
+	if cached, ok := c.clients[uid]; ok {
+		closeNotifierClient(cached.client)
+	}
+
+	c.clients[uid] = cachedNotifierClient{name: name, key: key, client: client}
+}
+
+// states describes the cached clients, ordered by the namespace and name of their notifiers
+func (c *notifierClients) states() []NotifierClientState {
+	states := []NotifierClientState{}
+	if c == nil {
+		return states
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, cached := range c.clients {
+		states = append(states, NotifierClientState{
+			Namespace: cached.name.Namespace,
+			Name:      cached.name.Name,
+			Key:       cached.key,
+			Type:      fmt.Sprintf("%T", cached.client),
+		})
+	}
+
+	slices.SortFunc(states, func(a, b NotifierClientState) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	return states
+}
+
+// delete closes and forgets the client of a deleted notifier
+func (c *notifierClients) delete(uid types.UID) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.clients[uid]; ok {
+		closeNotifierClient(cached.client)
+		delete(c.clients, uid)
+	}
+}
+
+// closeNotifierClient closes the client, if it holds a session or connection that has to be closed
+func closeNotifierClient(client notifiers.Notifier) {
+	if closer, ok := client.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// CachedClients describes the notifier clients cached by the reconciler, without their credentials
+func (r *NotifierReconciler) CachedClients() []NotifierClientState {
+	return r.clients.states()
+}
+
+// notifierClient returns the cached client of the notifier, or builds a new one with the NotifierFactory if the notifier,
+// its Secret or its ConfigMap changed since it was built. A new client logs with the logger of the context
+func (r *NotifierReconciler) notifierClient(
+	ctx context.Context,
+	notifier ddnsv1alpha1.NotifierObject,
+	secret *corev1.Secret,
+	configMap *corev1.ConfigMap,
+	observed ddnsv1alpha1.ObservedResources,
+) (notifiers.Notifier, error) {
+	key := notifierClientKey(notifier, observed)
+	if client, ok := r.clients.get(notifier.GetUID(), key); ok {
+		return client, nil
+	}
+
+	client, err := r.NotifierFactory(notifier, secret, configMap, log.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	r.clients.set(notifier.GetUID(), types.NamespacedName{Namespace: notifier.GetNamespace(), Name: notifier.GetName()}, key, client)
+
+	return client, nil
+}
@@ -20,12 +20,19 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -34,13 +41,31 @@ import (
 	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
 	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
 	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/tracing"
 )
 
 // NotifierReconciler reconciles a Notifier object
 type NotifierReconciler struct {
 	client.Client
 	Scheme          *runtime.Scheme
-	NotifierFactory func(notifier *ddnsv1alpha1.Notifier, secret *corev1.Secret, configMap *corev1.ConfigMap) (notifiers.Notifier, error)
+	NotifierFactory func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error)
+	// ClusterResourceNamespace is the namespace that Notifiers referenced by ClusterProviders are looked up in
+	ClusterResourceNamespace string
+	// AllowCrossNamespaceRefs allows notifierRefs to point to Notifiers in other namespaces than the one of the provider
+	AllowCrossNamespaceRefs bool
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+	// NamespaceScoped leaves out the ClusterProviders, which cannot be read without cluster-wide RBAC
+	NamespaceScoped bool
+	// MaxConcurrentNotifications is how many notifications are delivered at once, so one slow delivery does not hold up
+	// the notifications about the other providers. Defaults to defaultMaxConcurrentNotifications if not positive
+	MaxConcurrentNotifications int
+	// Recorder records events for the notifiers that are not ready and the notifications that could not be delivered.
+	// Optional
+	Recorder record.EventRecorder
+
+	// clients caches the clients built with the NotifierFactory. Clients are built for every reconciliation if it is nil
+	clients *notifierClients
 }
 
 // +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=notifiers,verbs=get;list;watch;create;update;patch;delete
@@ -48,7 +73,9 @@ type NotifierReconciler struct {
 // +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=notifiers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
-// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile will reconcile the Notifier object
 func (r *NotifierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -58,50 +85,376 @@ func (r *NotifierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	return r.reconcileNotifier(ctx, req.Namespace, notifier)
+}
+
+// reconcileNotifier sends notifications to a Notifier or ClusterNotifier for the providers that reference it
+// The secret and config map of the notifier are read from the given namespace
+func (r *NotifierReconciler) reconcileNotifier(
+	ctx context.Context,
+	namespace string,
+	notifier ddnsv1alpha1.NotifierObject,
+) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "Notifier.Reconcile", tracing.ObjectAttributes(notifierKind(notifier), notifier)...)
+	defer span.End()
+
+	if !notifier.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, notifier)
+	}
+
+	if err := r.ensureFinalizer(ctx, notifier); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if notifier.GetNotifierSpec().Suspend {
+		log.FromContext(ctx).Info("Notifier is suspended, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
 	_ = notifier.Conditions().FillConditions()
 
-	notifierClient, err := r.fetchNotifier(ctx, req, notifier)
+	notifierClient, observed, err := r.fetchNotifier(ctx, namespace, notifier)
 	if err != nil {
+		_ = r.patchReady(ctx, notifier, err)
 		return ctrl.Result{}, fmt.Errorf("unable to fetch notifier: %w", err)
 	}
 
-	if !notifier.Status.IsReady {
+	notifierClient = instrumentNotifier(ctx, notifier, notifierClient)
+
+	// A changed Secret or ConfigMap, e.g. a rotated token or a new webhook URL, is validated with a fresh greeting as well
+	if status := notifier.GetNotifierStatus(); !status.IsReady || resourcesChanged(status.ObservedResources, observed) {
+		// A notifier that is not ready is probed with another greeting after a backoff, so it recovers on its own once
+		// e.g. the webhook is back up
 		if err = r.markAsReady(ctx, notifier, notifierClient); err != nil {
-			return ctrl.Result{}, fmt.Errorf("unable to mark Notifier as ready: %w", err)
+			_ = r.patchStatus(ctx, notifier, r.patchGreetingFailed())
+
+			probeAfter := notifierProbeBackoff(notifier.GetNotifierStatus().ConsecutiveFailures)
+			log.FromContext(ctx).Error(err, "Notifier is not ready, probing it again", "after", probeAfter)
+			r.eventf(notifier, corev1.EventTypeWarning, "NotReady", "Could not greet the notifier, probing it again in %s: %s", probeAfter, err)
+
+			_ = r.patchReady(ctx, notifier, fmt.Errorf("%w, probing again in %s", err, probeAfter))
+			return ctrl.Result{RequeueAfter: probeAfter}, nil
+		}
+
+		if err = r.patchReady(ctx, notifier, nil); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// The greeting is not sent again if the notifications fail afterwards
+		if err := r.patchStatus(ctx, notifier, r.patchObservedResources(observed)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to update Notifier status: %w", err)
 		}
 
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	providers := &ddnsv1alpha1.ProviderList{}
-	if err = r.List(ctx, providers); err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to list Providers: %w", err)
+	if err = r.patchReady(ctx, notifier, nil); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	for _, provider := range providers.Items {
-		for _, ref := range provider.Spec.NotifierRefs {
-			if ref.Name == req.Name {
-				if err = r.notifyOfChange(ctx, req, &provider, notifier, notifierClient); err != nil {
-					return ctrl.Result{}, fmt.Errorf("unable to notify of change: %w", err)
-				}
-			}
+	if err = r.sendTest(ctx, notifier, notifierClient); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to send the test message: %w", err)
+	}
+
+	if err = r.retryPendingNotifications(ctx, notifier, notifierClient); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to retry the pending notifications: %w", err)
+	}
+
+	providers, err := r.listProviders(ctx, notifier)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	reported := []ddnsv1alpha1.ProviderObject{}
+	changes := []notification{}
+	for _, provider := range providers {
+		reportsOn, err := r.reportsOn(notifier, provider)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
+
+		if !reportsOn {
+			continue
+		}
+
+		reported = append(reported, provider)
+
+		if change, ok := r.changeNotification(ctx, provider, notifier); ok {
+			changes = append(changes, change)
+		}
+	}
+
+	if err = r.sendNotifications(ctx, notifier, notifierClient, changes); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to notify of change: %w", err)
 	}
 
-	if err := r.patchStatus(ctx, notifier, r.patchObservedGeneration(notifier.GetGeneration())); err != nil {
+	if err := r.patchStatus(ctx, notifier, r.patchAll(
+		r.patchNotifiedProviders(reported, changes),
+		r.patchObservedGeneration(notifier.GetGeneration()),
+		r.patchObservedResources(observed),
+	)); err != nil {
 		return ctrl.Result{}, fmt.Errorf("unable to update Notifier status: %w", err)
 	}
 
-	return ctrl.Result{}, nil
+	set := conditions.PatchConditionsSet(notifier)
+	set.Set(ddnsv1alpha1.NotifierConditionTypeResourcesChanged,
+		resourcesChangedOptions(notifier.GetNotifierStatus().ObservedResources, observed)...,
+	)
+	// The Ready condition also reflects whether the notifications could be delivered
+	set.Set(ddnsv1alpha1.NotifierConditionTypeReady, notifier.Conditions().ReadyOptions(nil)...)
+
+	if err = set.Patch(ctx, r.Client); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: nextNotificationAttempt(notifier.GetNotifierStatus().PendingNotifications)}, nil
 }
 
 // ============================================== PRIVATE FUNCTIONS ==============================================
 
+// eventf records an event on the notifier, if there is a Recorder
+func (r *NotifierReconciler) eventf(notifier ddnsv1alpha1.NotifierObject, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(notifier, eventType, reason, messageFmt, args...)
+	}
+}
+
+// ensureFinalizer adds the finalizer to the Notifier, so its cached client can be closed on deletion
+func (r *NotifierReconciler) ensureFinalizer(ctx context.Context, notifier ddnsv1alpha1.NotifierObject) error {
+	if controllerutil.ContainsFinalizer(notifier, ddnsv1alpha1.NotifierFinalizer) {
+		return nil
+	}
+
+	patch := finalizersPatch(notifier)
+	controllerutil.AddFinalizer(notifier, ddnsv1alpha1.NotifierFinalizer)
+
+	return r.Patch(ctx, notifier, patch)
+}
+
+// finalize cleans up after a deleted Notifier
+// What it reported about the providers is kept in its own status, so only its cached client is left to close
+func (r *NotifierReconciler) finalize(ctx context.Context, notifier ddnsv1alpha1.NotifierObject) error {
+	if !controllerutil.ContainsFinalizer(notifier, ddnsv1alpha1.NotifierFinalizer) {
+		return nil
+	}
+
+	r.clients.delete(notifier.GetUID())
+	deleteNotifierMetrics(notifier)
+
+	patch := finalizersPatch(notifier)
+	controllerutil.RemoveFinalizer(notifier, ddnsv1alpha1.NotifierFinalizer)
+
+	return r.Patch(ctx, notifier, patch)
+}
+
+// listProviders lists the Providers and ClusterProviders the notifier may report on: the ones that reference it by name
+// in their notifierRefs, looked up through the notifierRefsNameIndex, the ones in the refs of its providerSelector and the
+// ones with labels matching its selector. They still have to be checked with reportsOn, as names are not unique across kinds
+// and namespaces
+func (r *NotifierReconciler) listProviders(ctx context.Context, notifier ddnsv1alpha1.NotifierObject) ([]ddnsv1alpha1.ProviderObject, error) {
+	providers := []ddnsv1alpha1.ProviderObject{}
+	seen := map[types.NamespacedName]bool{}
+
+	if err := r.appendProviders(ctx, &providers, seen, client.MatchingFields{notifierRefsNameIndex: notifier.GetName()}); err != nil {
+		return nil, err
+	}
+
+	providerSelector := notifier.GetNotifierSpec().ProviderSelector
+	if providerSelector == nil {
+		return providers, nil
+	}
+
+	add := func(provider ddnsv1alpha1.ProviderObject) {
+		if key := client.ObjectKeyFromObject(provider); !seen[key] {
+			seen[key] = true
+			providers = append(providers, provider)
+		}
+	}
+
+	anyNamespace := false
+	for _, ref := range providerSelector.Refs {
+		// The Providers in the refs of a ClusterNotifier may be in any namespace, so they are listed below
+		if !ref.IsClusterProvider() && notifier.GetNamespace() == "" {
+			anyNamespace = true
+			continue
+		}
+
+		provider, err := r.getSelectedProvider(ctx, notifier, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		if provider != nil {
+			add(provider)
+		}
+	}
+
+	if anyNamespace {
+		providerList := &ddnsv1alpha1.ProviderList{}
+		if err := r.List(ctx, providerList); err != nil {
+			return nil, fmt.Errorf("unable to list Providers: %w", err)
+		}
+
+		for i := range providerList.Items {
+			add(&providerList.Items[i])
+		}
+	}
+
+	if providerSelector.Selector == nil {
+		return providers, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(providerSelector.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid providerSelector: %w", err)
+	}
+
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}, client.InNamespace(notifier.GetNamespace())}
+	if err := r.appendProviders(ctx, &providers, seen, opts...); err != nil {
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+// getSelectedProvider gets the Provider in the namespace of the notifier or the ClusterProvider in the refs of its
+// providerSelector, or nil if it does not exist. ClusterProviders are not looked up if the controller is namespaceScoped
+func (r *NotifierReconciler) getSelectedProvider(
+	ctx context.Context,
+	notifier ddnsv1alpha1.NotifierObject,
+	ref ddnsv1alpha1.ProviderRef,
+) (ddnsv1alpha1.ProviderObject, error) {
+	var provider ddnsv1alpha1.ProviderObject = &ddnsv1alpha1.Provider{}
+	kind := ddnsv1alpha1.ProviderKind
+	key := types.NamespacedName{Namespace: notifier.GetNamespace(), Name: ref.Name}
+
+	if ref.IsClusterProvider() {
+		if r.NamespaceScoped {
+			return nil, nil
+		}
+
+		provider, kind, key.Namespace = &ddnsv1alpha1.ClusterProvider{}, ddnsv1alpha1.ClusterProviderKind, ""
+	}
+
+	if err := r.Get(ctx, key, provider); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to get %s %s: %w", kind, ref.Name, err)
+	}
+
+	return provider, nil
+}
+
+// appendProviders lists both the Providers and the ClusterProviders with the given options and appends the ones not seen yet
+// The namespace of the options is ignored for ClusterProviders
+func (r *NotifierReconciler) appendProviders(
+	ctx context.Context,
+	providers *[]ddnsv1alpha1.ProviderObject,
+	seen map[types.NamespacedName]bool,
+	opts ...client.ListOption,
+) error {
+	providerList := &ddnsv1alpha1.ProviderList{}
+	if err := r.List(ctx, providerList, opts...); err != nil {
+		return fmt.Errorf("unable to list Providers: %w", err)
+	}
+
+	clusterOpts := append([]client.ListOption{}, opts...)
+	clusterOpts = append(clusterOpts, client.InNamespace(""))
+
+	clusterProviderList := &ddnsv1alpha1.ClusterProviderList{}
+	if !r.NamespaceScoped {
+		if err := r.List(ctx, clusterProviderList, clusterOpts...); err != nil {
+			return fmt.Errorf("unable to list ClusterProviders: %w", err)
+		}
+	}
+
+	found := make([]ddnsv1alpha1.ProviderObject, 0, len(providerList.Items)+len(clusterProviderList.Items))
+	for i := range providerList.Items {
+		found = append(found, &providerList.Items[i])
+	}
+	for i := range clusterProviderList.Items {
+		found = append(found, &clusterProviderList.Items[i])
+	}
+
+	for _, provider := range found {
+		if key := client.ObjectKeyFromObject(provider); !seen[key] {
+			seen[key] = true
+			*providers = append(*providers, provider)
+		}
+	}
+
+	return nil
+}
+
+// refersToNotifier returns true if the notifierRef of the provider points to the given Notifier or ClusterNotifier
+func (r *NotifierReconciler) refersToNotifier(
+	ref ddnsv1alpha1.ResourceRef,
+	provider ddnsv1alpha1.ProviderObject,
+	notifier ddnsv1alpha1.NotifierObject,
+) bool {
+	if ref.Name != notifier.GetName() || ref.IsClusterNotifier() != (notifier.GetNamespace() == "") {
+		return false
+	}
+
+	if ref.IsClusterNotifier() {
+		return true
+	}
+
+	namespace, ok := r.notifierNamespace(ref, provider)
+
+	return ok && namespace == notifier.GetNamespace()
+}
+
+// notifierNamespace returns the namespace of the Notifier that the notifierRef of the provider points to
+// Refs without a namespace point to the namespace of the provider, or the ClusterResourceNamespace for ClusterProviders.
+// Refs to other namespaces are ignored, unless AllowCrossNamespaceRefs is set.
+func (r *NotifierReconciler) notifierNamespace(ref ddnsv1alpha1.ResourceRef, provider ddnsv1alpha1.ProviderObject) (string, bool) {
+	namespace := resourceNamespace(provider, r.ClusterResourceNamespace)
+	if ref.Namespace == "" || ref.Namespace == namespace {
+		return namespace, true
+	}
+
+	return ref.Namespace, r.AllowCrossNamespaceRefs
+}
+
+// reportsOn returns true if the provider references the notifier in its notifierRefs,
+// or if the providerSelector of the notifier selects the provider
+func (r *NotifierReconciler) reportsOn(notifier ddnsv1alpha1.NotifierObject, provider ddnsv1alpha1.ProviderObject) (bool, error) {
+	for _, ref := range provider.GetProviderSpec().NotifierRefs {
+		if r.refersToNotifier(ref, provider, notifier) {
+			return true, nil
+		}
+	}
+
+	return selectsProvider(notifier, provider)
+}
+
+// degradedValue is the value recorded for a provider once the notifier was notified that the provider is Degraded
+const degradedValue = "Degraded"
+
+// legacyNotifierAnnotation is the annotation on the provider that held the last IP the notifier was notified of, or the
+// degradedValue, before it was recorded in the NotifiedProviders of the notifier. It is only read, so upgrading does not
+// send the notifications again
+func (r *NotifierReconciler) legacyNotifierAnnotation(notifier ddnsv1alpha1.NotifierObject) string {
+	if notifier.GetNamespace() == "" {
+		return fmt.Sprintf("%s/clusternotifier.%s", ddnsv1alpha1.GroupVersion.Group, notifier.GetName())
+	}
+
+	return fmt.Sprintf("%s/%s_%s", ddnsv1alpha1.GroupVersion.Group, notifier.GetName(), notifier.GetNamespace())
+}
+
+// patchReady sets the Ready condition of the Notifier from the other conditions and the error of the reconciliation, if any
+func (r *NotifierReconciler) patchReady(ctx context.Context, notifier ddnsv1alpha1.NotifierObject, err error) error {
+	return conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeReady, notifier.Conditions().ReadyOptions(err)...)
+}
+
 // markAsReady marks the Notifier as ready
 // Ready means that the Notifier has been successfully created and a greeting message has been sent
 func (r *NotifierReconciler) markAsReady(
 	ctx context.Context,
-	notifier *ddnsv1alpha1.Notifier,
+	notifier ddnsv1alpha1.NotifierObject,
 	notifierClient notifiers.Notifier,
 ) (err error) {
 	condOptions := []conditions.ConditionOption{}
@@ -132,99 +485,128 @@ func (r *NotifierReconciler) markAsReady(
 	return nil
 }
 
-// notifyOfChange sends a notification to the notifierClient
-// We need to first update the annotation of the Provider with the new IP, then send the notification
-// this is done to avoid issues with the resouceVersion of the Provider object
-func (r *NotifierReconciler) notifyOfChange(
+// sendTest sends a test message if a new value of the TestAnnotation was set and reports the result in the Tested condition.
+// The value is marked as handled before sending, so a failed test is not repeated until the annotation is changed again
+func (r *NotifierReconciler) sendTest(
 	ctx context.Context,
-	req ctrl.Request,
-	provider *ddnsv1alpha1.Provider,
-	notifier *ddnsv1alpha1.Notifier,
+	notifier ddnsv1alpha1.NotifierObject,
 	notifierClient notifiers.Notifier,
 ) error {
-	log := log.FromContext(ctx)
-	annotation := fmt.Sprintf("%s/%s_%s", ddnsv1alpha1.GroupVersion.Group, req.Name, req.Namespace)
-	if value, ok := provider.Annotations[annotation]; ok && value == provider.Status.ProviderIP {
-		log.Info("Provider IP has not changed", "IP", provider.Status.ProviderIP)
+	value := notifier.GetAnnotations()[ddnsv1alpha1.TestAnnotation]
+	if value == "" || value == notifier.GetNotifierStatus().LastHandledTest {
 		return nil
 	}
 
-	if provider.Status.ProviderIP == "" {
-		log.Info("Provider IP is empty")
-		return nil
+	if err := r.patchStatus(ctx, notifier, r.patchLastHandledTest(value)); err != nil {
+		return err
 	}
 
-	log.Info("Provider IP changed", "IP", provider.Status.ProviderIP)
+	log.FromContext(ctx).Info("Sending a test message")
 
-	var message string
+	err := notifierClient.SendNotification(fmt.Sprintf("Test message. From notifier: (%s).", notifier.GetName()))
+	if err := r.patchStatus(ctx, notifier, r.patchDeliveries(deliveries(err))); err != nil {
+		return err
+	}
 
-	if provider.Status.ProviderIP == provider.Status.PublicIP {
-		message = fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", provider.Status.ProviderIP, provider.Name)
-	} else {
-		message = fmt.Sprintf("Provider IP (%s) out of sync with Public IP (%s). From provider: (%s).", provider.Status.ProviderIP, provider.Status.PublicIP, provider.Name)
+	if err != nil {
+		return conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeTested,
+			conditions.WithReasonAndMessage("TestFailed", fmt.Sprintf("unable to send the test message: %s", err)),
+			conditions.False(),
+		)
 	}
 
-	if err := notifierClient.SendNotification(message); err != nil {
-		log.Error(err, "unable to send notification")
+	return conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeTested,
+		conditions.WithReasonAndMessage("TestSent", fmt.Sprintf("Test message for %q sent", value)),
+		conditions.True(),
+	)
+}
 
-		if err := r.patchStatus(ctx, notifier, r.patchIsReady(false)); err != nil {
-			log.Error(err, "unable to mark Notifier as not ready")
+// changeNotification returns the notification about the provider if its IP changed since the notifier was last notified
+// If the notifier has NotifyOnDegraded set, a Degraded provider is reported once instead, until it recovers
+func (r *NotifierReconciler) changeNotification(
+	ctx context.Context,
+	provider ddnsv1alpha1.ProviderObject,
+	notifier ddnsv1alpha1.NotifierObject,
+) (notification, bool) {
+	log := log.FromContext(ctx).WithValues("provider", provider.GetName())
+	notified, ok := r.notifiedValue(notifier, provider)
+	// A notification that is still pending is retried, so it is not queued again
+	if pending, queued := pendingValue(notifier, provider); queued {
+		notified, ok = pending, true
+	}
+	status := provider.GetProviderStatus()
+
+	degraded := meta.FindStatusCondition(status.Conditions, ddnsv1alpha1.ProviderConditionTypeDegraded)
+	if notifier.GetNotifierSpec().NotifyOnDegraded && degraded != nil && degraded.Status == metav1.ConditionTrue {
+		if ok && notified == degradedValue {
+			log.Info("Provider is still degraded")
+			return notification{}, false
 		}
 
-		conditions.PatchConditions(
-			ctx,
-			r.Client,
-			notifier,
-			ddnsv1alpha1.NotifierConditionTypeClient,
-			conditions.False(),
-			conditions.WithReasonAndMessage("ClientCommunication", fmt.Sprintf("unable to send notification: %s", err)),
-		)
+		log.Info("Provider degraded", "reason", degraded.Message)
 
-		return err
+		return notification{
+			provider: provider,
+			value:    degradedValue,
+			message:  fmt.Sprintf("Provider (%s) is degraded: %s.", provider.GetName(), degraded.Message),
+		}, true
 	}
 
-	conditions.PatchConditions(
-		ctx,
-		r.Client,
-		notifier,
-		ddnsv1alpha1.NotifierConditionTypeClient,
-		conditions.WithReasonAndMessage("ClientCommunication", "Notification sent"),
-		conditions.True(),
-	)
+	if ok && notified == status.ProviderIP {
+		log.Info("Provider IP has not changed", "IP", status.ProviderIP)
+		return notification{}, false
+	}
 
-	patch := client.MergeFrom(provider.DeepCopy())
-	if provider.Annotations == nil {
-		provider.Annotations = make(map[string]string)
+	if status.ProviderIP == "" {
+		log.Info("Provider IP is empty")
+		return notification{}, false
 	}
-	provider.Annotations[annotation] = provider.Status.ProviderIP
 
-	if err := r.Patch(ctx, provider, patch); err != nil {
-		return err
+	log.Info("Provider IP changed", "IP", status.ProviderIP)
+
+	var message string
+
+	if status.ProviderIP == status.PublicIP {
+		message = fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", status.ProviderIP, provider.GetName())
+	} else {
+		message = fmt.Sprintf("Provider IP (%s) out of sync with Public IP (%s). From provider: (%s).", status.ProviderIP, status.PublicIP, provider.GetName())
 	}
 
-	return nil
+	return notification{provider: provider, value: status.ProviderIP, message: message}, true
 }
 
+// fetchNotifier builds the client of the Notifier from its secret and config map
+// It also returns the observed resources and reports in the ResourcesChanged condition if they changed since the last successful reconciliation
+// The ConfigMap, Secret, ResourcesChanged and Client conditions are patched at once when it returns
 func (r *NotifierReconciler) fetchNotifier(
 	ctx context.Context,
-	req ctrl.Request,
-	notifier *ddnsv1alpha1.Notifier,
-) (notifiers.Notifier, error) {
+	namespace string,
+	notifier ddnsv1alpha1.NotifierObject,
+) (notifiers.Notifier, ddnsv1alpha1.ObservedResources, error) {
 	var err error
 
-	configMap, err := r.fetchConfig(ctx, req, notifier)
+	set := conditions.PatchConditionsSet(notifier)
+	defer func() { _ = set.Patch(ctx, r.Client) }()
+
+	configMap, err := r.fetchConfig(ctx, namespace, notifier, set)
 	if err != nil {
-		return nil, fmt.Errorf("unable to fetch ConfigMap: %w", err)
+		return nil, ddnsv1alpha1.ObservedResources{}, fmt.Errorf("unable to fetch ConfigMap: %w", err)
 	}
 
-	secret, err := r.fetchSecret(ctx, req, notifier)
+	secret, err := r.fetchSecret(ctx, namespace, notifier, set)
 	if err != nil {
-		return nil, fmt.Errorf("unable to fetch Secret: %w", err)
+		return nil, ddnsv1alpha1.ObservedResources{}, fmt.Errorf("unable to fetch Secret: %w", err)
+	}
+
+	observed := observedResources(secret, configMap)
+
+	if last := notifier.GetNotifierStatus().ObservedResources; last != nil {
+		set.Set(ddnsv1alpha1.NotifierConditionTypeResourcesChanged, resourcesChangedOptions(last, observed)...)
 	}
 
 	condOptions := []conditions.ConditionOption{}
 
-	notifierClient, err := r.NotifierFactory(notifier, secret, configMap)
+	notifierClient, err := r.notifierClient(ctx, notifier, secret, configMap, observed)
 	if err != nil {
 		condOptions = append(condOptions,
 			conditions.WithReasonAndMessage("ClientCreated", fmt.Sprintf("could not create client: %s", err)),
@@ -237,15 +619,16 @@ func (r *NotifierReconciler) fetchNotifier(
 		)
 	}
 
-	conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeClient, condOptions...)
+	set.Set(ddnsv1alpha1.NotifierConditionTypeClient, condOptions...)
 
-	return notifierClient, err
+	return notifierClient, observed, err
 }
 
 func (r *NotifierReconciler) fetchConfig(
 	ctx context.Context,
-	req ctrl.Request,
-	notifier *ddnsv1alpha1.Notifier,
+	namespace string,
+	notifier ddnsv1alpha1.NotifierObject,
+	set *conditions.ConditionsSet,
 ) (*corev1.ConfigMap, error) {
 	var (
 		configMap *corev1.ConfigMap
@@ -255,27 +638,28 @@ func (r *NotifierReconciler) fetchConfig(
 	condOptions := []conditions.ConditionOption{}
 
 	configMap = &corev1.ConfigMap{}
-	if err = r.Get(ctx, types.NamespacedName{Name: notifier.Spec.ConfigMap, Namespace: req.Namespace}, configMap); err != nil {
+	if err = r.Get(ctx, types.NamespacedName{Name: notifier.GetNotifierSpec().ConfigMap, Namespace: namespace}, configMap); err != nil {
 		condOptions = append(condOptions,
 			conditions.WithReasonAndMessage("ConfigMapFound", err.Error()),
 			conditions.False(),
 		)
 	} else {
 		condOptions = append(condOptions,
-			conditions.WithReasonAndMessage("ConfigMapFound", fmt.Sprintf("ConfigMap %s found", notifier.Spec.ConfigMap)),
+			conditions.WithReasonAndMessage("ConfigMapFound", fmt.Sprintf("ConfigMap %s found", notifier.GetNotifierSpec().ConfigMap)),
 			conditions.True(),
 		)
 	}
 
-	conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeConfigMap, condOptions...)
+	set.Set(ddnsv1alpha1.NotifierConditionTypeConfigMap, condOptions...)
 
 	return configMap, err
 }
 
 func (r *NotifierReconciler) fetchSecret(
 	ctx context.Context,
-	req ctrl.Request,
-	notifier *ddnsv1alpha1.Notifier,
+	namespace string,
+	notifier ddnsv1alpha1.NotifierObject,
+	set *conditions.ConditionsSet,
 ) (*corev1.Secret, error) {
 	var (
 		err    error
@@ -284,34 +668,35 @@ func (r *NotifierReconciler) fetchSecret(
 
 	condOptions := []conditions.ConditionOption{}
 
+	secretRef := notifier.GetNotifierSpec().GetSecretRef()
+
 	secret = &corev1.Secret{}
-	if err = r.Get(ctx, types.NamespacedName{Name: notifier.Spec.SecretName, Namespace: req.Namespace}, secret); err != nil {
+	if err = r.Get(ctx, types.NamespacedName{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
 		condOptions = append(condOptions,
 			conditions.WithReasonAndMessage("SecretFound", err.Error()),
 			conditions.False(),
 		)
 	} else {
 		condOptions = append(condOptions,
-			conditions.WithReasonAndMessage("SecretFound", fmt.Sprintf("Secret %s found", notifier.Spec.SecretName)),
+			conditions.WithReasonAndMessage("SecretFound", fmt.Sprintf("Secret %s found", secretRef.Name)),
 			conditions.True(),
 		)
+
+		secret = mapSecretKeys(secret, secretRef)
 	}
 
-	conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeSecret, condOptions...)
+	set.Set(ddnsv1alpha1.NotifierConditionTypeSecret, condOptions...)
 
 	return secret, nil
 }
 
 func (r *NotifierReconciler) patchStatus(
 	ctx context.Context,
-	notifier *ddnsv1alpha1.Notifier,
-	apply func(notifier *ddnsv1alpha1.Notifier) bool,
+	notifier ddnsv1alpha1.NotifierObject,
+	apply func(notifier ddnsv1alpha1.NotifierObject) bool,
 ) error {
-	patch := client.MergeFrom(notifier.DeepCopy())
 	if apply(notifier) {
-		if err := r.Status().Patch(ctx, notifier, patch); err != nil {
-			return err
-		}
+		return conditions.ApplyStatus(ctx, r.Client, notifier)
 	}
 
 	return nil
@@ -321,56 +706,229 @@ func (r *NotifierReconciler) patchStatus(
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *NotifierReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	r.clients = &notifierClients{}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&ddnsv1alpha1.Notifier{}).
+		WithOptions(r.ControllerOptions.options()).
 		Watches(
 			&ddnsv1alpha1.Provider{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForProvider),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
-		Complete(r)
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.notifiersForResource)).
+		WatchesMetadata(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.notifiersForResource))
+
+	if !r.NamespaceScoped {
+		controllerBuilder = controllerBuilder.Watches(
+			&ddnsv1alpha1.ClusterProvider{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForProvider),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+// notifiersForResource returns a list of requests for the Notifiers in the namespace of the Secret or ConfigMap that read it,
+// so changes to the credentials or config take effect immediately
+func (r *NotifierReconciler) notifiersForResource(ctx context.Context, obj client.Object) []reconcile.Request {
+	notifiers := &ddnsv1alpha1.NotifierList{}
+	if err := r.List(ctx, notifiers, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Notifiers")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, notifier := range notifiers.Items {
+		if notifierUsesResource(&notifier.Spec, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&notifier)})
+		}
+	}
+
+	return requests
 }
 
 // findObjectsForProvider returns a list of requests for Notifiers that are referenced by Providers
 // providers have a `.spec.notifierRefs.*` field that references a Notifier
+// Notifiers referenced by ClusterProviders are looked up in the ClusterResourceNamespace
+// Notifiers that select the provider with their providerSelector are returned as well
 func (r *NotifierReconciler) findObjectsForProvider(ctx context.Context, provider client.Object) []reconcile.Request {
-	notifierRefs := provider.(*ddnsv1alpha1.Provider).Spec.NotifierRefs
+	namespace := func(ref ddnsv1alpha1.ResourceRef) (string, bool) {
+		return r.notifierNamespace(ref, provider.(ddnsv1alpha1.ProviderObject))
+	}
+
+	notifierList := &ddnsv1alpha1.NotifierList{}
+	if err := r.List(ctx, notifierList, client.InNamespace(provider.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Notifiers")
+		return notifierRequests(provider, false, namespace)
+	}
+
+	notifiers := make([]ddnsv1alpha1.NotifierObject, 0, len(notifierList.Items))
+	for i := range notifierList.Items {
+		notifiers = append(notifiers, &notifierList.Items[i])
+	}
+
+	return append(notifierRequests(provider, false, namespace), selectingNotifierRequests(ctx, provider, notifiers)...)
+}
 
-	requests := make([]reconcile.Request, len(notifierRefs))
+// notifierRequests returns a request for every notifierRef of the provider that points to a ClusterNotifier
+// if cluster is true, or to a Notifier otherwise. The namespace of the request is resolved with the given func,
+// which returns false for refs that should be ignored
+func notifierRequests(
+	provider client.Object,
+	cluster bool,
+	namespace func(ref ddnsv1alpha1.ResourceRef) (string, bool),
+) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	for _, notifierRef := range provider.(ddnsv1alpha1.ProviderObject).GetProviderSpec().NotifierRefs {
+		if notifierRef.IsClusterNotifier() != cluster {
+			continue
+		}
+
+		refNamespace, ok := namespace(notifierRef)
+		if !ok {
+			continue
+		}
 
-	for i, notifierRef := range notifierRefs {
-		requests[i] = reconcile.Request{
+		requests = append(requests, reconcile.Request{
 			NamespacedName: types.NamespacedName{
 				Name:      notifierRef.Name,
-				Namespace: provider.GetNamespace(),
+				Namespace: refNamespace,
 			},
+		})
+	}
+
+	return requests
+}
+
+// selectingNotifierRequests returns a request for every notifier that selects the provider with its providerSelector
+func selectingNotifierRequests(ctx context.Context, provider client.Object, notifiers []ddnsv1alpha1.NotifierObject) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	for _, notifier := range notifiers {
+		selected, err := selectsProvider(notifier, provider.(ddnsv1alpha1.ProviderObject))
+		if err != nil {
+			log.FromContext(ctx).Error(err, "unable to match the providerSelector", "notifier", notifier.GetName())
+			continue
+		}
+
+		if selected {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(notifier)})
 		}
 	}
 
 	return requests
 }
 
+// selectsProvider returns true if the providerSelector of the notifier selects the provider
+// Notifiers only select Providers in their own namespace and ClusterProviders, while ClusterNotifiers select all of them
+func selectsProvider(notifier ddnsv1alpha1.NotifierObject, provider ddnsv1alpha1.ProviderObject) (bool, error) {
+	providerSelector := notifier.GetNotifierSpec().ProviderSelector
+	if providerSelector == nil {
+		return false, nil
+	}
+
+	if notifier.GetNamespace() != "" && provider.GetNamespace() != "" && provider.GetNamespace() != notifier.GetNamespace() {
+		return false, nil
+	}
+
+	for _, ref := range providerSelector.Refs {
+		if refersTo(ref, provider) {
+			return true, nil
+		}
+	}
+
+	if providerSelector.Selector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(providerSelector.Selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid providerSelector: %w", err)
+	}
+
+	return selector.Matches(labels.Set(provider.GetLabels())), nil
+}
+
 // ============================================ PATCH FUNCTIONS ============================================
 
-func (r NotifierReconciler) patchObservedGeneration(observedGeneration int64) func(notifiers *ddnsv1alpha1.Notifier) bool {
-	return func(notifiers *ddnsv1alpha1.Notifier) bool {
-		if notifiers.Status.ObservedGeneration == observedGeneration {
+// patchAll applies all the patches to the notifier, so their changes are written to its status at once
+func (r NotifierReconciler) patchAll(patches ...func(notifiers ddnsv1alpha1.NotifierObject) bool) func(notifiers ddnsv1alpha1.NotifierObject) bool {
+	return func(notifiers ddnsv1alpha1.NotifierObject) bool {
+		changed := false
+		for _, patch := range patches {
+			// Every patch is applied, even once one of them changed the status
+			if patch(notifiers) {
+				changed = true
+			}
+		}
+
+		return changed
+	}
+}
+
+func (r NotifierReconciler) patchObservedGeneration(observedGeneration int64) func(notifiers ddnsv1alpha1.NotifierObject) bool {
+	return func(notifiers ddnsv1alpha1.NotifierObject) bool {
+		if notifiers.GetNotifierStatus().ObservedGeneration == observedGeneration {
+			return false
+		}
+
+		notifiers.GetNotifierStatus().ObservedGeneration = observedGeneration
+
+		return true
+	}
+}
+
+func (r NotifierReconciler) patchObservedResources(observed ddnsv1alpha1.ObservedResources) func(notifiers ddnsv1alpha1.NotifierObject) bool {
+	return func(notifiers ddnsv1alpha1.NotifierObject) bool {
+		status := notifiers.GetNotifierStatus()
+		if status.ObservedResources != nil && *status.ObservedResources == observed {
 			return false
 		}
 
-		notifiers.Status.ObservedGeneration = observedGeneration
+		status.ObservedResources = &observed
 
 		return true
 	}
 }
 
-func (r NotifierReconciler) patchIsReady(isReady bool) func(notifiers *ddnsv1alpha1.Notifier) bool {
-	return func(notifiers *ddnsv1alpha1.Notifier) bool {
-		if notifiers.Status.IsReady == isReady {
+func (r NotifierReconciler) patchLastHandledTest(value string) func(notifiers ddnsv1alpha1.NotifierObject) bool {
+	return func(notifiers ddnsv1alpha1.NotifierObject) bool {
+		if notifiers.GetNotifierStatus().LastHandledTest == value {
 			return false
 		}
 
-		notifiers.Status.IsReady = isReady
+		notifiers.GetNotifierStatus().LastHandledTest = value
+
+		return true
+	}
+}
+
+// patchIsReady sets IsReady, resetting the ConsecutiveFailures once the notifier is ready
+func (r NotifierReconciler) patchIsReady(isReady bool) func(notifiers ddnsv1alpha1.NotifierObject) bool {
+	return func(notifiers ddnsv1alpha1.NotifierObject) bool {
+		status := notifiers.GetNotifierStatus()
+		if status.IsReady == isReady && (!isReady || status.ConsecutiveFailures == 0) {
+			return false
+		}
+
+		status.IsReady = isReady
+		if isReady {
+			status.ConsecutiveFailures = 0
+		}
+
+		return true
+	}
+}
+
+// patchGreetingFailed marks the notifier as not ready and counts the failed greeting in its ConsecutiveFailures
+func (r NotifierReconciler) patchGreetingFailed() func(notifiers ddnsv1alpha1.NotifierObject) bool {
+	return func(notifiers ddnsv1alpha1.NotifierObject) bool {
+		status := notifiers.GetNotifierStatus()
+		status.IsReady = false
+		status.ConsecutiveFailures++
 
 		return true
 	}
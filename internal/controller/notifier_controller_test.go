@@ -19,14 +19,20 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -135,7 +141,7 @@ var _ = Describe("Notifier Controller", func() {
 			controllerNotifierReconciler = &NotifierReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				NotifierFactory: func(notifier *ddnsv1alpha1.Notifier, secret *corev1.Secret, configMap *corev1.ConfigMap) (notifiers.Notifier, error) {
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
 					return &MockNotifier{}, nil
 				},
 			}
@@ -202,7 +208,7 @@ var _ = Describe("Notifier Controller", func() {
 						Name:          "Cloudflare",
 						SecretName:    secretNamespacedName.Name,
 						ConfigMap:     configMapNamespacedName.Name,
-						RetryInterval: 123,
+						RetryInterval: ptr.To(intstr.FromInt32(123)),
 						NotifierRefs: []ddnsv1alpha1.ResourceRef{
 							{
 								Name: notifierNamespacedName.Name,
@@ -221,10 +227,10 @@ var _ = Describe("Notifier Controller", func() {
 			controllerReconciler = &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(test string) (string, error) {
+				IPProvider: func(ctx context.Context, test string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{}, nil
 				},
 			}
@@ -240,7 +246,7 @@ var _ = Describe("Notifier Controller", func() {
 			Expect(k8sClient.Get(ctx, configMapNamespacedName, configMapResource)).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance Provider and related resources")
-			Expect(k8sClient.Delete(ctx, providerResource)).To(Succeed())
+			deleteProvider(ctx, providerResource)
 			Expect(k8sClient.Delete(ctx, secretResource)).To(Succeed())
 			Expect(k8sClient.Delete(ctx, configMapResource)).To(Succeed())
 
@@ -253,7 +259,7 @@ var _ = Describe("Notifier Controller", func() {
 			Expect(k8sClient.Get(ctx, configMapNotifierNamespacedName, configMapNotifierResource)).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance Notifier and related resources")
-			Expect(k8sClient.Delete(ctx, notifierResource)).To(Succeed())
+			deleteNotifier(ctx, notifierResource)
 			Expect(k8sClient.Delete(ctx, secretNotifierResource)).To(Succeed())
 			Expect(k8sClient.Delete(ctx, configMapNotifierResource)).To(Succeed())
 		})
@@ -271,7 +277,7 @@ var _ = Describe("Notifier Controller", func() {
 			err = k8sClient.Get(ctx, notifierNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(resource.Status.Conditions).To(HaveLen(3))
+			Expect(resource.Status.Conditions).To(HaveLen(4))
 			Expect(resource.Status.Conditions[0].Reason).To(Equal("ConfigMapFound"))
 			Expect(resource.Status.Conditions[0].Type).To(Equal("ConfigMap"))
 			Expect(resource.Status.Conditions[0].Message).To(Equal(fmt.Sprintf("ConfigMap %s found", configMapNotifierNamespacedName.Name)))
@@ -281,6 +287,8 @@ var _ = Describe("Notifier Controller", func() {
 			Expect(resource.Status.Conditions[2].Reason).To(Equal("ClientCommunication"))
 			Expect(resource.Status.Conditions[2].Type).To(Equal("Client"))
 			Expect(resource.Status.Conditions[2].Message).To(Equal("Communications established"))
+			Expect(resource.Status.Conditions[3].Type).To(Equal("Ready"))
+			Expect(resource.Status.Conditions[3].Status).To(Equal(metav1.ConditionTrue))
 			Expect(resource.Status.IsReady).To(BeTrue())
 			Expect(int(resource.Status.ObservedGeneration)).To(Equal(0))
 		})
@@ -291,24 +299,25 @@ var _ = Describe("Notifier Controller", func() {
 			controllerNotifierReconciler = &NotifierReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				NotifierFactory: func(notifier *ddnsv1alpha1.Notifier, secret *corev1.Secret, configMap *corev1.ConfigMap) (notifiers.Notifier, error) {
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
 					return &MockNotifier{
 						SendGreetingsError: fmt.Errorf("error sending greetings"),
 					}, nil
 				},
 			}
 
-			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{
+			result, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: notifierNamespacedName,
 			})
-			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(notificationRetryInterval))
 
 			By("Not marking the notifier as ready")
 			resource := &ddnsv1alpha1.Notifier{}
 			err = k8sClient.Get(ctx, notifierNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(resource.Status.Conditions).To(HaveLen(3))
+			Expect(resource.Status.Conditions).To(HaveLen(4))
 			Expect(resource.Status.Conditions[0].Reason).To(Equal("ConfigMapFound"))
 			Expect(resource.Status.Conditions[0].Type).To(Equal("ConfigMap"))
 			Expect(resource.Status.Conditions[0].Message).To(Equal(fmt.Sprintf("ConfigMap %s found", configMapNotifierNamespacedName.Name)))
@@ -319,19 +328,68 @@ var _ = Describe("Notifier Controller", func() {
 			Expect(resource.Status.Conditions[2].Type).To(Equal("Client"))
 			Expect(resource.Status.Conditions[2].Status).To(Equal(metav1.ConditionFalse))
 			Expect(resource.Status.Conditions[2].Message).To(Equal("unable to send greetings: error sending greetings"))
+			Expect(resource.Status.Conditions[3].Type).To(Equal("Ready"))
+			Expect(resource.Status.Conditions[3].Status).To(Equal(metav1.ConditionFalse))
 			Expect(resource.Status.IsReady).NotTo(BeTrue())
+			Expect(resource.Status.ConsecutiveFailures).To(Equal(int64(1)))
 			Expect(int(resource.Status.ObservedGeneration)).To(Equal(0))
 		})
 
+		It("should probe a Notifier that is not ready with greetings until it recovers", func() {
+			greetingErr := fmt.Errorf("webhook is down")
+			greetings := 0
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendGreetingsError:       greetingErr,
+						SendGreetingsInterceptor: func() { greetings++ },
+					}, nil
+				},
+			}
+
+			By("Backing off the probes while the greetings fail")
+			for _, probeAfter := range []time.Duration{notificationRetryInterval, 2 * notificationRetryInterval, 4 * notificationRetryInterval} {
+				result, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(probeAfter))
+			}
+
+			resource := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.IsReady).To(BeFalse())
+			Expect(resource.Status.ConsecutiveFailures).To(Equal(int64(3)))
+
+			ready := meta.FindStatusCondition(resource.Status.Conditions, ddnsv1alpha1.NotifierConditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Message).To(ContainSubstring("probing again in 2m0s"))
+
+			By("Marking the notifier as ready once a greeting is sent")
+			greetingErr = nil
+
+			result, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(greetings).To(Equal(4))
+
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.IsReady).To(BeTrue())
+			Expect(resource.Status.ConsecutiveFailures).To(BeZero())
+		})
+
 		It("should successfully reconcile the resource and not send a notification as the provider is not ready", func() {
 			sendNotificationCounter := 0
+			var mu sync.Mutex
 			By("Creating a custom notifier reconciler")
 			controllerNotifierReconciler = &NotifierReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				NotifierFactory: func(notifier *ddnsv1alpha1.Notifier, secret *corev1.Secret, configMap *corev1.ConfigMap) (notifiers.Notifier, error) {
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
 					return &MockNotifier{
 						SendNotificationInterceptor: func(message any) {
+							mu.Lock()
+							defer mu.Unlock()
 							sendNotificationCounter++
 						},
 					}, nil
@@ -365,7 +423,7 @@ var _ = Describe("Notifier Controller", func() {
 						Name:          "Cloudflare",
 						SecretName:    secretNotifierNamespacedName.Name,
 						ConfigMap:     configMapNamespacedName.Name,
-						RetryInterval: 900,
+						RetryInterval: ptr.To(intstr.FromInt32(900)),
 						NotifierRefs:  []ddnsv1alpha1.ResourceRef{},
 					},
 					Status: ddnsv1alpha1.ProviderStatus{},
@@ -375,7 +433,7 @@ var _ = Describe("Notifier Controller", func() {
 
 				// Cleanup the resource after the test
 				defer func() {
-					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).NotTo(HaveOccurred())
+					deleteProvider(ctx, resource)
 				}()
 			} else {
 				Expect(err).NotTo(HaveOccurred())
@@ -392,13 +450,17 @@ var _ = Describe("Notifier Controller", func() {
 
 		It("should successfully reconcile the resource and send a notification as the provider is ready", func() {
 			sendNotificationCounter := 0
+			var mu sync.Mutex
 			By("Creating a custom notifier reconciler")
 			controllerNotifierReconciler = &NotifierReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				NotifierFactory: func(notifier *ddnsv1alpha1.Notifier, secret *corev1.Secret, configMap *corev1.ConfigMap) (notifiers.Notifier, error) {
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
 					return &MockNotifier{
 						SendNotificationInterceptor: func(message any) {
+							defer GinkgoRecover()
+							mu.Lock()
+							defer mu.Unlock()
 							Expect(message).To(Equal(fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", dummyIp, providerNamespacedName.Name)))
 							sendNotificationCounter++
 						},
@@ -434,15 +496,512 @@ var _ = Describe("Notifier Controller", func() {
 			Expect(sendNotificationCounter).To(Equal(1))
 		})
 
-		It("should successfully reconcile the resource and not send a notification as the provider is ready but there is an error", func() {
+		It("should send a notification once when a Provider becomes Degraded if notifyOnDegraded is set", func() {
+			messages := []any{}
+			var mu sync.Mutex
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendNotificationInterceptor: func(message any) {
+							mu.Lock()
+							defer mu.Unlock()
+							messages = append(messages, message)
+						},
+					}, nil
+				},
+			}
+
+			resource := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			resource.Spec.NotifyOnDegraded = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.FailureThreshold = 1
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Failing the Provider")
+			controllerReconciler.IPProvider = func(ctx context.Context, test string) (string, error) {
+				return "", fmt.Errorf("cannot fetch public IP")
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			for range 2 {
+				_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(messages).To(Equal([]any{
+				fmt.Sprintf("Provider (%s) is degraded: 1 consecutive failures: cannot fetch public IP.", providerNamespacedName.Name),
+			}))
+
+			By("Notifying of the recovery of the Provider")
+			controllerReconciler.IPProvider = func(ctx context.Context, test string) (string, error) {
+				return dummyIp, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messages).To(HaveLen(2))
+			Expect(messages[1]).To(Equal(fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", dummyIp, providerNamespacedName.Name)))
+		})
+
+		It("should send a notification for Providers selected by the providerSelector", func() {
 			sendNotificationCounter := 0
-			By("Creating a custom notifier reconciler")
+			var mu sync.Mutex
+
+			By("Replacing the notifierRefs of the Provider with a label")
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Labels = map[string]string{"notify": "true"}
+			provider.Spec.NotifierRefs = nil
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			By("Selecting the Provider by its label")
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			notifier.Spec.ProviderSelector = &ddnsv1alpha1.ProviderSelector{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"notify": "true"}},
+			}
+			Expect(k8sClient.Update(ctx, notifier)).To(Succeed())
+
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendNotificationInterceptor: func(message any) {
+							mu.Lock()
+							defer mu.Unlock()
+							sendNotificationCounter++
+						},
+					}, nil
+				},
+			}
+
+			Expect(controllerNotifierReconciler.findObjectsForProvider(ctx, provider)).To(ContainElement(
+				reconcile.Request{NamespacedName: notifierNamespacedName},
+			))
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sendNotificationCounter).To(Equal(1))
+
+			By("Not selecting the Provider once the label no longer matches")
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Labels = map[string]string{"notify": "false"}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			Expect(controllerNotifierReconciler.findObjectsForProvider(ctx, provider)).To(BeEmpty())
+		})
+
+		It("should send a notification for Providers referenced in the providerSelector", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.NotifierRefs = nil
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+
+			Expect(selectsProvider(notifier, provider)).To(BeFalse())
+
+			notifier.Spec.ProviderSelector = &ddnsv1alpha1.ProviderSelector{
+				Refs: []ddnsv1alpha1.ProviderRef{{Kind: ddnsv1alpha1.ProviderKind, Name: providerNamespacedName.Name}},
+			}
+
+			Expect(selectsProvider(notifier, provider)).To(BeTrue())
+
+			By("Ignoring Providers in other namespaces")
+			provider.Namespace = "kube-public"
+			Expect(selectsProvider(notifier, provider)).To(BeFalse())
+		})
+
+		It("should honor the namespace of notifierRefs only if cross namespace refs are allowed", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+
 			controllerNotifierReconciler = &NotifierReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				NotifierFactory: func(notifier *ddnsv1alpha1.Notifier, secret *corev1.Secret, configMap *corev1.ConfigMap) (notifiers.Notifier, error) {
+			}
+
+			By("Defaulting to the namespace of the Provider")
+			ref := ddnsv1alpha1.ResourceRef{Kind: ddnsv1alpha1.NotifierKind, Name: notifierNamespacedName.Name}
+			Expect(controllerNotifierReconciler.refersToNotifier(ref, provider, notifier)).To(BeTrue())
+
+			By("Not matching a Notifier with the same name in another namespace")
+			otherNotifier := notifier.DeepCopy()
+			otherNotifier.Namespace = "kube-public"
+			Expect(controllerNotifierReconciler.refersToNotifier(ref, provider, otherNotifier)).To(BeFalse())
+
+			By("Ignoring refs to other namespaces by default")
+			ref.Namespace = "kube-public"
+			provider.Spec.NotifierRefs = []ddnsv1alpha1.ResourceRef{ref}
+			Expect(controllerNotifierReconciler.refersToNotifier(ref, provider, otherNotifier)).To(BeFalse())
+			Expect(controllerNotifierReconciler.findObjectsForProvider(ctx, provider)).To(BeEmpty())
+
+			By("Following refs to other namespaces once allowed")
+			controllerNotifierReconciler.AllowCrossNamespaceRefs = true
+			Expect(controllerNotifierReconciler.refersToNotifier(ref, provider, otherNotifier)).To(BeTrue())
+			Expect(controllerNotifierReconciler.refersToNotifier(ref, provider, notifier)).To(BeFalse())
+			Expect(controllerNotifierReconciler.findObjectsForProvider(ctx, provider)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: notifierNamespacedName.Name, Namespace: "kube-public"}},
+			))
+		})
+
+		It("should only notify of the Providers that reference the Notifier in its own namespace", func() {
+			messages := []any{}
+			var mu sync.Mutex
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
 					return &MockNotifier{
 						SendNotificationInterceptor: func(message any) {
+							mu.Lock()
+							defer mu.Unlock()
+							messages = append(messages, message)
+						},
+					}, nil
+				},
+			}
+
+			By("Syncing the Provider in the namespace of the Notifier")
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			By("Creating a synced Provider in another namespace with a notifierRef of the same name")
+			other := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-namespace-provider", Namespace: "kube-public"},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:         "Cloudflare",
+					SecretName:   secretNamespacedName.Name,
+					ConfigMap:    configMapNamespacedName.Name,
+					NotifierRefs: []ddnsv1alpha1.ResourceRef{{Name: notifierNamespacedName.Name}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer deleteProvider(ctx, other)
+
+			other.Status = *provider.Status.DeepCopy()
+			Expect(k8sClient.Status().Update(ctx, other)).To(Succeed())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messages).To(ConsistOf(
+				fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", dummyIp, providerNamespacedName.Name),
+			))
+
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.NotifiedProviders).To(ConsistOf(ddnsv1alpha1.NotifiedProvider{
+				Kind:      ddnsv1alpha1.ProviderKind,
+				Name:      providerNamespacedName.Name,
+				Namespace: providerNamespacedName.Namespace,
+				Value:     dummyIp,
+			}))
+
+			By("Ignoring an explicit ref to the namespace of the Notifier while cross namespace refs are not allowed")
+			other.Spec.NotifierRefs[0].Namespace = notifierNamespacedName.Namespace
+			Expect(k8sClient.Update(ctx, other)).To(Succeed())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+
+			By("Notifying of it once cross namespace refs are allowed")
+			controllerNotifierReconciler.AllowCrossNamespaceRefs = true
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(2))
+			Expect(messages[1]).To(Equal(fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", dummyIp, other.Name)))
+		})
+
+		It("should deliver the notifications about several Providers concurrently", func() {
+			var (
+				mu                  sync.Mutex
+				inFlight, maxFlight int
+			)
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendNotificationError: fmt.Errorf("webhook timed out"),
+						SendNotificationInterceptor: func(message any) {
+							mu.Lock()
+							inFlight++
+							maxFlight = max(maxFlight, inFlight)
+							mu.Unlock()
+
+							time.Sleep(200 * time.Millisecond)
+
+							mu.Lock()
+							inFlight--
+							mu.Unlock()
+						},
+					}, nil
+				},
+			}
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			By("Creating a second Provider that references the Notifier")
+			second := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "second-provider", Namespace: providerNamespacedName.Namespace},
+				Spec:       *provider.Spec.DeepCopy(),
+			}
+			Expect(k8sClient.Create(ctx, second)).To(Succeed())
+			defer deleteProvider(ctx, second)
+
+			second.Status = *provider.Status.DeepCopy()
+			Expect(k8sClient.Status().Update(ctx, second)).To(Succeed())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maxFlight).To(Equal(2))
+
+			By("Reporting the failed deliveries of every Provider")
+			resource := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.PendingNotifications).To(HaveLen(2))
+			Expect(resource.Status.FailedDeliveries).To(Equal(int64(2)))
+
+			condition := meta.FindStatusCondition(resource.Status.Conditions, ddnsv1alpha1.NotifierConditionTypeClient)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("provider %s: webhook timed out", providerNamespacedName.Name))
+			Expect(condition.Message).To(ContainSubstring("provider %s: webhook timed out", second.Name))
+
+			By("Delivering them one at a time if the concurrency is limited")
+			maxFlight = 0
+			controllerNotifierReconciler.MaxConcurrentNotifications = 1
+			controllerNotifierReconciler.deliverNotifications(ctx, &MockNotifier{
+				SendNotificationInterceptor: func(message any) {
+					mu.Lock()
+					inFlight++
+					maxFlight = max(maxFlight, inFlight)
+					mu.Unlock()
+
+					time.Sleep(50 * time.Millisecond)
+
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+				},
+			}, []notification{{provider: provider, message: "first"}, {provider: second, message: "second"}})
+			Expect(maxFlight).To(Equal(1))
+
+			By("Not sending them once the context is done")
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+
+			delivered := false
+			errs := controllerNotifierReconciler.deliverMessages(canceled, &MockNotifier{
+				SendNotificationInterceptor: func(message any) { delivered = true },
+			}, []string{"first", "second"})
+			Expect(delivered).To(BeFalse())
+			Expect(errs).To(HaveEach(MatchError(context.Canceled)))
+		})
+
+		It("should only list the Providers that reference the Notifier or are in the scope of its providerSelector", func() {
+			other := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated-provider", Namespace: "default"},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:       "Cloudflare",
+					SecretName: secretNamespacedName.Name,
+					ConfigMap:  configMapNamespacedName.Name,
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer deleteProvider(ctx, other)
+
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+
+			providers, err := controllerNotifierReconciler.listProviders(ctx, notifier)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers).To(HaveLen(1))
+			Expect(providers[0].GetName()).To(Equal(providerNamespacedName.Name))
+
+			By("Listing the Providers with matching labels for a providerSelector")
+			notifier.Spec.ProviderSelector = &ddnsv1alpha1.ProviderSelector{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			}
+
+			providers, err = controllerNotifierReconciler.listProviders(ctx, notifier)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers).To(HaveLen(1))
+
+			other.Labels = map[string]string{"team": "a"}
+			Expect(k8sClient.Update(ctx, other)).To(Succeed())
+
+			providers, err = controllerNotifierReconciler.listProviders(ctx, notifier)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers).To(HaveLen(2))
+
+			By("Getting the Providers in the refs of a providerSelector by their kind and name")
+			notifier.Spec.ProviderSelector = &ddnsv1alpha1.ProviderSelector{
+				Refs: []ddnsv1alpha1.ProviderRef{
+					{Name: other.Name},
+					{Name: "missing-provider"},
+					{Kind: ddnsv1alpha1.ClusterProviderKind, Name: other.Name},
+				},
+			}
+
+			providers, err = controllerNotifierReconciler.listProviders(ctx, notifier)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers).To(HaveLen(2))
+			Expect(providers[1].GetName()).To(Equal(other.Name))
+		})
+
+		It("should requeue the Notifiers that read a changed Secret or ConfigMap", func() {
+			request := reconcile.Request{NamespacedName: notifierNamespacedName}
+
+			secret := &corev1.Secret{}
+			secret.SetName(secretNotifierNamespacedName.Name)
+			secret.SetNamespace(secretNotifierNamespacedName.Namespace)
+			Expect(controllerNotifierReconciler.notifiersForResource(ctx, secret)).To(Equal([]reconcile.Request{request}))
+
+			configMap := &corev1.ConfigMap{}
+			configMap.SetName(configMapNotifierNamespacedName.Name)
+			configMap.SetNamespace(configMapNotifierNamespacedName.Namespace)
+			Expect(controllerNotifierReconciler.notifiersForResource(ctx, configMap)).To(Equal([]reconcile.Request{request}))
+
+			configMap.SetName(secretNotifierNamespacedName.Name)
+			Expect(controllerNotifierReconciler.notifiersForResource(ctx, configMap)).To(BeEmpty())
+		})
+
+		It("should send a fresh greeting when the Secret of a ready Notifier changes", func() {
+			greetings := 0
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendGreetingsInterceptor: func() { greetings++ },
+					}, nil
+				},
+			}
+
+			By("Marking the notifier as ready")
+			for range 2 {
+				_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(greetings).To(Equal(1))
+
+			By("Rotating the credentials in the Secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNotifierNamespacedName, secret)).To(Succeed())
+			secret.StringData = map[string]string{"rotated": "true"}
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(greetings).To(Equal(2))
+
+			By("Not sending it again while the Secret does not change")
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(greetings).To(Equal(2))
+
+			By("Marking the notifier as not ready if the new Secret is rejected")
+			Expect(k8sClient.Get(ctx, secretNotifierNamespacedName, secret)).To(Succeed())
+			secret.StringData = map[string]string{"rotated": "again"}
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			controllerNotifierReconciler.NotifierFactory = func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+				return &MockNotifier{SendGreetingsError: fmt.Errorf("invalid webhook")}, nil
+			}
+
+			result, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(notificationRetryInterval))
+
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.IsReady).To(BeFalse())
+		})
+
+		It("should not send greetings or notifications if the Notifier is suspended", func() {
+			By("Suspending the notifier")
+			resource := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			resource.Spec.Suspend = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					Fail("NotifierFactory should not be called for a suspended Notifier")
+					return nil, nil
+				},
+			}
+
+			By("Reconciling the suspended resource")
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: notifierNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.IsReady).To(BeFalse())
+			Expect(resource.Status.Conditions).To(BeEmpty())
+		})
+
+		It("should successfully reconcile the resource and not send a notification as the provider is ready but there is an error", func() {
+			sendNotificationCounter := 0
+			var mu sync.Mutex
+			recorder := record.NewFakeRecorder(10)
+			By("Creating a custom notifier reconciler")
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendNotificationInterceptor: func(message any) {
+							defer GinkgoRecover()
+							mu.Lock()
+							defer mu.Unlock()
 							Expect(message).To(Equal(fmt.Sprintf("Provider IP (%s) in sync with Public IP. From provider: (%s).", dummyIp, providerNamespacedName.Name)))
 							sendNotificationCounter++
 						},
@@ -470,13 +1029,374 @@ var _ = Describe("Notifier Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			By("Sending a notification due to a change")
-			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{
+			By("Queueing the notification that could not be sent")
+			result, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: notifierNamespacedName,
 			})
-			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", notificationRetryInterval, time.Second))
+
+			Expect(sendNotificationCounter).To(Equal(1))
 
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.IsReady).To(BeTrue())
+			Expect(resource.Status.PendingNotifications).To(HaveLen(1))
+			Expect(resource.Status.PendingNotifications[0].Provider).To(Equal(providerNamespacedName.String()))
+			Expect(resource.Status.PendingNotifications[0].Value).To(Equal(dummyIp))
+			Expect(resource.Status.PendingNotifications[0].Attempts).To(Equal(int32(1)))
+			Expect(resource.Status.PendingNotifications[0].LastError).To(Equal("error sending notification"))
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, ddnsv1alpha1.NotifierConditionTypeReady)).To(BeTrue())
+			Expect(receivedEvents(recorder)).To(ConsistOf(fmt.Sprintf(
+				"Warning NotificationFailed Could not notify about Provider %s, retrying later: error sending notification", providerNamespacedName.Name,
+			)))
+
+			By("Not recording it as notified before it is delivered")
+			Expect(resource.Status.NotifiedProviders).To(BeEmpty())
+
+			By("Not retrying it before it is due")
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: notifierNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
 			Expect(sendNotificationCounter).To(Equal(1))
+
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.PendingNotifications).To(HaveLen(1))
+		})
+
+		It("should retry the pending notifications that are due and drop them once they run out of retries", func() {
+			sent := []any{}
+			var mu sync.Mutex
+			mockNotifier := &MockNotifier{
+				SendNotificationInterceptor: func(message any) {
+					mu.Lock()
+					defer mu.Unlock()
+					sent = append(sent, message)
+				},
+			}
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return mockNotifier, nil
+				},
+			}
+
+			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Queueing a notification that is due and one that is not")
+			resource := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			resource.Spec.NotificationRetries = ptr.To[int32](2)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			resource.Status.PendingNotifications = []ddnsv1alpha1.PendingNotification{
+				{Provider: "due", Message: "due", Attempts: 2, NextAttemptTime: metav1.NewTime(time.Now().Add(-time.Minute))},
+				{
+					Provider:        providerNamespacedName.String(),
+					Message:         "later",
+					Value:           "1.2.3.4",
+					Attempts:        1,
+					NextAttemptTime: metav1.NewTime(time.Now().Add(time.Hour)),
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+
+			By("Dropping the due notification when it fails for the last time")
+			mockNotifier.SendNotificationError = fmt.Errorf("error sending notification")
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sent).To(Equal([]any{"due"}))
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.PendingNotifications).To(HaveLen(1))
+			Expect(resource.Status.PendingNotifications[0].Provider).To(Equal(providerNamespacedName.String()))
+
+			By("Sending it once it is due and the notifier works again")
+			resource.Status.PendingNotifications[0].NextAttemptTime = metav1.NewTime(time.Now().Add(-time.Second))
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+
+			mockNotifier.SendNotificationError = nil
+			result, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			Expect(sent).To(Equal([]any{"due", "later"}))
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.PendingNotifications).To(BeEmpty())
+			Expect(resource.Status.NotificationsSent).To(Equal(int64(1)))
+			Expect(resource.Status.FailedDeliveries).To(Equal(int64(1)))
+
+			By("Recording what the delivered notification reported")
+			Expect(resource.Status.NotifiedProviders).To(ConsistOf(ddnsv1alpha1.NotifiedProvider{
+				Kind:      ddnsv1alpha1.ProviderKind,
+				Name:      providerNamespacedName.Name,
+				Namespace: providerNamespacedName.Namespace,
+				Value:     "1.2.3.4",
+			}))
+		})
+
+		It("should record what it reported in its status instead of annotating the Providers", func() {
+			messages := []any{}
+			var mu sync.Mutex
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendNotificationInterceptor: func(message any) {
+							mu.Lock()
+							defer mu.Unlock()
+							messages = append(messages, message)
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			resourceVersion := provider.ResourceVersion
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.NotifiedProviders).To(Equal([]ddnsv1alpha1.NotifiedProvider{{
+				Kind:      ddnsv1alpha1.ProviderKind,
+				Name:      providerNamespacedName.Name,
+				Namespace: providerNamespacedName.Namespace,
+				Value:     dummyIp,
+			}}))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.ResourceVersion).To(Equal(resourceVersion))
+
+			By("Not notifying again while the IP does not change")
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(1))
+
+			By("Dropping the Providers it no longer reports on")
+			provider.Spec.NotifierRefs = nil
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.NotifiedProviders).To(BeEmpty())
+		})
+
+		It("should not notify again of Providers annotated by older versions of the controller", func() {
+			messages := []any{}
+			var mu sync.Mutex
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return &MockNotifier{
+						SendNotificationInterceptor: func(message any) {
+							mu.Lock()
+							defer mu.Unlock()
+							messages = append(messages, message)
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Annotating the Provider like older versions did")
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Annotations = map[string]string{controllerNotifierReconciler.legacyNotifierAnnotation(notifier): dummyIp}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+
+			By("Taking over the value of the annotation in the status")
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.NotifiedProviders).To(HaveLen(1))
+			Expect(notifier.Status.NotifiedProviders[0].Value).To(Equal(dummyIp))
+		})
+
+		It("should remove its finalizer when the Notifier is deleted", func() {
+			By("Adding the finalizer")
+			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Finalizers).To(ContainElement(ddnsv1alpha1.NotifierFinalizer))
+
+			By("Deleting the Notifier")
+			Expect(k8sClient.Delete(ctx, notifier)).To(Succeed())
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, notifierNamespacedName, notifier))).To(BeTrue())
+
+			By("Recreating the Notifier for the AfterEach")
+			Expect(k8sClient.Create(ctx, &ddnsv1alpha1.Notifier{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      notifierNamespacedName.Name,
+					Namespace: notifierNamespacedName.Namespace,
+				},
+				Spec: ddnsv1alpha1.NotifierSpec{
+					Name:       "Webhook",
+					SecretName: secretNotifierNamespacedName.Name,
+					ConfigMap:  configMapNotifierNamespacedName.Name,
+				},
+			})).To(Succeed())
+		})
+
+		It("should send a test message once for every new value of the test annotation", func() {
+			sent := []any{}
+			var mu sync.Mutex
+			mockNotifier := &MockNotifier{
+				SendNotificationInterceptor: func(message any) {
+					mu.Lock()
+					defer mu.Unlock()
+					sent = append(sent, message)
+				},
+			}
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					return mockNotifier, nil
+				},
+			}
+
+			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Requesting a test")
+			notifier := &ddnsv1alpha1.Notifier{}
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			notifier.Annotations = map[string]string{ddnsv1alpha1.TestAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, notifier)).To(Succeed())
+
+			for range 2 {
+				_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(sent).To(Equal([]any{fmt.Sprintf("Test message. From notifier: (%s).", notifierNamespacedName.Name)}))
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			Expect(notifier.Status.LastHandledTest).To(Equal("1"))
+			Expect(notifier.Status.NotificationsSent).To(Equal(int64(1)))
+			Expect(notifier.Status.LastNotificationTime).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(notifier.Status.Conditions, ddnsv1alpha1.NotifierConditionTypeTested)).To(BeTrue())
+
+			By("Reporting a test that failed")
+			notifier.Annotations[ddnsv1alpha1.TestAnnotation] = "2"
+			Expect(k8sClient.Update(ctx, notifier)).To(Succeed())
+
+			mockNotifier.SendNotificationError = fmt.Errorf("error sending notification")
+			_, err = controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sent).To(HaveLen(2))
+			Expect(k8sClient.Get(ctx, notifierNamespacedName, notifier)).To(Succeed())
+			tested := meta.FindStatusCondition(notifier.Status.Conditions, ddnsv1alpha1.NotifierConditionTypeTested)
+			Expect(tested.Status).To(Equal(metav1.ConditionFalse))
+			Expect(tested.Message).To(Equal("unable to send the test message: error sending notification"))
+			Expect(notifier.Status.NotificationsSent).To(Equal(int64(1)))
+			Expect(notifier.Status.FailedDeliveries).To(Equal(int64(1)))
+		})
+
+		It("should reuse the client of the Notifier until its Secret changes", func() {
+			built := 0
+			controllerNotifierReconciler = &NotifierReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				NotifierFactory: func(notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (notifiers.Notifier, error) {
+					built++
+					return &MockNotifier{}, nil
+				},
+				clients: &notifierClients{},
+			}
+
+			for range 3 {
+				_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(built).To(Equal(1))
+
+			By("Building a new client for the rotated Secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNotifierNamespacedName, secret)).To(Succeed())
+			secret.StringData = map[string]string{"rotated": "true"}
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			_, err := controllerNotifierReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: notifierNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(built).To(Equal(2))
+		})
+
+		It("should double the wait between the attempts to deliver a notification", func() {
+			Expect(notificationBackoff(1)).To(Equal(30 * time.Second))
+			Expect(notificationBackoff(3)).To(Equal(2 * time.Minute))
+			Expect(notificationBackoff(20)).To(Equal(maxNotificationRetryInterval))
+		})
+
+		It("should report the deliveries of the notifications in the metrics", func() {
+			// deliveryCount returns how many notifications were delivered to the Notifier with the result
+			deliveryCount := func(result string) float64 {
+				metric := &dto.Metric{}
+				counter := notificationDeliveries.WithLabelValues("Notifier", "default", "test-metrics-notifier", "Webhook", result)
+				Expect(counter.Write(metric)).To(Succeed())
+
+				return metric.GetCounter().GetValue()
+			}
+
+			metricsNotifier := &ddnsv1alpha1.Notifier{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-metrics-notifier", Namespace: "default"},
+				Spec:       ddnsv1alpha1.NotifierSpec{Name: "Webhook"},
+			}
+			successes, failures := deliveryCount("success"), deliveryCount("failure")
+
+			Expect(instrumentNotifier(ctx, metricsNotifier, &MockNotifier{}).SendNotification("sent")).To(Succeed())
+			Expect(instrumentNotifier(ctx, metricsNotifier, &MockNotifier{
+				SendNotificationError: fmt.Errorf("webhook timed out"),
+			}).SendNotification("failed")).NotTo(Succeed())
+
+			Expect(deliveryCount("success")).To(Equal(successes + 1))
+			Expect(deliveryCount("failure")).To(Equal(failures + 1))
+		})
+
+		It("should apply every patch of the status at once", func() {
+			observed := ddnsv1alpha1.ObservedResources{SecretHash: "secret", ConfigMapHash: "config-map"}
+			patched := &ddnsv1alpha1.Notifier{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+			patched.Status.ObservedGeneration = 2
+
+			patch := controllerNotifierReconciler.patchAll(
+				controllerNotifierReconciler.patchObservedGeneration(2),
+				controllerNotifierReconciler.patchObservedResources(observed),
+			)
+			Expect(patch(patched)).To(BeTrue())
+			Expect(patched.Status.ObservedResources).To(Equal(&observed))
+
+			Expect(patch(patched)).To(BeFalse())
 		})
 	})
 })
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+)
+
+// defaultMaxConcurrentNotifications is how many notifications are delivered at once if MaxConcurrentNotifications is not set
+const defaultMaxConcurrentNotifications = 5
+
+// notification is a message to the notifier about a provider, together with the value it reports, which is recorded in
+// the NotifiedProviders of the notifier
+type notification struct {
+	provider ddnsv1alpha1.ProviderObject
+	value    string
+	message  string
+	// err is the error of the delivery, if it failed
+	err error
+}
+
+// maxConcurrentNotifications returns how many notifications may be delivered at once
+func (r *NotifierReconciler) maxConcurrentNotifications() int {
+	if r.MaxConcurrentNotifications > 0 {
+		return r.MaxConcurrentNotifications
+	}
+
+	return defaultMaxConcurrentNotifications
+}
+
+// deliverNotifications sends the notifications concurrently, at most maxConcurrentNotifications at a time, and stores the
+// error of every delivery in its notification
+func (r *NotifierReconciler) deliverNotifications(
+	ctx context.Context,
+	notifierClient notifiers.Notifier,
+	notifications []notification,
+) {
+	messages := make([]string, len(notifications))
+	for i, notification := range notifications {
+		messages[i] = notification.message
+	}
+
+	for i, err := range r.deliverMessages(ctx, notifierClient, messages) {
+		notifications[i].err = err
+	}
+}
+
+// deliverMessages sends the messages concurrently, at most maxConcurrentNotifications at a time, and returns the error of
+// every delivery. Once the context is done, the messages that were not sent yet fail with its error
+func (r *NotifierReconciler) deliverMessages(ctx context.Context, notifierClient notifiers.Notifier, messages []string) []error {
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, r.maxConcurrentNotifications())
+		errs  = make([]error, len(messages))
+	)
+
+	for i := range messages {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+
+			errs[i] = notifierClient.SendNotification(messages[i])
+		}()
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// sendNotifications delivers the notifications to the notifierClient. Notifications that could not be delivered are
+// queued in the PendingNotifications of the notifier and retried from there. The Client condition lists the providers
+// whose notifications failed
+func (r *NotifierReconciler) sendNotifications(
+	ctx context.Context,
+	notifier ddnsv1alpha1.NotifierObject,
+	notifierClient notifiers.Notifier,
+	notifications []notification,
+) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	log := log.FromContext(ctx)
+
+	r.deliverNotifications(ctx, notifierClient, notifications)
+
+	// The notifications that were not sent because the reconciliation was canceled are sent again by the next one
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var (
+		sent, failed int64
+		deliveryErrs []error
+		undelivered  []notification
+	)
+
+	for _, notification := range notifications {
+		providerSent, providerFailed := deliveries(notification.err)
+		sent += providerSent
+		failed += providerFailed
+
+		if notification.err == nil {
+			continue
+		}
+
+		deliveryErrs = append(deliveryErrs, fmt.Errorf("provider %s: %w", notification.provider.GetName(), notification.err))
+		undelivered = append(undelivered, notification)
+
+		log.Error(notification.err, "unable to send notification, retrying it later", "provider", notification.provider.GetName())
+		r.eventf(notifier, corev1.EventTypeWarning, "NotificationFailed", "Could not notify about %s %s, retrying later: %s",
+			providerKind(notification.provider), notification.provider.GetName(), notification.err)
+	}
+
+	if err := r.patchStatus(ctx, notifier, r.patchAll(
+		r.patchDeliveries(sent, failed),
+		r.queueNotifications(ctx, undelivered),
+	)); err != nil {
+		return err
+	}
+
+	r.patchClientCommunication(ctx, notifier, errors.Join(deliveryErrs...))
+
+	return nil
+}
+
+// notifiedProvider returns the entry of the provider in the NotifiedProviders of a notifier
+func notifiedProvider(provider ddnsv1alpha1.ProviderObject, value string) ddnsv1alpha1.NotifiedProvider {
+	kind := ddnsv1alpha1.ProviderKind
+	if provider.GetNamespace() == "" {
+		kind = ddnsv1alpha1.ClusterProviderKind
+	}
+
+	return ddnsv1alpha1.NotifiedProvider{
+		Kind:      kind,
+		Name:      provider.GetName(),
+		Namespace: provider.GetNamespace(),
+		Value:     value,
+	}
+}
+
+// notifiedValue returns what the notifier last reported about the provider, if anything. Providers that were reported on
+// by older versions of the controller may still hold it in the legacyNotifierAnnotation instead
+func (r *NotifierReconciler) notifiedValue(notifier ddnsv1alpha1.NotifierObject, provider ddnsv1alpha1.ProviderObject) (string, bool) {
+	key := notifiedProvider(provider, "")
+	for _, notified := range notifier.GetNotifierStatus().NotifiedProviders {
+		if notified.Kind == key.Kind && notified.Name == key.Name && notified.Namespace == key.Namespace {
+			return notified.Value, true
+		}
+	}
+
+	value, ok := provider.GetAnnotations()[r.legacyNotifierAnnotation(notifier)]
+
+	return value, ok
+}
+
+// patchNotifiedProviders records what the notifier reported about the providers it reports on, including the
+// notifications that were just delivered. The values of the queued notifications are only recorded once they are
+// delivered. The providers it no longer reports on are dropped
+func (r NotifierReconciler) patchNotifiedProviders(
+	providers []ddnsv1alpha1.ProviderObject,
+	sent []notification,
+) func(notifier ddnsv1alpha1.NotifierObject) bool {
+	sentValues := map[types.NamespacedName]string{}
+	for _, notification := range sent {
+		if notification.err == nil {
+			sentValues[client.ObjectKeyFromObject(notification.provider)] = notification.value
+		}
+	}
+
+	return func(notifier ddnsv1alpha1.NotifierObject) bool {
+		notified := []ddnsv1alpha1.NotifiedProvider{}
+		for _, provider := range providers {
+			value, ok := sentValues[client.ObjectKeyFromObject(provider)]
+			if !ok {
+				value, ok = r.notifiedValue(notifier, provider)
+			}
+
+			if ok {
+				notified = append(notified, notifiedProvider(provider, value))
+			}
+		}
+
+		status := notifier.GetNotifierStatus()
+		if slices.Equal(status.NotifiedProviders, notified) {
+			return false
+		}
+
+		status.NotifiedProviders = notified
+
+		return true
+	}
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/tracing"
+)
+
+// The metrics of the notifiers are labeled by the kind, namespace and name of the notifier and the type of its backend,
+// so a webhook that silently stopped accepting the notifications shows up on a dashboard
+var (
+	notificationDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ddns_notifier_deliveries_total",
+		Help: "How many notifications were sent to a notifier, by result: success or failure.",
+	}, []string{"kind", "namespace", "name", "type", "result"})
+
+	notificationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ddns_notifier_delivery_duration_seconds",
+		Help:    "How long sending a notification to a notifier took.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"kind", "namespace", "name", "type"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(notificationDeliveries, notificationDuration)
+}
+
+// instrumentedNotifier reports the deliveries of the notifications of a notifier in the metrics and traces them
+type instrumentedNotifier struct {
+	notifiers.Notifier
+	// ctx holds the span of the reconciliation the notifications are sent in, as the Notifiers do not take a context
+	ctx    context.Context
+	labels []string
+}
+
+// instrumentNotifier wraps the client of the notifier for a reconciliation, so every notification sent with it is
+// reported in the metrics and traced as part of the reconciliation
+func instrumentNotifier(ctx context.Context, notifier ddnsv1alpha1.NotifierObject, notifierClient notifiers.Notifier) notifiers.Notifier {
+	return instrumentedNotifier{
+		Notifier: notifierClient,
+		ctx:      ctx,
+		labels:   []string{notifierKind(notifier), notifier.GetNamespace(), notifier.GetName(), notifier.GetNotifierSpec().Name},
+	}
+}
+
+// notifierKind returns the kind of the notifier, used to tell Notifiers and ClusterNotifiers apart
+func notifierKind(notifier ddnsv1alpha1.NotifierObject) string {
+	if notifier.GetNamespace() == "" {
+		return ddnsv1alpha1.ClusterNotifierKind
+	}
+
+	return ddnsv1alpha1.NotifierKind
+}
+
+// SendNotification sends the notification with the wrapped client, recording its result and how long it took
+func (n instrumentedNotifier) SendNotification(message any) error {
+	_, span := tracing.Start(n.ctx, "Notifier.SendNotification")
+
+	start := time.Now()
+	err := n.Notifier.SendNotification(message)
+	notificationDuration.WithLabelValues(n.labels...).Observe(time.Since(start).Seconds())
+	tracing.End(span, err)
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	notificationDeliveries.WithLabelValues(append(n.labels, result)...).Inc()
+
+	return err
+}
+
+// deleteNotifierMetrics removes the metrics of a deleted notifier, so they are not reported until the controller restarts
+func deleteNotifierMetrics(notifier ddnsv1alpha1.NotifierObject) {
+	labels := prometheus.Labels{"namespace": notifier.GetNamespace(), "name": notifier.GetName()}
+
+	notificationDeliveries.DeletePartialMatch(labels)
+	notificationDuration.DeletePartialMatch(labels)
+}
@@ -9,7 +9,7 @@ type MockNotifier struct {
 	SendNotificationInterceptor func(message any)
 }
 
-func (n MockNotifier) SendGreetings(notifier *ddnsv1alpha1.Notifier) error {
+func (n MockNotifier) SendGreetings(notifier ddnsv1alpha1.NotifierObject) error {
 	if n.SendGreetingsInterceptor != nil {
 		n.SendGreetingsInterceptor()
 	}
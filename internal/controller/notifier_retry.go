@@ -0,0 +1,292 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/notifiers"
+)
+
+const (
+	// notificationRetryInterval is how long to wait before retrying a notification that could not be delivered.
+	// It doubles with every failed attempt, up to maxNotificationRetryInterval
+	notificationRetryInterval    = 30 * time.Second
+	maxNotificationRetryInterval = 30 * time.Minute
+)
+
+// notificationBackoff returns how long to wait before the next attempt to deliver a notification that failed attempts times
+func notificationBackoff(attempts int32) time.Duration {
+	backoff := notificationRetryInterval
+	for i := int32(1); i < attempts && backoff < maxNotificationRetryInterval; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxNotificationRetryInterval)
+}
+
+// notifierProbeBackoff returns how long to wait before greeting a notifier again whose greeting failed failures times in
+// a row. Notifiers are probed on the same schedule as the notifications are retried
+func notifierProbeBackoff(failures int64) time.Duration {
+	return notificationBackoff(int32(min(failures, math.MaxInt32)))
+}
+
+// retryPendingNotifications delivers the pending notifications of the notifier that are due, as many at once as the
+// notifications about changes. Notifications that fail again are retried later, until they run out of
+// NotificationRetries and are dropped
+func (r *NotifierReconciler) retryPendingNotifications(
+	ctx context.Context,
+	notifier ddnsv1alpha1.NotifierObject,
+	notifierClient notifiers.Notifier,
+) error {
+	log := log.FromContext(ctx)
+	status := notifier.GetNotifierStatus()
+	retries := notifier.GetNotifierSpec().GetNotificationRetries()
+
+	var (
+		due      []ddnsv1alpha1.PendingNotification
+		messages []string
+		pending  []ddnsv1alpha1.PendingNotification
+	)
+
+	for _, notification := range status.PendingNotifications {
+		if time.Now().Before(notification.NextAttemptTime.Time) {
+			pending = append(pending, notification)
+			continue
+		}
+
+		due = append(due, notification)
+		messages = append(messages, notification.Message)
+	}
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	errs := r.deliverMessages(ctx, notifierClient, messages)
+
+	// The notifications stay pending if the reconciliation was canceled, their status could not be updated anyway
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var (
+		sent, failed int64
+		lastErr      error
+		delivered    []ddnsv1alpha1.PendingNotification
+	)
+
+	for i, notification := range due {
+		err := errs[i]
+		if err == nil {
+			log.Info("Pending notification sent", "provider", notification.Provider, "attempts", notification.Attempts)
+			sent++
+			delivered = append(delivered, notification)
+			continue
+		}
+
+		failed++
+		lastErr = err
+		notification.Attempts++
+		notification.LastError = err.Error()
+
+		if notification.Attempts > retries {
+			log.Error(err, "Dropping notification, it ran out of retries", "provider", notification.Provider, "message", notification.Message)
+			continue
+		}
+
+		notification.NextAttemptTime = metav1.NewTime(time.Now().Add(notificationBackoff(notification.Attempts)))
+		pending = append(pending, notification)
+	}
+
+	if err := r.patchStatus(ctx, notifier, r.patchAll(
+		r.patchPendingNotifications(pending),
+		r.patchDeliveries(sent, failed),
+		r.patchRetriedProviders(delivered),
+	)); err != nil {
+		return err
+	}
+
+	r.patchClientCommunication(ctx, notifier, lastErr)
+
+	return nil
+}
+
+// queueNotifications adds the notifications that could not be delivered to the pending notifications of the notifier,
+// unless they may not be retried
+func (r NotifierReconciler) queueNotifications(
+	ctx context.Context,
+	undelivered []notification,
+) func(notifier ddnsv1alpha1.NotifierObject) bool {
+	return func(notifier ddnsv1alpha1.NotifierObject) bool {
+		if len(undelivered) == 0 {
+			return false
+		}
+
+		if notifier.GetNotifierSpec().GetNotificationRetries() == 0 {
+			for _, notification := range undelivered {
+				log.FromContext(ctx).Error(notification.err, "Dropping notification, retries are disabled", "message", notification.message)
+			}
+
+			return false
+		}
+
+		status := notifier.GetNotifierStatus()
+		for _, notification := range undelivered {
+			status.PendingNotifications = append(status.PendingNotifications, ddnsv1alpha1.PendingNotification{
+				Provider:        pendingProvider(notification.provider),
+				Message:         notification.message,
+				Value:           notification.value,
+				Attempts:        1,
+				NextAttemptTime: metav1.NewTime(time.Now().Add(notificationBackoff(1))),
+				LastError:       notification.err.Error(),
+			})
+		}
+
+		return true
+	}
+}
+
+// pendingProvider returns how a pending notification refers to the provider: namespace/name for a Provider and only the
+// name for a ClusterProvider
+func pendingProvider(provider ddnsv1alpha1.ProviderObject) string {
+	if provider.GetNamespace() == "" {
+		return provider.GetName()
+	}
+
+	return client.ObjectKeyFromObject(provider).String()
+}
+
+// pendingValue returns what the last of the pending notifications about the provider reports, if there is one
+func pendingValue(notifier ddnsv1alpha1.NotifierObject, provider ddnsv1alpha1.ProviderObject) (string, bool) {
+	key := pendingProvider(provider)
+	pending := notifier.GetNotifierStatus().PendingNotifications
+	for i := len(pending) - 1; i >= 0; i-- {
+		if pending[i].Provider == key && pending[i].Value != "" {
+			return pending[i].Value, true
+		}
+	}
+
+	return "", false
+}
+
+// patchRetriedProviders records the values of the pending notifications that were delivered in the NotifiedProviders.
+// Notifications queued by older versions of the controller hold no value and are not recorded
+func (r NotifierReconciler) patchRetriedProviders(delivered []ddnsv1alpha1.PendingNotification) func(notifier ddnsv1alpha1.NotifierObject) bool {
+	return func(notifier ddnsv1alpha1.NotifierObject) bool {
+		status := notifier.GetNotifierStatus()
+		changed := false
+
+		for _, notification := range delivered {
+			if notification.Value == "" {
+				continue
+			}
+
+			notified := ddnsv1alpha1.NotifiedProvider{Kind: ddnsv1alpha1.ClusterProviderKind, Name: notification.Provider}
+			if namespace, name, ok := strings.Cut(notification.Provider, "/"); ok {
+				notified = ddnsv1alpha1.NotifiedProvider{Kind: ddnsv1alpha1.ProviderKind, Name: name, Namespace: namespace}
+			}
+
+			notified.Value = notification.Value
+
+			i := slices.IndexFunc(status.NotifiedProviders, func(provider ddnsv1alpha1.NotifiedProvider) bool {
+				return provider.Kind == notified.Kind && provider.Name == notified.Name && provider.Namespace == notified.Namespace
+			})
+
+			switch {
+			case i < 0:
+				status.NotifiedProviders = append(status.NotifiedProviders, notified)
+			case status.NotifiedProviders[i] == notified:
+				continue
+			default:
+				status.NotifiedProviders[i] = notified
+			}
+
+			changed = true
+		}
+
+		return changed
+	}
+}
+
+// patchClientCommunication reports in the Client condition if the last notification was delivered
+func (r *NotifierReconciler) patchClientCommunication(ctx context.Context, notifier ddnsv1alpha1.NotifierObject, err error) {
+	if err == nil {
+		conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeClient,
+			conditions.WithReasonAndMessage("ClientCommunication", "Notification sent"),
+			conditions.True(),
+		)
+
+		return
+	}
+
+	message := fmt.Sprintf("unable to send notification: %s", err)
+	if pending := len(notifier.GetNotifierStatus().PendingNotifications); pending > 0 {
+		message = fmt.Sprintf("%s, %d notifications pending", message, pending)
+	}
+
+	conditions.PatchConditions(ctx, r.Client, notifier, ddnsv1alpha1.NotifierConditionTypeClient,
+		conditions.False(),
+		conditions.WithReasonAndMessage("ClientCommunication", message),
+	)
+}
+
+// nextNotificationAttempt returns how long until the first of the pending notifications is due, or 0 if there are none
+func nextNotificationAttempt(pending []ddnsv1alpha1.PendingNotification) time.Duration {
+	var next time.Duration
+	for i, notification := range pending {
+		until := max(time.Until(notification.NextAttemptTime.Time), time.Second)
+		if i == 0 || until < next {
+			next = until
+		}
+	}
+
+	return next
+}
+
+// patchDeliveries adds the notifications that were sent and the deliveries that failed to the statistics of the notifier
+func (r NotifierReconciler) patchDeliveries(sent, failed int64) func(notifier ddnsv1alpha1.NotifierObject) bool {
+	return func(notifier ddnsv1alpha1.NotifierObject) bool {
+		status := notifier.GetNotifierStatus()
+		status.NotificationsSent += sent
+		status.FailedDeliveries += failed
+
+		if sent > 0 {
+			now := metav1.Now()
+			status.LastNotificationTime = &now
+		}
+
+		return sent+failed > 0
+	}
+}
+
+// deliveries returns the arguments of patchDeliveries for a single delivery that failed with err, if not nil
+func deliveries(err error) (int64, int64) {
+	if err != nil {
+		return 0, 1
+	}
+
+	return 1, 0
+}
+
+func (r NotifierReconciler) patchPendingNotifications(pending []ddnsv1alpha1.PendingNotification) func(notifier ddnsv1alpha1.NotifierObject) bool {
+	return func(notifier ddnsv1alpha1.NotifierObject) bool {
+		status := notifier.GetNotifierStatus()
+		if len(status.PendingNotifications) == 0 && len(pending) == 0 {
+			return false
+		}
+
+		status.PendingNotifications = pending
+
+		return true
+	}
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// ControllerNames are the names of the controllers, which the RateLimiterDelays of single controllers are set by
+var ControllerNames = []string{
+	"provider", "clusterprovider", "notifier", "clusternotifier", "dnsrecord", "zone", "ingress", "service", "dnsendpoint",
+	"ingressroute", "istio-gateway", "istio-virtualservice",
+}
+
+// ControllerOptions configures how many reconciliations a controller runs at once and how its work queue is rate limited.
+// The zero value keeps the defaults of controller-runtime
+type ControllerOptions struct {
+	// MaxConcurrentReconciles is the maximum number of reconciliations that run at once. Defaults to 1
+	MaxConcurrentReconciles int
+	// RateLimiterBaseDelay is the delay before the first retry of a failed request, doubling with every failure. Defaults to 5ms
+	RateLimiterBaseDelay time.Duration
+	// RateLimiterMaxDelay is the maximum delay before the retry of a failed request. Defaults to 1000s
+	RateLimiterMaxDelay time.Duration
+	// RateLimiterQPS is the overall number of requests per second that are queued. Defaults to 10
+	RateLimiterQPS float64
+	// RateLimiterBurst is the number of requests that can be queued at once, above the RateLimiterQPS. Defaults to 100
+	RateLimiterBurst int
+	// RateLimiterDelays override the RateLimiterBaseDelay and RateLimiterMaxDelay of single controllers, by the name of the
+	// controller, e.g. provider or notifier
+	RateLimiterDelays map[string]RateLimiterDelays
+}
+
+// RateLimiterDelays are the delays of the rate limiter of a single controller. A delay of 0 keeps the one of the ControllerOptions
+type RateLimiterDelays struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// ParseRateLimiterDelays parses a comma separated list of controller=baseDelay:maxDelay pairs, e.g.
+// provider=1s:5m,notifier=10ms. The max delay can be left out. The controllers must be ones of ControllerNames
+func ParseRateLimiterDelays(value string) (map[string]RateLimiterDelays, error) {
+	delays := map[string]RateLimiterDelays{}
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		name, durations, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid rate limiter delays %q, expected <controller>=<baseDelay>[:<maxDelay>]", pair)
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(ControllerNames, name) {
+			return nil, fmt.Errorf("unknown controller %q in rate limiter delays, expected one of %s",
+				name, strings.Join(ControllerNames, ", "))
+		}
+
+		baseDelay, maxDelay, _ := strings.Cut(durations, ":")
+
+		var (
+			controllerDelays RateLimiterDelays
+			err              error
+		)
+		if controllerDelays.BaseDelay, err = time.ParseDuration(strings.TrimSpace(baseDelay)); err != nil {
+			return nil, fmt.Errorf("invalid base delay %q of controller %q: %w", baseDelay, name, err)
+		}
+
+		if strings.TrimSpace(maxDelay) != "" {
+			if controllerDelays.MaxDelay, err = time.ParseDuration(strings.TrimSpace(maxDelay)); err != nil {
+				return nil, fmt.Errorf("invalid max delay %q of controller %q: %w", maxDelay, name, err)
+			}
+		}
+
+		if controllerDelays.MaxDelay > 0 && controllerDelays.BaseDelay > controllerDelays.MaxDelay {
+			return nil, fmt.Errorf("base delay %s of controller %q is greater than its max delay %s",
+				controllerDelays.BaseDelay, name, controllerDelays.MaxDelay)
+		}
+
+		delays[name] = controllerDelays
+	}
+
+	return delays, nil
+}
+
+// For returns the options of the controller of the given name, with its own RateLimiterDelays if it has any
+func (o ControllerOptions) For(name string) ControllerOptions {
+	delays, ok := o.RateLimiterDelays[name]
+	if !ok {
+		return o
+	}
+
+	if delays.BaseDelay > 0 {
+		o.RateLimiterBaseDelay = delays.BaseDelay
+	}
+
+	if delays.MaxDelay > 0 {
+		o.RateLimiterMaxDelay = delays.MaxDelay
+	}
+
+	return o
+}
+
+// Validate returns an error if the base delay of the rate limiter of any controller is greater than its max delay
+func (o ControllerOptions) Validate() error {
+	for _, name := range ControllerNames {
+		options := o.For(name)
+		if options.RateLimiterMaxDelay > 0 && options.RateLimiterBaseDelay > options.RateLimiterMaxDelay {
+			return fmt.Errorf("rate limiter base delay %s of controller %q is greater than its max delay %s",
+				options.RateLimiterBaseDelay, name, options.RateLimiterMaxDelay)
+		}
+	}
+
+	return nil
+}
+
+// options returns the controller options, with the rate limiter only set if one of its options is
+func (o ControllerOptions) options() ctrlcontroller.Options {
+	options := ctrlcontroller.Options{MaxConcurrentReconciles: o.MaxConcurrentReconciles}
+
+	if o.RateLimiterBaseDelay == 0 && o.RateLimiterMaxDelay == 0 && o.RateLimiterQPS == 0 && o.RateLimiterBurst == 0 {
+		return options
+	}
+
+	baseDelay, maxDelay, qps, burst := 5*time.Millisecond, 1000*time.Second, 10.0, 100
+	if o.RateLimiterBaseDelay > 0 {
+		baseDelay = o.RateLimiterBaseDelay
+	}
+
+	if o.RateLimiterMaxDelay > 0 {
+		maxDelay = o.RateLimiterMaxDelay
+	}
+
+	if o.RateLimiterQPS > 0 {
+		qps = o.RateLimiterQPS
+	}
+
+	if o.RateLimiterBurst > 0 {
+		burst = o.RateLimiterBurst
+	}
+
+	options.RateLimiter = workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+
+	return options
+}
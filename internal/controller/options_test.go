@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controller options", func() {
+	It("should keep the defaults of controller-runtime if nothing is set", func() {
+		options := ControllerOptions{}.options()
+		Expect(options.MaxConcurrentReconciles).To(BeZero())
+		Expect(options.RateLimiter).To(BeNil())
+	})
+
+	It("should build a rate limiter from the options that are set", func() {
+		options := ControllerOptions{
+			MaxConcurrentReconciles: 5,
+			RateLimiterBaseDelay:    time.Second,
+			RateLimiterMaxDelay:     3 * time.Second,
+		}.options()
+		Expect(options.MaxConcurrentReconciles).To(Equal(5))
+		Expect(options.RateLimiter).NotTo(BeNil())
+
+		Expect(options.RateLimiter.When("item")).To(Equal(time.Second))
+		Expect(options.RateLimiter.When("item")).To(Equal(2 * time.Second))
+		Expect(options.RateLimiter.When("item")).To(Equal(3 * time.Second))
+	})
+
+	It("should override the delays of single controllers", func() {
+		delays, err := ParseRateLimiterDelays("Provider=1s:5m, notifier=10ms")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delays).To(Equal(map[string]RateLimiterDelays{
+			"provider": {BaseDelay: time.Second, MaxDelay: 5 * time.Minute},
+			"notifier": {BaseDelay: 10 * time.Millisecond},
+		}))
+
+		options := ControllerOptions{RateLimiterBaseDelay: time.Millisecond, RateLimiterMaxDelay: time.Minute, RateLimiterDelays: delays}
+		Expect(options.For("provider")).To(HaveField("RateLimiterBaseDelay", time.Second))
+		Expect(options.For("provider")).To(HaveField("RateLimiterMaxDelay", 5*time.Minute))
+		Expect(options.For("notifier")).To(HaveField("RateLimiterMaxDelay", time.Minute))
+		Expect(options.For("zone")).To(HaveField("RateLimiterBaseDelay", time.Millisecond))
+
+		_, err = ParseRateLimiterDelays("provider")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseRateLimiterDelays("provider=fast")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject the delays of unknown controllers", func() {
+		_, err := ParseRateLimiterDelays("providers=1s")
+		Expect(err).To(MatchError(ContainSubstring(`unknown controller "providers"`)))
+
+		delays, err := ParseRateLimiterDelays("istio-gateway=1s,dnsrecord=2s")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delays).To(HaveLen(2))
+	})
+
+	It("should reject base delays greater than the max delays", func() {
+		_, err := ParseRateLimiterDelays("provider=5m:1s")
+		Expect(err).To(HaveOccurred())
+
+		delays, err := ParseRateLimiterDelays("provider=5m")
+		Expect(err).NotTo(HaveOccurred())
+
+		options := ControllerOptions{RateLimiterBaseDelay: time.Millisecond, RateLimiterMaxDelay: time.Minute, RateLimiterDelays: delays}
+		Expect(options.Validate()).To(MatchError(ContainSubstring(`controller "provider"`)))
+
+		options.RateLimiterDelays = nil
+		Expect(options.Validate()).To(Succeed())
+
+		options.RateLimiterBaseDelay = time.Hour
+		Expect(options.Validate()).NotTo(Succeed())
+	})
+})
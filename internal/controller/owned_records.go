@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// Owned records are the DNSRecords the controller creates for the hosts of other resources, e.g. Ingresses. They are
+// controlled by the resource, so they are garbage collected with it, and labeled with its name, so they can be listed
+
+// ownedRecord returns a DNSRecord of the host for the owner, labeled with the ownerLabel
+// Records are deleted with their DNSRecord unless the spec sets a DeletionPolicy
+func ownedRecord(owner client.Object, ownerLabel string, spec ddnsv1alpha1.DNSRecordSpec) ddnsv1alpha1.DNSRecord {
+	// The default is set explicitly, so the DNSRecord is not updated again on every reconciliation
+	if spec.DeletionPolicy == "" {
+		spec.DeletionPolicy = ddnsv1alpha1.DeletionPolicyDelete
+	}
+
+	labels := maps.Clone(owner.GetLabels())
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ownerLabel] = owner.GetName()
+
+	return ddnsv1alpha1.DNSRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ownedRecordName(owner.GetName(), spec.Name, spec.Type),
+			Namespace: owner.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+}
+
+// ownedRecordName returns the name of the DNSRecord of a host of the owner. Hosts are hashed, as they may be longer
+// than a name allows or contain a wildcard
+func ownedRecordName(owner, host, recordType string) string {
+	sum := sha256.Sum256([]byte(host))
+	suffix := fmt.Sprintf("-%s-%s", strings.ToLower(recordType), hex.EncodeToString(sum[:])[:10])
+
+	// Names of DNSRecords are limited to 253 characters
+	if len(owner)+len(suffix) > 253 {
+		owner = strings.TrimRight(owner[:253-len(suffix)], "-.")
+	}
+
+	return owner + suffix
+}
+
+// syncOwnedRecords creates or updates the desired DNSRecords of the owner and deletes the ones it owns that are not
+// desired anymore, e.g. of a removed host
+func syncOwnedRecords(
+	ctx context.Context,
+	c client.Client,
+	scheme *runtime.Scheme,
+	owner client.Object,
+	ownerLabel string,
+	desired []ddnsv1alpha1.DNSRecord,
+) error {
+	log := log.FromContext(ctx)
+
+	for _, record := range desired {
+		spec := record.Spec
+		labels := record.Labels
+
+		result, err := controllerutil.CreateOrUpdate(ctx, c, &record, func() error {
+			record.Labels = labels
+			record.Spec = spec
+
+			return controllerutil.SetControllerReference(owner, &record, scheme)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to update DNSRecord %s: %w", record.Name, err)
+		}
+
+		if result != controllerutil.OperationResultNone {
+			log.Info("Owned DNSRecord updated", "record", record.Name, "host", spec.Name, "operation", result)
+		}
+	}
+
+	records := &ddnsv1alpha1.DNSRecordList{}
+	if err := c.List(ctx, records, client.InNamespace(owner.GetNamespace()), client.MatchingLabels{ownerLabel: owner.GetName()}); err != nil {
+		return fmt.Errorf("unable to list the owned DNSRecords: %w", err)
+	}
+
+	for _, record := range records.Items {
+		if !metav1.IsControlledBy(&record, owner) {
+			continue
+		}
+
+		if slices.ContainsFunc(desired, func(d ddnsv1alpha1.DNSRecord) bool { return d.Name == record.Name }) {
+			continue
+		}
+
+		log.Info("Owned DNSRecord is no longer needed, deleting it", "record", record.Name, "host", record.Spec.Name)
+
+		if err := c.Delete(ctx, &record); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("unable to delete DNSRecord %s: %w", record.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// publicIPRecords returns the DNSRecords of the hosts of the owner that follow the public IP of the Provider named in its
+// IngressProviderAnnotation: one for every host in the zone and every IP family managed by the Provider. Owners without
+// the annotation need none
+func publicIPRecords(
+	ctx context.Context,
+	c client.Reader,
+	namespaceScoped bool,
+	owner client.Object,
+	ownerLabel string,
+	hosts []string,
+) ([]ddnsv1alpha1.DNSRecord, error) {
+	annotations := owner.GetAnnotations()
+	if annotations[ddnsv1alpha1.IngressProviderAnnotation] == "" {
+		return nil, nil
+	}
+
+	ref, err := annotatedProviderRef(annotations[ddnsv1alpha1.IngressProviderAnnotation])
+	if err != nil {
+		return nil, err
+	}
+
+	zone, err := annotatedZone(annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := getProvider(ctx, c, namespaceScoped, ref, owner.GetNamespace())
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s %s: %w", ref.Kind, ref.Name, err)
+	}
+
+	recordTypes, err := ingressRecordTypes(annotations, provider.GetProviderSpec().IPVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := annotatedTTL(annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	deletionPolicy, err := annotatedDeletionPolicy(annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []ddnsv1alpha1.DNSRecord{}
+	for _, host := range hosts {
+		if !inZone(host, zone) {
+			log.FromContext(ctx).Info("Host is not in the zone, skipping it", "owner", owner.GetName(), "host", host, "zone", zone)
+			continue
+		}
+
+		for _, recordType := range recordTypes {
+			records = append(records, ownedRecord(owner, ownerLabel, ddnsv1alpha1.DNSRecordSpec{
+				ProviderRef:    ref,
+				Zone:           zone,
+				Name:           host,
+				Type:           recordType,
+				TTL:            ttl,
+				Proxied:        annotations[ddnsv1alpha1.IngressProxiedAnnotation] == "true",
+				DeletionPolicy: deletionPolicy,
+			}))
+		}
+	}
+
+	return records, nil
+}
+
+// annotatedForProvider maps a Provider or ClusterProvider to the resources of the kind, read as unstructured objects, that
+// name it in their IngressProviderAnnotation, so their records follow a change of its IP families
+// ClusterProviders are not namespaced, so the resources of all namespaces are listed for them
+func annotatedForProvider(ctx context.Context, c client.Reader, gvk schema.GroupVersionKind, provider client.Object) []reconcile.Request {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, list, client.InNamespace(provider.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list "+gvk.Kind+"s")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, item := range list.Items {
+		ref, err := annotatedProviderRef(item.GetAnnotations()[ddnsv1alpha1.IngressProviderAnnotation])
+		if err != nil || ref.Name == "" {
+			continue
+		}
+
+		if refersTo(ref, provider) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+		}
+	}
+
+	return requests
+}
+
+// annotatedProviderRef parses the value of the IngressProviderAnnotation: the name of a Provider, or ClusterProvider/<name>
+func annotatedProviderRef(value string) (ddnsv1alpha1.ProviderRef, error) {
+	kind, name, found := strings.Cut(value, "/")
+	if !found {
+		return ddnsv1alpha1.ProviderRef{Kind: ddnsv1alpha1.ProviderKind, Name: value}, nil
+	}
+
+	if (kind != ddnsv1alpha1.ProviderKind && kind != ddnsv1alpha1.ClusterProviderKind) || name == "" {
+		return ddnsv1alpha1.ProviderRef{}, fmt.Errorf("invalid %s annotation %q, expected <name> or ClusterProvider/<name>",
+			ddnsv1alpha1.IngressProviderAnnotation, value)
+	}
+
+	return ddnsv1alpha1.ProviderRef{Kind: kind, Name: name}, nil
+}
+
+// annotatedZone returns the value of the IngressZoneAnnotation, which is required
+func annotatedZone(annotations map[string]string) (string, error) {
+	zone := strings.TrimSuffix(strings.ToLower(annotations[ddnsv1alpha1.IngressZoneAnnotation]), ".")
+	if zone == "" {
+		return "", fmt.Errorf("the %s annotation is required", ddnsv1alpha1.IngressZoneAnnotation)
+	}
+
+	return zone, nil
+}
+
+// annotatedTTL returns the value of the IngressTTLAnnotation, or 1, meaning automatic, if it is not set
+func annotatedTTL(annotations map[string]string) (int, error) {
+	value, ok := annotations[ddnsv1alpha1.IngressTTLAnnotation]
+	if !ok {
+		return 1, nil
+	}
+
+	ttl, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || ttl < 1 {
+		return 0, fmt.Errorf("invalid %s annotation %q, expected a number of seconds of at least 1", ddnsv1alpha1.IngressTTLAnnotation, value)
+	}
+
+	return ttl, nil
+}
+
+// annotatedDeletionPolicy returns the value of the IngressDeletionPolicyAnnotation, or Delete if it is not set
+func annotatedDeletionPolicy(annotations map[string]string) (ddnsv1alpha1.DeletionPolicy, error) {
+	switch policy := ddnsv1alpha1.DeletionPolicy(annotations[ddnsv1alpha1.IngressDeletionPolicyAnnotation]); policy {
+	case "":
+		return ddnsv1alpha1.DeletionPolicyDelete, nil
+	case ddnsv1alpha1.DeletionPolicyOrphan, ddnsv1alpha1.DeletionPolicyDelete:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid %s annotation %q, expected Orphan or Delete", ddnsv1alpha1.IngressDeletionPolicyAnnotation, policy)
+	}
+}
+
+// inZone returns true if the host is the zone itself or one of its subdomains
+func inZone(host, zone string) bool {
+	return host == zone || strings.HasSuffix(host, "."+zone)
+}
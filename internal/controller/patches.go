@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+)
+
+// FieldOwner is the field manager of every write of the controller, so server-side apply and GitOps tools can tell the
+// fields the controller manages, i.e. the finalizers and the status, from the ones of the applied manifests.
+// The status is server-side applied with it by conditions.ApplyStatus
+const FieldOwner = conditions.FieldOwner
+
+// NewClient creates the client of the manager, which writes as the FieldOwner
+func NewClient(config *rest.Config, options client.Options) (client.Client, error) {
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.WithFieldOwner(c, FieldOwner), nil
+}
+
+// finalizersPatch returns a merge patch from the current state of the object for adding or removing a finalizer.
+// A merge patch replaces the whole list of finalizers, so the patch is locked to the resourceVersion of the object: a
+// concurrent change of the finalizers, e.g. by a server-side apply, fails with a conflict and is retried instead of
+// being overwritten
+func finalizersPatch(obj client.Object) client.Patch {
+	return client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+}
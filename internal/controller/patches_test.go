@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+)
+
+// statusPatchCounter counts the status patches sent through the client and fails them with err, if set
+type statusPatchCounter struct {
+	client.Client
+	patches *int
+	err     error
+}
+
+func (c statusPatchCounter) Status() client.SubResourceWriter {
+	return statusPatchCounterWriter{SubResourceWriter: c.Client.Status(), patches: c.patches, err: c.err}
+}
+
+type statusPatchCounterWriter struct {
+	client.SubResourceWriter
+	patches *int
+	err     error
+}
+
+func (w statusPatchCounterWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	*w.patches++
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+var _ = Describe("Finalizers patch", func() {
+	ctx := context.Background()
+
+	It("should not overwrite the finalizers that were changed concurrently", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-finalizers-patch", Namespace: "default"},
+		}
+		Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			configMap.Finalizers = nil
+			Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+		})
+
+		stale := configMap.DeepCopy()
+
+		By("Adding a finalizer with another field manager, e.g. by a server-side apply")
+		patch := client.MergeFrom(configMap.DeepCopy())
+		controllerutil.AddFinalizer(configMap, "example.com/applied")
+		Expect(k8sClient.Patch(ctx, configMap, patch, client.FieldOwner("kubectl"))).To(Succeed())
+
+		patch = finalizersPatch(stale)
+		controllerutil.AddFinalizer(stale, "example.com/controller")
+		Expect(errors.IsConflict(k8sClient.Patch(ctx, stale, patch))).To(BeTrue())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+		Expect(configMap.Finalizers).To(Equal([]string{"example.com/applied"}))
+	})
+})
+
+var _ = Describe("Conditions set", func() {
+	ctx := context.Background()
+
+	It("should patch all the changed conditions at once", func() {
+		provider := &ddnsv1alpha1.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-conditions-set", Namespace: "default"},
+			Spec: ddnsv1alpha1.ProviderSpec{
+				Name:       "Cloudflare",
+				SecretName: "test-conditions-set",
+				ConfigMap:  "test-conditions-set",
+			},
+		}
+		Expect(k8sClient.Create(ctx, provider)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, provider)
+
+		patches := 0
+		counter := statusPatchCounter{Client: k8sClient, patches: &patches}
+
+		set := conditions.PatchConditionsSet(provider)
+		set.Set(ddnsv1alpha1.ProviderConditionTypeSecret, conditions.WithReasonAndMessage("SecretFound", "Secret found"), conditions.True())
+		set.Set(ddnsv1alpha1.ProviderConditionTypeConfigMap, conditions.WithReasonAndMessage("ConfigMapFound", "ConfigMap found"), conditions.True())
+		set.Remove(ddnsv1alpha1.ProviderConditionTypeDryRun)
+		Expect(set.Patch(ctx, counter)).To(Succeed())
+		Expect(patches).To(Equal(1))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+		Expect(provider.Conditions().GetCondition(ddnsv1alpha1.ProviderConditionTypeSecret).Status).To(Equal(metav1.ConditionTrue))
+		Expect(provider.Conditions().GetCondition(ddnsv1alpha1.ProviderConditionTypeConfigMap).Status).To(Equal(metav1.ConditionTrue))
+
+		By("Not patching conditions that did not change")
+		set = conditions.PatchConditionsSet(provider)
+		set.Set(ddnsv1alpha1.ProviderConditionTypeSecret, conditions.WithReasonAndMessage("SecretFound", "Secret found"), conditions.True())
+		set.Remove(ddnsv1alpha1.ProviderConditionTypeDryRun)
+		Expect(set.Patch(ctx, counter)).To(Succeed())
+		Expect(patches).To(Equal(1))
+	})
+})
+
+var _ = Describe("Status apply", func() {
+	ctx := context.Background()
+
+	It("should own the whole status, including the fields that were patched before", func() {
+		provider := &ddnsv1alpha1.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-status-apply", Namespace: "default"},
+			Spec: ddnsv1alpha1.ProviderSpec{
+				Name:       "Cloudflare",
+				SecretName: "test-status-apply",
+				ConfigMap:  "test-status-apply",
+			},
+		}
+		Expect(k8sClient.Create(ctx, provider)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, provider)
+
+		By("Patching the status like the controller did before it was applied")
+		patch := client.MergeFrom(provider.DeepCopy())
+		provider.Status.DesyncedSince = &metav1.Time{Time: metav1.Now().Rfc3339Copy().Time}
+		Expect(k8sClient.Status().Patch(ctx, provider, patch, client.FieldOwner("manager"))).To(Succeed())
+
+		provider.Status.ProviderIP = "1.2.3.4"
+		Expect(conditions.ApplyStatus(ctx, k8sClient, provider)).To(Succeed())
+		Expect(provider.Status.ProviderIP).To(Equal("1.2.3.4"))
+		Expect(provider.Status.DesyncedSince).NotTo(BeNil())
+
+		Expect(provider.ManagedFields).To(ContainElement(SatisfyAll(
+			HaveField("Manager", FieldOwner),
+			HaveField("Operation", metav1.ManagedFieldsOperationApply),
+			HaveField("Subresource", "status"),
+		)))
+
+		By("Removing the fields that are unset")
+		provider.Status.DesyncedSince = nil
+		Expect(conditions.ApplyStatus(ctx, k8sClient, provider)).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+		Expect(provider.Status.DesyncedSince).To(BeNil())
+		Expect(provider.Status.ProviderIP).To(Equal("1.2.3.4"))
+	})
+
+	It("should not apply the status of a stale resource", func() {
+		provider := &ddnsv1alpha1.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-stale-status-apply", Namespace: "default"},
+			Spec: ddnsv1alpha1.ProviderSpec{
+				Name:       "Cloudflare",
+				SecretName: "test-stale-status-apply",
+				ConfigMap:  "test-stale-status-apply",
+			},
+		}
+		Expect(k8sClient.Create(ctx, provider)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, provider)
+
+		stale := provider.DeepCopy()
+
+		provider.Status.SyncCount = 2
+		Expect(conditions.ApplyStatus(ctx, k8sClient, provider)).To(Succeed())
+
+		By("Applying the next status of the same resource")
+		provider.Status.SyncCount = 3
+		Expect(conditions.ApplyStatus(ctx, k8sClient, provider)).To(Succeed())
+
+		stale.Status.SyncCount = 1
+		Expect(errors.IsConflict(conditions.ApplyStatus(ctx, k8sClient, stale))).To(BeTrue())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+		Expect(provider.Status.SyncCount).To(Equal(int64(3)))
+	})
+})
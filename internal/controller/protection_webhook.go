@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// Validators runs every validator and joins their warnings and errors, as only one validator can be registered per type
+type Validators []admission.CustomValidator
+
+var _ admission.CustomValidator = Validators{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v Validators) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(func(validator admission.CustomValidator) (admission.Warnings, error) {
+		return validator.ValidateCreate(ctx, obj)
+	})
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v Validators) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(func(validator admission.CustomValidator) (admission.Warnings, error) {
+		return validator.ValidateUpdate(ctx, oldObj, newObj)
+	})
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v Validators) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(func(validator admission.CustomValidator) (admission.Warnings, error) {
+		return validator.ValidateDelete(ctx, obj)
+	})
+}
+
+func (v Validators) validate(
+	validate func(validator admission.CustomValidator) (admission.Warnings, error),
+) (admission.Warnings, error) {
+	warnings := admission.Warnings{}
+	errs := []error{}
+
+	for _, validator := range v {
+		w, err := validate(validator)
+		warnings = append(warnings, w...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return warnings, errors.Join(errs...)
+}
+
+// +kubebuilder:webhook:path=/validate-ddns-stefangenov-site-v1alpha1-provider-delete,mutating=false,failurePolicy=ignore,sideEffects=None,groups=ddns.stefangenov.site,resources=providers,verbs=delete,versions=v1alpha1,name=vprovider-delete.kb.io,admissionReviewVersions=v1,timeoutSeconds=5
+// +kubebuilder:webhook:path=/validate-ddns-stefangenov-site-v1alpha1-clusterprovider-delete,mutating=false,failurePolicy=ignore,sideEffects=None,groups=ddns.stefangenov.site,resources=clusterproviders,verbs=delete,versions=v1alpha1,name=vclusterprovider-delete.kb.io,admissionReviewVersions=v1,timeoutSeconds=5
+
+// DeletionProtectionValidator denies the deletion of Providers and ClusterProviders with the ProtectedAnnotation while their
+// records are still in use: records the Provider keeps pointed at the public IP, or DNSRecords and Zones that reference it.
+// It is served by its own webhooks, which only handle deletions and ignore failures, so the deletions are not blocked while
+// the webhook is unavailable, e.g. when the namespace of the controller is deleted
+type DeletionProtectionValidator struct {
+	Reader client.Reader
+}
+
+// SetupWebhookWithManager registers the webhooks of the deletions of Providers and ClusterProviders
+func (v *DeletionProtectionValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register("/validate-ddns-stefangenov-site-v1alpha1-provider-delete",
+		admission.WithCustomValidator(mgr.GetScheme(), &ddnsv1alpha1.Provider{}, v))
+	server.Register("/validate-ddns-stefangenov-site-v1alpha1-clusterprovider-delete",
+		admission.WithCustomValidator(mgr.GetScheme(), &ddnsv1alpha1.ClusterProvider{}, v))
+
+	return nil
+}
+
+var _ admission.CustomValidator = &DeletionProtectionValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *DeletionProtectionValidator) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *DeletionProtectionValidator) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *DeletionProtectionValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	provider, ok := obj.(ddnsv1alpha1.ProviderObject)
+	if !ok {
+		return nil, fmt.Errorf("expected a Provider or a ClusterProvider but got a %T", obj)
+	}
+
+	if provider.GetAnnotations()[ddnsv1alpha1.ProtectedAnnotation] != "true" {
+		return nil, nil
+	}
+
+	inUse, err := v.recordsInUse(ctx, provider)
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify if the records of the protected %s %s are in use: %w",
+			providerKind(provider), provider.GetName(), err)
+	}
+
+	if len(inUse) == 0 {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf(
+		"%s %s is protected by the %s annotation and its records are still in use by %s, remove the annotation to delete it",
+		strings.ToLower(providerKind(provider)), provider.GetName(), ddnsv1alpha1.ProtectedAnnotation, strings.Join(inUse, ", "),
+	)
+}
+
+// recordsInUse describes what still uses the records of the provider. The DNSRecords and Zones of every namespace can
+// reference a ClusterProvider
+func (v *DeletionProtectionValidator) recordsInUse(ctx context.Context, provider ddnsv1alpha1.ProviderObject) ([]string, error) {
+	inUse := []string{}
+
+	for _, record := range provider.GetProviderStatus().Records {
+		if record.CurrentValue != "" {
+			inUse = append(inUse, fmt.Sprintf("record %s (%s)", record.FQDN, record.Type))
+		}
+	}
+
+	records := &ddnsv1alpha1.DNSRecordList{}
+	if err := v.Reader.List(ctx, records, client.InNamespace(provider.GetNamespace())); err != nil {
+		return nil, err
+	}
+
+	for _, record := range records.Items {
+		if refersTo(record.Spec.ProviderRef, provider) {
+			inUse = append(inUse, "DNSRecord "+inUseName(provider, &record))
+		}
+	}
+
+	zones := &ddnsv1alpha1.ZoneList{}
+	if err := v.Reader.List(ctx, zones, client.InNamespace(provider.GetNamespace())); err != nil {
+		return nil, err
+	}
+
+	for _, zone := range zones.Items {
+		if refersTo(zone.Spec.ProviderRef, provider) {
+			inUse = append(inUse, "Zone "+inUseName(provider, &zone))
+		}
+	}
+
+	return inUse, nil
+}
+
+// inUseName returns the name of the resource using the records of the provider, with its namespace for a ClusterProvider
+func inUseName(provider ddnsv1alpha1.ProviderObject, obj client.Object) string {
+	if provider.GetNamespace() == "" {
+		return client.ObjectKeyFromObject(obj).String()
+	}
+
+	return obj.GetName()
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// fakeValidator returns the same warnings and error for every request
+type fakeValidator struct {
+	warnings admission.Warnings
+	err      error
+}
+
+func (v fakeValidator) ValidateCreate(context.Context, runtime.Object) (admission.Warnings, error) {
+	return v.warnings, v.err
+}
+
+func (v fakeValidator) ValidateUpdate(context.Context, runtime.Object, runtime.Object) (admission.Warnings, error) {
+	return v.warnings, v.err
+}
+
+func (v fakeValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return v.warnings, v.err
+}
+
+var _ = Describe("Deletion protection validator", func() {
+	ctx := context.Background()
+
+	validator := &DeletionProtectionValidator{Reader: k8sClient}
+
+	protectedProvider := func() *ddnsv1alpha1.Provider {
+		return &ddnsv1alpha1.Provider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-protected-provider",
+				Namespace:   "default",
+				Annotations: map[string]string{ddnsv1alpha1.ProtectedAnnotation: "true"},
+			},
+		}
+	}
+
+	It("should allow the deletion of a protected Provider whose records are not in use", func() {
+		provider := protectedProvider()
+		provider.Status.Records = []ddnsv1alpha1.RecordStatus{{FQDN: "home.example.com", Type: "A", DesiredValue: "1.2.3.4"}}
+
+		_, err := validator.ValidateDelete(ctx, provider)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny the deletion of a protected Provider that still manages records", func() {
+		provider := protectedProvider()
+		provider.Status.Records = []ddnsv1alpha1.RecordStatus{
+			{FQDN: "home.example.com", Type: "A", CurrentValue: "1.2.3.4", DesiredValue: "1.2.3.4"},
+		}
+
+		_, err := validator.ValidateDelete(ctx, provider)
+		Expect(err).To(MatchError(ContainSubstring("record home.example.com (A)")))
+
+		By("Allowing the deletion once the annotation is removed")
+		provider.Annotations = nil
+
+		_, err = validator.ValidateDelete(ctx, provider)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny the deletion of a protected Provider that is referenced by DNSRecords", func() {
+		record := &ddnsv1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-protected-record", Namespace: "default"},
+			Spec: ddnsv1alpha1.DNSRecordSpec{
+				ProviderRef: ddnsv1alpha1.ProviderRef{Name: "test-protected-provider"},
+				Zone:        "example.com",
+				Name:        "home.example.com",
+			},
+		}
+		Expect(k8sClient.Create(ctx, record)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, record)
+
+		_, err := validator.ValidateDelete(ctx, protectedProvider())
+		Expect(err).To(MatchError(ContainSubstring("DNSRecord test-protected-record")))
+
+		By("Ignoring the DNSRecords of a ClusterProvider of the same name")
+		record.Spec.ProviderRef.Kind = ddnsv1alpha1.ClusterProviderKind
+		Expect(k8sClient.Update(ctx, record)).To(Succeed())
+
+		_, err = validator.ValidateDelete(ctx, protectedProvider())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny the deletion of a protected ClusterProvider that is referenced by DNSRecords of any namespace", func() {
+		clusterProvider := &ddnsv1alpha1.ClusterProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-protected-clusterprovider",
+				Annotations: map[string]string{ddnsv1alpha1.ProtectedAnnotation: "true"},
+			},
+		}
+
+		_, err := validator.ValidateDelete(ctx, clusterProvider)
+		Expect(err).NotTo(HaveOccurred())
+
+		record := &ddnsv1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-protected-cluster-record", Namespace: "default"},
+			Spec: ddnsv1alpha1.DNSRecordSpec{
+				ProviderRef: ddnsv1alpha1.ProviderRef{
+					Name: "test-protected-clusterprovider",
+					Kind: ddnsv1alpha1.ClusterProviderKind,
+				},
+				Zone: "example.com",
+				Name: "home.example.com",
+			},
+		}
+		Expect(k8sClient.Create(ctx, record)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, record)
+
+		_, err = validator.ValidateDelete(ctx, clusterProvider)
+		Expect(err).To(MatchError(And(
+			HavePrefix("clusterprovider test-protected-clusterprovider is protected"),
+			ContainSubstring("DNSRecord default/test-protected-cluster-record"),
+		)))
+	})
+})
+
+var _ = Describe("Validators", func() {
+	It("should join the warnings and errors of every validator", func() {
+		validators := Validators{
+			fakeValidator{warnings: admission.Warnings{"first"}},
+			fakeValidator{warnings: admission.Warnings{"second"}, err: errors.New("denied")},
+		}
+
+		warnings, err := validators.ValidateDelete(context.Background(), &ddnsv1alpha1.Provider{})
+		Expect(warnings).To(Equal(admission.Warnings{"first", "second"}))
+		Expect(err).To(MatchError("denied"))
+	})
+})
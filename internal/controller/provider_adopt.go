@@ -0,0 +1,75 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// adopting returns true if the existing records of the provider have to be adopted: it sets AdoptExisting and was never
+// synced before. Dry runs do not adopt, as adopting marks the records as owned
+func adopting(provider ddnsv1alpha1.ProviderObject) bool {
+	spec := provider.GetProviderSpec()
+	status := provider.GetProviderStatus()
+
+	return spec.AdoptExisting && !spec.DryRun && status.AdoptedTime == nil && status.LastSyncTime == nil
+}
+
+// adoptionHeld returns true if the adopted records of the family are left at their current values: the public IP did not
+// change since they were adopted
+func adoptionHeld(spec *ddnsv1alpha1.ProviderSpec, status *ddnsv1alpha1.ProviderStatus, family ipFamily) bool {
+	adoptedIp := *family.adoptedIp(status)
+
+	return spec.AdoptExisting && adoptedIp != "" && adoptedIp == *family.publicIp(status)
+}
+
+// adoptRecords marks the existing records as owned, if the client keeps track of the records it owns
+func adoptRecords(providerClient clients.Client, records []clients.DNSRecord) error {
+	adopter, ok := providerClient.(clients.RecordsAdopter)
+	if !ok {
+		return nil
+	}
+
+	return adopter.AdoptRecords(records)
+}
+
+// adoptedRecordStatuses returns the state of the adopted records at the provider. Existing records are in sync with their
+// current value and settings, as they are not updated until the public IP changes. Records that do not exist are compared
+// to the public IP
+func adoptedRecordStatuses(records []clients.DNSRecord, publicIp string) []ddnsv1alpha1.RecordStatus {
+	statuses := recordStatuses(records, publicIp)
+	for i, record := range records {
+		if record.Content == "" {
+			continue
+		}
+
+		statuses[i].DesiredValue = record.Content
+		statuses[i].Synced = true
+	}
+
+	return statuses
+}
+
+// patchAdoptedIp sets the public IP the records of the family were adopted at, or clears it once they are managed
+func (p ProviderReconciler) patchAdoptedIp(family ipFamily, adoptedIp string) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		current := family.adoptedIp(provider.GetProviderStatus())
+		if *current == adoptedIp {
+			return false
+		}
+
+		*current = adoptedIp
+
+		return true
+	}
+}
+
+func (p ProviderReconciler) patchAdoptedTime() func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		now := metav1.Now()
+		provider.GetProviderStatus().AdoptedTime = &now
+
+		return true
+	}
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// backendsClient routes the records of the Provider to its backends by domain suffix and returns a client that manages
+// all of them. Records that match no backend are managed with the secret and config map of the Provider itself
+func (r *ProviderReconciler) backendsClient(
+	ctx context.Context,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+	secret *corev1.Secret,
+	configMap *corev1.ConfigMap,
+) (clients.Client, error) {
+	spec := provider.GetProviderSpec()
+
+	if configMap.Data["records"] == "" {
+		return nil, fmt.Errorf("backends require the records of the Provider to be set in `records`")
+	}
+
+	var records []clients.RecordConfig
+	if err := json.Unmarshal([]byte(configMap.Data["records"]), &records); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the records: %s", err)
+	}
+
+	routed := routeRecords(records, spec.Backends)
+	backendClients := []clients.Client{}
+
+	if len(routed[0]) > 0 {
+		providerConfig, err := withRecords(configMap, routed[0])
+		if err != nil {
+			return nil, err
+		}
+
+		providerClient, err := r.ClientFactory(ctx, spec.Name, secret, providerConfig, log.FromContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		backendClients = append(backendClients, providerClient)
+	}
+
+	for i, backend := range spec.Backends {
+		if len(routed[i+1]) == 0 {
+			continue
+		}
+
+		backendClient, err := r.backendClient(ctx, namespace, backend, routed[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("backend %s: %w", backend.DomainSuffix, err)
+		}
+
+		backendClients = append(backendClients, backendClient)
+	}
+
+	return clients.NewMultiClient(backendClients...), nil
+}
+
+// backendClient creates the client of a single backend that manages the given records
+func (r *ProviderReconciler) backendClient(
+	ctx context.Context,
+	namespace string,
+	backend ddnsv1alpha1.ProviderBackend,
+	records []clients.RecordConfig,
+) (clients.Client, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: backend.SecretRef.Name, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if backend.ConfigRef != nil {
+		if err := r.Get(ctx, types.NamespacedName{Name: backend.ConfigRef.Name, Namespace: namespace}, configMap); err != nil {
+			return nil, err
+		}
+	}
+
+	configMap, err := withRecords(configMap, records)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ClientFactory(ctx, backend.Client, mapSecretKeys(secret, backend.SecretRef), configMap, log.FromContext(ctx))
+}
+
+// routeRecords assigns every record to the backend with the longest DomainSuffix it matches.
+// The first group holds the records that match no backend, group i+1 the records of backends[i]
+func routeRecords(records []clients.RecordConfig, backends []ddnsv1alpha1.ProviderBackend) [][]clients.RecordConfig {
+	routed := make([][]clients.RecordConfig, len(backends)+1)
+
+	for _, record := range records {
+		group, longest := 0, 0
+
+		for i, backend := range backends {
+			suffix := strings.ToLower(strings.TrimSuffix(backend.DomainSuffix, "."))
+			if len(suffix) > longest && inDomain(record.Name, suffix) {
+				group, longest = i+1, len(suffix)
+			}
+		}
+
+		routed[group] = append(routed[group], record)
+	}
+
+	return routed
+}
+
+// inDomain returns true if the name is the domain itself or one of its subdomains
+func inDomain(name, domain string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// withRecords returns a copy of the config map with the `records` key set to the given records
+func withRecords(configMap *corev1.ConfigMap, records []clients.RecordConfig) (*corev1.ConfigMap, error) {
+	encoded, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal the records: %w", err)
+	}
+
+	routed := configMap.DeepCopy()
+	if routed.Data == nil {
+		routed.Data = make(map[string]string)
+	}
+
+	routed.Data["records"] = string(encoded)
+
+	return routed, nil
+}
+
+// clientVersions returns the libraries of the clients of the Provider and its backends with their versions, joined with a comma
+func clientVersions(spec *ddnsv1alpha1.ProviderSpec) string {
+	names := []string{spec.Name}
+	for _, backend := range spec.Backends {
+		names = append(names, backend.Client)
+	}
+
+	versions := []string{}
+	for _, name := range names {
+		if clientVersion := clients.ClientVersion(name); clientVersion != "" && !slices.Contains(versions, clientVersion) {
+			versions = append(versions, clientVersion)
+		}
+	}
+
+	return strings.Join(versions, ", ")
+}
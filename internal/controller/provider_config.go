@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// ipFamilies returns the IP families that should be kept in sync based on the IPVersion of the Provider
+func ipFamilies(spec *ddnsv1alpha1.ProviderSpec, ipv4Provider, ipv6Provider IPProvider) []ipFamily {
+	families := []ipFamily{}
+
+	if spec.IPVersion.IPv4Enabled() {
+		families = append(families, ipFamily{
+			recordType: clients.RecordTypeA,
+			ipProvider: overrideIp(spec.OverrideIP, false, ipv4Provider),
+			publicIp:   func(status *ddnsv1alpha1.ProviderStatus) *string { return &status.PublicIP },
+			providerIp: func(status *ddnsv1alpha1.ProviderStatus) *string { return &status.ProviderIP },
+			adoptedIp:  func(status *ddnsv1alpha1.ProviderStatus) *string { return &status.AdoptedPublicIP },
+		})
+	}
+
+	if spec.IPVersion.IPv6Enabled() {
+		families = append(families, ipFamily{
+			recordType: clients.RecordTypeAAAA,
+			ipProvider: overrideIp(spec.OverrideIP, true, ipv6Provider),
+			publicIp:   func(status *ddnsv1alpha1.ProviderStatus) *string { return &status.PublicIPv6 },
+			providerIp: func(status *ddnsv1alpha1.ProviderStatus) *string { return &status.ProviderIPv6 },
+			adoptedIp:  func(status *ddnsv1alpha1.ProviderStatus) *string { return &status.AdoptedPublicIPv6 },
+		})
+	}
+
+	return families
+}
+
+// overrideIp returns an IPProvider that always returns the override, if it belongs to the given family.
+// Otherwise the detection is left to the given IPProvider.
+func overrideIp(override string, ipv6 bool, ipProvider IPProvider) IPProvider {
+	if override == "" {
+		return ipProvider
+	}
+
+	ip := net.ParseIP(override)
+	if ip == nil {
+		return func(context.Context, string) (string, error) {
+			return "", fmt.Errorf("overrideIP %q is not a valid IP address", override)
+		}
+	}
+
+	if (ip.To4() == nil) != ipv6 {
+		return ipProvider
+	}
+
+	return func(context.Context, string) (string, error) {
+		return override, nil
+	}
+}
+
+// driftCheckInterval returns the DriftCheckInterval of the provider, or the default of the controller if it does not set one
+func driftCheckInterval(spec *ddnsv1alpha1.ProviderSpec, defaultInterval time.Duration) time.Duration {
+	if interval := spec.GetDriftCheckInterval(); interval > 0 {
+		return interval
+	}
+
+	return defaultInterval
+}
+
+// driftCheckOnly returns true if the records of the provider do not have to be verified at the provider yet: it has a
+// drift check interval, its records were verified less than a RetryInterval ago and are in sync, it did not fail since, its
+// spec and resources did not change since and every detected public IP is still the one set at the provider, or the one its
+// adopted records were left at
+func driftCheckOnly(
+	provider ddnsv1alpha1.ProviderObject,
+	families []ipFamily,
+	observed ddnsv1alpha1.ObservedResources,
+	defaultInterval time.Duration,
+) bool {
+	spec := provider.GetProviderSpec()
+	status := provider.GetProviderStatus()
+
+	if driftCheckInterval(spec, defaultInterval) == 0 || status.LastSyncTime == nil || time.Since(status.LastSyncTime.Time) >= spec.GetRetryInterval() {
+		return false
+	}
+
+	if status.OutOfSyncSince != nil || status.ConsecutiveFailures > 0 || status.ObservedGeneration != provider.GetGeneration() ||
+		status.ObservedResources == nil || *status.ObservedResources != observed {
+		return false
+	}
+
+	for _, family := range families {
+		if *family.publicIp(status) != *family.providerIp(status) && !adoptionHeld(spec, status, family) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// driftCheckRequeue returns when to check the public IP again: after the drift check interval, but no later than when the
+// records have to be verified at the provider again
+func driftCheckRequeue(spec *ddnsv1alpha1.ProviderSpec, lastSync time.Time, defaultInterval time.Duration) time.Duration {
+	return min(driftCheckInterval(spec, defaultInterval), spec.GetRetryInterval()-time.Since(lastSync))
+}
+
+// errorRetryBackoff returns how long to wait before retrying a failed Provider, or 0 if neither the Provider nor the
+// controller set an ErrorRetryInterval. The ErrorRetryInterval doubles with every consecutive failure, up to the RetryInterval
+func errorRetryBackoff(provider ddnsv1alpha1.ProviderObject, defaultInterval time.Duration) time.Duration {
+	spec := provider.GetProviderSpec()
+
+	interval := spec.GetErrorRetryInterval()
+	if interval == 0 {
+		interval = defaultInterval
+	}
+
+	if interval == 0 {
+		return 0
+	}
+
+	for i := int64(1); i < provider.GetProviderStatus().ConsecutiveFailures; i++ {
+		interval *= 2
+		if interval >= spec.GetRetryInterval() {
+			return spec.GetRetryInterval()
+		}
+	}
+
+	return min(interval, spec.GetRetryInterval())
+}
+
+// degradedOptions returns the options for the Degraded condition of the provider after a reconciliation that failed with err,
+// if any. It is only True once the ConsecutiveFailures reach the FailureThreshold, so single transient errors are not reported
+func degradedOptions(provider ddnsv1alpha1.ProviderObject, err error) []conditions.ConditionOption {
+	failures := provider.GetProviderStatus().ConsecutiveFailures
+	threshold := provider.GetProviderSpec().GetFailureThreshold()
+
+	if err == nil {
+		return []conditions.ConditionOption{conditions.WithReasonAndMessage("Healthy", "Last reconciliation succeeded"), conditions.False()}
+	}
+
+	if failures < threshold {
+		return []conditions.ConditionOption{
+			conditions.WithReasonAndMessage("BelowThreshold", fmt.Sprintf("%d of %d consecutive failures: %s", failures, threshold, err)),
+			conditions.False(),
+		}
+	}
+
+	return []conditions.ConditionOption{
+		conditions.WithReasonAndMessage("FailureThresholdReached", fmt.Sprintf("%d consecutive failures: %s", failures, err)),
+		conditions.True(),
+	}
+}
+
+// outOfSyncRetryInterval is how long to wait before checking records that are still out of sync after an update
+const outOfSyncRetryInterval = time.Minute
+
+// outOfSyncBackoff returns how long to wait before retrying records that have been out of sync since the given time
+// The wait starts at the outOfSyncRetryInterval and grows with the time the records have been out of sync, so it roughly
+// doubles with every retry, up to the RetryInterval
+func outOfSyncBackoff(spec *ddnsv1alpha1.ProviderSpec, since time.Time) time.Duration {
+	return min(max(outOfSyncRetryInterval, time.Since(since)), spec.GetRetryInterval())
+}
+
+// recordsOutOfSync returns true if any of the records does not have the desired value
+func recordsOutOfSync(records []ddnsv1alpha1.RecordStatus) bool {
+	for _, record := range records {
+		if !record.Synced {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syncedOptions returns the options for the Synced condition from the records that were just verified at the provider
+// Records that are intentionally not updated, e.g. in DryRun mode or outside of the update windows, are still out of sync
+func syncedOptions(records []ddnsv1alpha1.RecordStatus) []conditions.ConditionOption {
+	if recordsOutOfSync(records) {
+		return []conditions.ConditionOption{conditions.WithReasonAndMessage("OutOfSync", recordsMessage(records)), conditions.False()}
+	}
+
+	return []conditions.ConditionOption{conditions.WithReasonAndMessage("InSync", recordsMessage(records)), conditions.True()}
+}
+
+// recordsMessage summarizes the state of the records, e.g. "All 4 records synced to 1.2.3.4"
+// or "1 of 4 records out of sync: www.example.com (A) is 1.2.3.3 instead of 1.2.3.4"
+func recordsMessage(records []ddnsv1alpha1.RecordStatus) string {
+	if len(records) == 0 {
+		return "No records managed"
+	}
+
+	desired := make([]string, 0, len(records))
+	var outOfSync []string
+
+	for _, record := range records {
+		desired = append(desired, record.DesiredValue)
+
+		if record.Synced {
+			continue
+		}
+
+		if record.CurrentValue == "" {
+			outOfSync = append(outOfSync, fmt.Sprintf("%s (%s) does not exist", record.FQDN, record.Type))
+			continue
+		}
+
+		if record.CurrentValue == record.DesiredValue && record.ProxiedDrift {
+			outOfSync = append(outOfSync, fmt.Sprintf("%s (%s) has the wrong proxied setting", record.FQDN, record.Type))
+			continue
+		}
+
+		outOfSync = append(outOfSync, fmt.Sprintf("%s (%s) is %s instead of %s", record.FQDN, record.Type, record.CurrentValue, record.DesiredValue))
+	}
+
+	if len(outOfSync) == 0 {
+		return fmt.Sprintf("All %d records synced to %s", len(records), strings.Join(uniqueIps(desired), ", "))
+	}
+
+	return fmt.Sprintf("%d of %d records out of sync: %s", len(outOfSync), len(records), strings.Join(outOfSync, ", "))
+}
+
+// uniqueIps will remove duplicates from a list of IPs
+func uniqueIps(ips []string) []string {
+	uniqueIps := make([]string, 0, len(ips))
+	ipMap := make(map[string]bool, len(ips))
+
+	for _, ip := range ips {
+		if !ipMap[ip] {
+			ipMap[ip] = true
+			uniqueIps = append(uniqueIps, ip)
+		}
+	}
+
+	return uniqueIps
+}
+
+// zonesConfigMap builds an in-memory ConfigMap holding the given zones, in the same format as a referenced ConfigMap
+// The zones are wrapped under the lowercased provider name, e.g. `{"cloudflare": {"zones": [...]}}`
+func zonesConfigMap(providerName string, zones []ddnsv1alpha1.ZoneConfig) (*corev1.ConfigMap, error) {
+	config, err := json.Marshal(map[string]any{
+		strings.ToLower(providerName): map[string]any{
+			"zones": zones,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal the inline config: %w", err)
+	}
+
+	return &corev1.ConfigMap{
+		Data: map[string]string{
+			"config": string(config),
+		},
+	}, nil
+}
+
+// recordsConfigMap builds an in-memory ConfigMap holding the given provider-neutral records under the `records` key
+func recordsConfigMap(records []ddnsv1alpha1.ManagedRecord) (*corev1.ConfigMap, error) {
+	config, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal the records: %w", err)
+	}
+
+	return &corev1.ConfigMap{
+		Data: map[string]string{
+			"records": string(config),
+		},
+	}, nil
+}
+
+// getProvider fetches the Provider or ClusterProvider that the ref points to
+// Providers are looked up in the given namespace, while ClusterProviders are cluster-scoped and cannot be fetched if
+// the controller is namespaceScoped
+func getProvider(
+	ctx context.Context,
+	c client.Reader,
+	namespaceScoped bool,
+	ref ddnsv1alpha1.ProviderRef,
+	namespace string,
+) (ddnsv1alpha1.ProviderObject, error) {
+	var provider ddnsv1alpha1.ProviderObject = &ddnsv1alpha1.Provider{}
+
+	key := types.NamespacedName{Name: ref.Name, Namespace: namespace}
+	if ref.IsClusterProvider() && namespaceScoped {
+		return nil, fmt.Errorf("ClusterProvider %s cannot be used, as the controller only watches some namespaces", ref.Name)
+	}
+
+	if ref.IsClusterProvider() {
+		provider = &ddnsv1alpha1.ClusterProvider{}
+		key.Namespace = ""
+	}
+
+	return provider, c.Get(ctx, key, provider)
+}
+
+// refersTo returns true if the ref points to the given Provider or ClusterProvider
+func refersTo(ref ddnsv1alpha1.ProviderRef, provider client.Object) bool {
+	return ref.Name == provider.GetName() && ref.IsClusterProvider() == (provider.GetNamespace() == "")
+}
+
+// resourceNamespace returns the namespace the secret and config map of the provider are read from
+// ClusterProviders are cluster-scoped, so they read them from the cluster resource namespace
+func resourceNamespace(provider ddnsv1alpha1.ProviderObject, clusterResourceNamespace string) string {
+	if provider.GetNamespace() == "" {
+		return clusterResourceNamespace
+	}
+
+	return provider.GetNamespace()
+}
+
+// fetchProviderSecret fetches the secret referenced by the provider, with the keys mapped to the expected names
+func fetchProviderSecret(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	spec *ddnsv1alpha1.ProviderSpec,
+) (*corev1.Secret, error) {
+	secretRef := spec.GetSecretRef()
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	return mapSecretKeys(secret, secretRef), nil
+}
+
+// fetchProviderConfig returns the inline config of the provider, or fetches the referenced config map
+func fetchProviderConfig(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	spec *ddnsv1alpha1.ProviderSpec,
+) (*corev1.ConfigMap, error) {
+	if spec.Config != nil {
+		return inlineConfigMap(spec)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: spec.ConfigMap, Namespace: namespace}, configMap); err != nil {
+		return nil, err
+	}
+
+	return configMap, nil
+}
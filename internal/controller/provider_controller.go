@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -25,30 +26,97 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
 	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
 	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/tracing"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/version"
 )
 
 type (
-	IPProvider    func(string) (string, error)
-	ClientFactory func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error)
+	IPProvider    func(ctx context.Context, customIPProvider string) (string, error)
+	ClientFactory func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error)
+	// Resolver looks up the IPs of the A or AAAA records of a name at the given DNS resolver
+	Resolver func(ctx context.Context, resolver, name, recordType string) ([]string, error)
 )
 
+// statusApplyTimeout is how long applying the status at the end of a reconciliation may take once it is canceled
+const statusApplyTimeout = 10 * time.Second
+
 // ProviderReconciler reconciles a Provider object
 type ProviderReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
 	IPProvider    IPProvider
+	IPv6Provider  IPProvider
 	ClientFactory ClientFactory
+	// Resolver is used to verify the propagation of the records of Providers with a PropagationCheck
+	Resolver Resolver
+	// ErrorRetryInterval is the ErrorRetryInterval of Providers that do not set one.
+	// If 0, their failures are returned to the controller and retried with its default rate limiter
+	ErrorRetryInterval time.Duration
+	// DriftCheckInterval is the DriftCheckInterval of Providers that do not set one. Between the verifications of their records,
+	// the API of the provider is only called if the public IP changed. If 0, their records are verified every RetryInterval
+	DriftCheckInterval time.Duration
+	// RecordsVerifyInterval is how long the records read from the DNS provider by a successful sync are trusted. Until then,
+	// syncs whose public IP did not change reuse them instead of listing the records at the provider again, while the ones
+	// whose public IP changed or that were requested still verify them. If 0, the records are listed by every sync
+	RecordsVerifyInterval time.Duration
+	// RequeueJitter spreads the requeues of Providers by adding up to this fraction of the interval to it, e.g. 0.1 for up to 10%,
+	// so Providers created at the same time do not sync at the same time. If 0, they are requeued after the exact interval
+	RequeueJitter float64
+	// SyncTimeout is how long detecting the public IP and syncing the records of a Provider may take before it is canceled
+	// and reported as a failure. If 0, it is only canceled when the controller stops
+	SyncTimeout time.Duration
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+	// IPChangeHistoryLimit is how many IPChanges are kept for every provider, the oldest ones are deleted first.
+	// No IPChanges are recorded if it is 0
+	IPChangeHistoryLimit int
+	// IPChangeTTL is how long IPChanges are kept. If 0, they are kept until the IPChangeHistoryLimit is reached
+	IPChangeTTL time.Duration
+	// HeartbeatInterval is how often at most the LastHeartbeatTime of the providers is updated, so external tooling can tell
+	// an idle controller from a dead one. Suspended providers are requeued after it to keep it updated. If 0, it is not updated
+	HeartbeatInterval time.Duration
+	// Recorder records events for the updated records, the records that are out of sync or were changed outside of the
+	// controller and the failed syncs. Optional
+	Recorder record.EventRecorder
+	// NamespaceScoped is set if the controller only watches some namespaces. Nodes cannot be read without cluster-wide RBAC,
+	// so Providers cannot take their public IP from a NodeAddress then
+	NamespaceScoped bool
+	// AllowCrossNamespaceNotifierRefs is the AllowCrossNamespaceRefs of the NotifierReconciler. notifierRefs to other
+	// namespaces are reported as missing in the NotifierRefs condition unless it is set
+	AllowCrossNamespaceNotifierRefs bool
+
+	// records caches the records read from the DNS providers. Records are listed by every sync if it is nil
+	records *providerRecords
+}
+
+// ipFamily describes how a single IP family is detected, stored in the status and set in the provider
+type ipFamily struct {
+	recordType string
+	ipProvider IPProvider
+	publicIp   func(status *ddnsv1alpha1.ProviderStatus) *string
+	providerIp func(status *ddnsv1alpha1.ProviderStatus) *string
+	adoptedIp  func(status *ddnsv1alpha1.ProviderStatus) *string
 }
 
 // +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch;create;update;patch;delete
@@ -56,91 +124,471 @@ type ProviderReconciler struct {
 // +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=notifiers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusternotifiers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile will reconcile the Provider object
 func (r *ProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	var (
-		err            error
-		providerClient clients.Client
-		providerIps    []string
-		publicIp       string
-	)
-
 	provider := &ddnsv1alpha1.Provider{}
-	if err = r.Get(ctx, req.NamespacedName, provider); err != nil {
+	if err := r.Get(ctx, req.NamespacedName, provider); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if publicIp, err = r.IPProvider(provider.Spec.CustomIPProvider); err != nil {
+	return r.reconcileProvider(ctx, req.Namespace, provider)
+}
+
+// reconcileProvider keeps the records of a Provider or ClusterProvider in sync with the public IP
+// The secret and config map of the provider are read from the given namespace
+func (r *ProviderReconciler) reconcileProvider(
+	ctx context.Context,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+) (_ ctrl.Result, reconcileErr error) {
+	ctx, span := tracing.Start(ctx, "Provider.Reconcile", tracing.ObjectAttributes(providerKind(provider), provider)...)
+	defer span.End()
+
+	if !provider.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, namespace, provider)
+	}
+
+	if err := r.ensureFinalizer(ctx, provider); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	provider.Conditions().FillConditions()
+	// The reconciliation only changes the status in memory, it is applied once when it returns
+	original := provider.GetProviderStatus().DeepCopy()
+	defer func() {
+		if err := r.applyStatus(ctx, provider, original); err != nil {
+			reconcileErr = errors.Join(reconcileErr, fmt.Errorf("unable to update the status: %w", err))
+		}
+	}()
 
-	if err = r.patchStatus(ctx, provider, r.patchPublicIp(publicIp)); err != nil {
-		return ctrl.Result{}, err
+	r.setStatus(provider, r.patchLastHeartbeatTime(time.Now()), r.patchVersions())
+
+	if provider.GetProviderSpec().Suspend {
+		log.FromContext(ctx).Info("Provider is suspended, skipping reconciliation")
+		return ctrl.Result{RequeueAfter: r.HeartbeatInterval}, nil
 	}
 
-	if providerClient, err = r.fetchClient(ctx, req, provider); err != nil {
+	if err := r.patchNotifierRefs(ctx, namespace, provider); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if providerIps, err = providerClient.GetIp(); err != nil {
-		return ctrl.Result{}, err
+	// A requested sync always verifies the records at the provider, even between drift checks
+	syncNow := syncRequested(provider)
+	r.setStatus(provider, r.patchLastHandledSyncNow())
+
+	syncCtx, syncSpan := tracing.Start(ctx, "Provider.Sync")
+	result, driftChecked, err := r.syncWithTimeout(syncCtx, namespace, provider, syncNow)
+	tracing.End(syncSpan, err)
+	recordReconcileResult(provider, err)
+	if err != nil {
+		r.eventf(provider, corev1.EventTypeWarning, "SyncFailed", "Could not sync the records: %s", err)
+		r.setStatus(provider, r.patchSyncResult(err))
+
+		set := conditions.PatchConditionsSet(provider)
+		set.Set(ddnsv1alpha1.ProviderConditionTypeReady, provider.Conditions().ReadyOptions(err)...)
+		set.Set(ddnsv1alpha1.ProviderConditionTypeDegraded, degradedOptions(provider, err)...)
+
+		errorRetryInterval := errorRetryBackoff(provider, r.ErrorRetryInterval)
+		if errorRetryInterval > 0 {
+			set.Set(ddnsv1alpha1.ProviderConditionTypeRetrying,
+				conditions.WithReasonAndMessage("BackingOff", fmt.Sprintf(
+					"%d consecutive failures, retrying in %s: %s",
+					provider.GetProviderStatus().ConsecutiveFailures, errorRetryInterval, err,
+				)),
+				conditions.True(),
+			)
+
+			log.FromContext(ctx).Error(err, "Reconciliation failed, retrying", "after", errorRetryInterval)
+			return ctrl.Result{RequeueAfter: errorRetryInterval}, nil
+		}
+
+		return result, err
 	}
 
-	// Remove duplicates
-	uniqueIps := r.uniqueIps(providerIps)
+	// A drift check did not verify the records, so it is not counted as a sync and leaves the result of the last one as is
+	if driftChecked {
+		return result, nil
+	}
 
-	if err := r.patchStatus(ctx, provider, r.patchProviderIp(strings.Join(uniqueIps, ", "))); err != nil {
-		return ctrl.Result{}, err
+	r.setStatus(provider, r.patchSyncResult(nil))
+
+	set := conditions.PatchConditionsSet(provider)
+	set.Set(ddnsv1alpha1.ProviderConditionTypeReady, provider.Conditions().ReadyOptions(nil)...)
+	set.Set(ddnsv1alpha1.ProviderConditionTypeDegraded, degradedOptions(provider, nil)...)
+
+	return result, nil
+}
+
+// syncProvider detects the public IP and updates the records at the provider if they are out of sync
+// Between the verifications of a Provider with a DriftCheckInterval, the records are only verified if the public IP changed,
+// unless syncNow is set. driftChecked is true if only the public IP was checked and the records were not verified
+func (r *ProviderReconciler) syncProvider(
+	ctx context.Context,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+	syncNow bool,
+) (result ctrl.Result, driftChecked bool, err error) {
+	var (
+		providerClient clients.Client
+		observed       ddnsv1alpha1.ObservedResources
+		providerIps    []string
+		publicIp       string
+		changes        []string
+		deferred       []string
+		updated        bool
+		drifted        []string
+		dnsRecords     []clients.DNSRecord
+		propagating    []clients.DNSRecord
+		records        []ddnsv1alpha1.RecordStatus
+		zoneErrs       = map[string]error{}
+		listed         = map[string][]clients.DNSRecord{}
+	)
+
+	spec := provider.GetProviderSpec()
+	status := provider.GetProviderStatus()
+
+	families := r.ipFamilies(provider)
+
+	provider.Conditions().FillConditions()
+
+	for _, family := range families {
+		ipCtx, span := tracing.Start(ctx, "Provider.DetectPublicIP", attribute.String("dns.record_type", family.recordType))
+		publicIp, err = family.ipProvider(ipCtx, spec.CustomIPProvider)
+		tracing.End(span, err)
+		if err != nil {
+			return ctrl.Result{}, false, err
+		}
+
+		r.setStatus(provider, r.patchPublicIp(family, publicIp))
 	}
 
-	if provider.Status.PublicIP != provider.Status.ProviderIP {
-		log.FromContext(ctx).Info("IPs desynced, updating provider IP")
+	if providerClient, observed, err = r.fetchClient(ctx, namespace, provider); err != nil {
+		return ctrl.Result{}, false, err
+	}
+
+	if !syncNow && driftCheckOnly(provider, families, observed, r.DriftCheckInterval) {
+		log.FromContext(ctx).Info("Public IP did not change, not verifying the records at the provider yet")
+
+		return ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: r.jitter(driftCheckRequeue(spec, status.LastSyncTime.Time, r.DriftCheckInterval)),
+		}, true, nil
+	}
+
+	windowOpen, nextWindow, err := updateWindowsOpen(spec.UpdateWindows, time.Now())
+	if err != nil {
+		return ctrl.Result{}, false, err
+	}
+
+	adopt := adopting(provider)
+	recordsKey := providerRecordsKey(provider, observed)
+
+	for _, family := range families {
+		verified, reuse := r.records.get(provider.GetUID(), recordsKey, family.recordType, r.RecordsVerifyInterval)
+		reuse = reuse && !syncNow && !adopt && verified.inSync(*family.publicIp(status))
+
+		if reuse {
+			log.FromContext(ctx).Info("Public IP did not change, reusing the records of the last sync instead of listing them",
+				"type", family.recordType, "verifiedAt", verified.verifiedAt)
 
-		if err := providerClient.SetIp(provider.Status.PublicIP); err != nil {
-			return ctrl.Result{}, err
+			providerIps, dnsRecords = recordContents(verified.records), verified.records
+		} else if providerIps, err = callProvider(ctx, provider, "GetIp", family.recordType, func() ([]string, error) {
+			return providerClient.GetIp(family.recordType)
+		}); err != nil && !mergeZoneErrors(zoneErrs, err) {
+			return ctrl.Result{}, false, err
 		}
 
-		if err := r.patchStatus(ctx, provider, r.patchProviderIp(provider.Status.PublicIP)); err != nil {
-			return ctrl.Result{}, err
+		// Remove duplicates
+		uniqueIps := uniqueIps(providerIps)
+
+		r.setStatus(provider, r.patchProviderIp(family, strings.Join(uniqueIps, ", ")))
+
+		publicIp := *family.publicIp(status)
+
+		if !reuse {
+			if dnsRecords, err = getRecords(ctx, provider, providerClient, family.recordType); err != nil && !mergeZoneErrors(zoneErrs, err) {
+				return ctrl.Result{}, false, err
+			}
+		}
+
+		if adopt {
+			log.FromContext(ctx).Info("Adopting the existing records, they are not updated until the public IP changes", "type", family.recordType)
+
+			err := callProviderErr(ctx, provider, "AdoptRecords", family.recordType, func() error {
+				return adoptRecords(providerClient, dnsRecords)
+			})
+			if err != nil && !mergeZoneErrors(zoneErrs, err) {
+				return ctrl.Result{}, false, err
+			}
+
+			r.setStatus(provider, r.patchAdoptedIp(family, publicIp))
+		} else if adoptedIp := *family.adoptedIp(status); adoptedIp != "" && adoptedIp != publicIp {
+			log.FromContext(ctx).Info("Public IP changed since the records were adopted, managing them from now on", "type", family.recordType)
+
+			r.setStatus(provider, r.patchAdoptedIp(family, ""))
+		}
+
+		drifted = append(drifted, driftedRecords(status.Records, dnsRecords, publicIp)...)
+
+		providerIp := *family.providerIp(status)
+		desynced := desyncedChanges(family.recordType, providerIp, publicIp, dnsRecords)
+
+		held := adoptionHeld(spec, status, family)
+		if held {
+			desynced = nil
+		}
+
+		if len(desynced) > 0 {
+			r.setStatus(provider, r.patchDesyncedSince())
 		}
+
+		// Only changes of the IP are recorded as IPChanges, not the records whose proxied setting drifted
+		ipChanged := len(desynced) > 0 && publicIp != providerIp
+		ipChange := newIPChange(provider, family.recordType, providerIp, publicIp, dnsRecords, syncNow)
+
+		if spec.DryRun {
+			if len(desynced) > 0 {
+				log.FromContext(ctx).Info("Records desynced, dry run enabled so not updating them", "type", family.recordType)
+				r.eventf(provider, corev1.EventTypeWarning, "RecordsDesynced", "Not updating the records in dry run: %s", strings.Join(desynced, ", "))
+				changes = append(changes, desynced...)
+			}
+
+			if ipChanged {
+				r.recordIPChange(ctx, namespace, provider, ipChange, ddnsv1alpha1.IPChangeOutcomeDryRun, nil)
+			}
+		} else if len(desynced) > 0 && !windowOpen {
+			log.FromContext(ctx).Info("Records desynced, outside of the update windows so not updating them", "type", family.recordType)
+			r.eventf(provider, corev1.EventTypeWarning, "RecordsDesynced", "Not updating the records outside of the update windows: %s", strings.Join(desynced, ", "))
+			deferred = append(deferred, desynced...)
+
+			if ipChanged {
+				r.recordIPChange(ctx, namespace, provider, ipChange, ddnsv1alpha1.IPChangeOutcomeDeferred, nil)
+			}
+		} else if len(desynced) > 0 {
+			log.FromContext(ctx).Info("Records desynced, updating them", "type", family.recordType, "changes", desynced)
+
+			err := callProviderErr(ctx, provider, "UpdateRecords", family.recordType, func() error {
+				return updateRecords(providerClient, publicIp, family.recordType, dnsRecords)
+			})
+
+			if ipChanged {
+				outcome := ddnsv1alpha1.IPChangeOutcomeUpdated
+				if err != nil {
+					outcome = ddnsv1alpha1.IPChangeOutcomeFailed
+				}
+
+				r.recordIPChange(ctx, namespace, provider, ipChange, outcome, err)
+			}
+
+			if err != nil && !mergeZoneErrors(zoneErrs, err) {
+				return ctrl.Result{}, false, err
+			}
+
+			recordUpdates.WithLabelValues(metricLabels(provider, family.recordType)...).Inc()
+			r.recordUpdatedEvents(provider, publicIp, dnsRecords, zoneErrs)
+
+			r.setStatus(provider, r.patchProviderIp(family, publicIp))
+
+			if publicIp != providerIp {
+				r.setStatus(provider, r.patchLastIPChangeTime())
+
+				ipChanges.WithLabelValues(metricLabels(provider, family.recordType)...).Inc()
+			}
+
+			updated = true
+
+			if dnsRecords, err = getRecords(ctx, provider, providerClient, family.recordType); err != nil && !mergeZoneErrors(zoneErrs, err) {
+				return ctrl.Result{}, false, err
+			}
+		}
+
+		if !reuse {
+			listed[family.recordType] = dnsRecords
+		}
+
+		if held {
+			records = append(records, adoptedRecordStatuses(dnsRecords, publicIp)...)
+			continue
+		}
+
+		records = append(records, recordStatuses(dnsRecords, publicIp)...)
+		propagating = append(propagating, propagatingRecords(dnsRecords, publicIp)...)
 	}
 
-	if err := r.patchStatus(ctx, provider, r.patchObservedGeneration()); err != nil {
-		return ctrl.Result{}, err
+	r.patchDriftDetected(ctx, provider, drifted)
+
+	// Records that are intentionally not updated are not out of sync
+	outOfSync := !spec.DryRun && len(deferred) == 0 && recordsOutOfSync(records)
+	if outOfSync {
+		log.FromContext(ctx).Info("Records are still out of sync after the update, retrying sooner")
+		r.eventf(provider, corev1.EventTypeWarning, "RecordsOutOfSync", "Records are still out of sync after the update")
+	}
+
+	pending := r.patchPropagated(ctx, provider, updated, propagating)
+	if len(pending) > 0 {
+		log.FromContext(ctx).Info("Records did not propagate to all the resolvers yet, retrying sooner", "pending", pending)
+		outOfSync = true
+	}
+
+	r.setStatus(provider, r.patchRecords(records, outOfSync))
+
+	zones := providerZones(providerClient, zoneErrs)
+	r.setStatus(provider, r.patchZones(zones, zoneErrs))
+
+	// The other zones were synced, but the reconciliation is retried like any failure until all of them are
+	if len(zoneErrs) > 0 {
+		r.records.delete(provider.GetUID())
+
+		return ctrl.Result{}, false, zonesError(zones, zoneErrs)
+	}
+
+	r.records.set(provider.GetUID(), recordsKey, listed)
+
+	// Zones that failed are adopted again by the next reconciliation, the ones already adopted are left as they are
+	if adopt {
+		r.setStatus(provider, r.patchAdoptedTime())
+	}
+
+	if !recordsOutOfSync(records) {
+		r.recordResynced(ctx, provider)
+	}
+
+	r.setStatus(provider, r.patchLastSyncTime())
+
+	recordLastSync(provider, provider.GetProviderStatus().LastSyncTime.Time)
+	r.pruneIPChanges(ctx, namespace, provider)
+
+	r.setStatus(provider, r.patchObservedGeneration(), r.patchObservedResources(observed))
+
+	set := conditions.PatchConditionsSet(provider)
+	set.Set(ddnsv1alpha1.ProviderConditionTypeSynced, syncedOptions(records)...)
+	setDryRun(set, provider, changes)
+	setUpdateWindow(set, provider, windowOpen, nextWindow, deferred)
+	set.Set(ddnsv1alpha1.ProviderConditionTypeResourcesChanged,
+		resourcesChangedOptions(provider.GetProviderStatus().ObservedResources, observed)...,
+	)
+
+	requeueAfter := spec.GetRetryInterval()
+	if outOfSync {
+		requeueAfter = outOfSyncBackoff(spec, status.OutOfSyncSince.Time)
+	} else if interval := driftCheckInterval(spec, r.DriftCheckInterval); interval > 0 && interval < requeueAfter {
+		requeueAfter = interval
+	}
+
+	requeueAfter = r.jitter(requeueAfter)
+
+	if untilWindow := time.Until(nextWindow); len(deferred) > 0 && !nextWindow.IsZero() && untilWindow < requeueAfter {
+		requeueAfter = untilWindow
 	}
 
 	return ctrl.Result{
 		Requeue:      true,
-		RequeueAfter: time.Second * time.Duration(provider.Spec.RetryInterval),
-	}, nil
+		RequeueAfter: requeueAfter,
+	}, false, nil
 }
 
 // =================================================== PRIVATE FUNCTIONS ===================================================
 
-// uniqueIps will remove duplicates from a list of IPs
-func (r *ProviderReconciler) uniqueIps(ips []string) []string {
-	uniqueIps := []string{}
-	ipMap := make(map[string]bool)
+// syncWithTimeout syncs the provider, canceling the sync after the SyncTimeout. The failure can still be reported with ctx.
+// The sync is not canceled together with ctx, so a controller that is stopped or hands over the leadership finishes the
+// records it is updating instead of leaving them half done. The next leader verifies them again either way
+func (r *ProviderReconciler) syncWithTimeout(
+	ctx context.Context,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+	syncNow bool,
+) (ctrl.Result, bool, error) {
+	if r.SyncTimeout <= 0 {
+		return r.syncProvider(ctx, namespace, provider, syncNow)
+	}
+
+	syncCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.SyncTimeout)
+	defer cancel()
 
-	for _, ip := range ips {
-		if !ipMap[ip] {
-			ipMap[ip] = true
-			uniqueIps = append(uniqueIps, ip)
+	result, driftChecked, err := r.syncProvider(syncCtx, namespace, provider, syncNow)
+	if err != nil && errors.Is(syncCtx.Err(), context.DeadlineExceeded) {
+		return result, driftChecked, fmt.Errorf("sync did not finish within %s: %w", r.SyncTimeout, err)
+	}
+
+	return result, driftChecked, err
+}
+
+// jitter adds up to the RequeueJitter of the interval to it
+func (r *ProviderReconciler) jitter(interval time.Duration) time.Duration {
+	if r.RequeueJitter <= 0 {
+		return interval
+	}
+
+	return wait.Jitter(interval, r.RequeueJitter)
+}
+
+// ensureFinalizer adds the finalizer to the Provider, so the DeletionPolicy can be honored on deletion
+func (r *ProviderReconciler) ensureFinalizer(ctx context.Context, provider ddnsv1alpha1.ProviderObject) error {
+	if controllerutil.ContainsFinalizer(provider, ddnsv1alpha1.ProviderFinalizer) {
+		return nil
+	}
+
+	patch := finalizersPatch(provider)
+	controllerutil.AddFinalizer(provider, ddnsv1alpha1.ProviderFinalizer)
+
+	return r.Patch(ctx, provider, patch)
+}
+
+// finalize cleans up after a deleted Provider
+// With the Delete policy, the owned records are removed from the provider before the finalizer is removed
+func (r *ProviderReconciler) finalize(ctx context.Context, namespace string, provider ddnsv1alpha1.ProviderObject) error {
+	if !controllerutil.ContainsFinalizer(provider, ddnsv1alpha1.ProviderFinalizer) {
+		return nil
+	}
+
+	if provider.GetProviderSpec().DeletionPolicy == ddnsv1alpha1.DeletionPolicyDelete && !provider.GetProviderSpec().DryRun {
+		log.FromContext(ctx).Info("Provider is being deleted, deleting owned records")
+
+		providerClient, _, err := r.fetchClient(ctx, namespace, provider)
+		if err != nil {
+			return err
 		}
+
+		for _, family := range r.ipFamilies(provider) {
+			err := callProviderErr(ctx, provider, "DeleteRecords", family.recordType, func() error {
+				return providerClient.DeleteRecords(family.recordType)
+			})
+			if err != nil {
+				return fmt.Errorf("unable to delete %s records: %w", family.recordType, err)
+			}
+		}
+	}
+
+	deleteProviderMetrics(provider)
+	r.records.delete(provider.GetUID())
+
+	patch := finalizersPatch(provider)
+	controllerutil.RemoveFinalizer(provider, ddnsv1alpha1.ProviderFinalizer)
+
+	return r.Patch(ctx, provider, patch)
+}
+
+// ipFamilies returns the IP families that should be kept in sync based on the IPVersion of the Provider
+// Providers with a NodeAddress take the IPs from the addresses of their Nodes instead of detecting them
+func (r *ProviderReconciler) ipFamilies(provider ddnsv1alpha1.ProviderObject) []ipFamily {
+	spec := provider.GetProviderSpec()
+	if spec.NodeAddress != nil {
+		return ipFamilies(spec, r.nodeIpProvider(spec.NodeAddress, false), r.nodeIpProvider(spec.NodeAddress, true))
 	}
 
-	return uniqueIps
+	return ipFamilies(spec, r.IPProvider, r.IPv6Provider)
 }
 
-// fetchSecret will fetch the secret from the namespace and set the status of the Provider
-// it will also update the status of the Provider so logic is isolated in this function
+// fetchSecret will fetch the secret from the namespace and set the Secret condition of the Provider in the set
 func (r *ProviderReconciler) fetchSecret(
 	ctx context.Context,
-	req ctrl.Request,
-	provider *ddnsv1alpha1.Provider,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+	set *conditions.ConditionsSet,
 ) (*corev1.Secret, error) {
 	var (
 		err    error
@@ -149,28 +597,35 @@ func (r *ProviderReconciler) fetchSecret(
 
 	condOptions := []conditions.ConditionOption{}
 
+	secretRef := provider.GetProviderSpec().GetSecretRef()
+
 	secret = &corev1.Secret{}
-	if err = r.Get(ctx, types.NamespacedName{Name: provider.Spec.SecretName, Namespace: req.Namespace}, secret); err != nil {
+	if err = r.Get(ctx, types.NamespacedName{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
 		condOptions = append(condOptions,
 			conditions.WithReasonAndMessage("SecretFound", err.Error()),
 			conditions.False(),
 		)
 	} else {
 		condOptions = append(condOptions,
-			conditions.WithReasonAndMessage("SecretFound", fmt.Sprintf("Secret %s found", provider.Spec.SecretName)),
+			conditions.WithReasonAndMessage("SecretFound", fmt.Sprintf("Secret %s found", secretRef.Name)),
 			conditions.True(),
 		)
+
+		secret = mapSecretKeys(secret, secretRef)
 	}
 
-	_ = conditions.PatchConditions(ctx, r.Client, provider, ddnsv1alpha1.ProviderConditionTypeSecret, condOptions...)
+	set.Set(ddnsv1alpha1.ProviderConditionTypeSecret, condOptions...)
 
 	return secret, err
 }
 
+// fetchConfig will fetch the config map from the namespace and set the ConfigMap condition of the Provider in the set
+// If the Provider has an inline config, a ConfigMap is built from it instead and nothing is fetched
 func (r *ProviderReconciler) fetchConfig(
 	ctx context.Context,
-	req ctrl.Request,
-	provider *ddnsv1alpha1.Provider,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+	set *conditions.ConditionsSet,
 ) (*corev1.ConfigMap, error) {
 	var (
 		configMap *corev1.ConfigMap
@@ -179,42 +634,105 @@ func (r *ProviderReconciler) fetchConfig(
 
 	condOptions := []conditions.ConditionOption{}
 
+	if provider.GetProviderSpec().Config != nil {
+		if configMap, err = inlineConfigMap(provider.GetProviderSpec()); err != nil {
+			condOptions = append(condOptions,
+				conditions.WithReasonAndMessage("InlineConfig", err.Error()),
+				conditions.False(),
+			)
+		} else {
+			condOptions = append(condOptions,
+				conditions.WithReasonAndMessage("InlineConfig", "Using the inline config from the spec"),
+				conditions.True(),
+			)
+		}
+
+		set.Set(ddnsv1alpha1.ProviderConditionTypeConfigMap, condOptions...)
+
+		return configMap, err
+	}
+
 	configMap = &corev1.ConfigMap{}
-	if err = r.Get(ctx, types.NamespacedName{Name: provider.Spec.ConfigMap, Namespace: req.Namespace}, configMap); err != nil {
+	if err = r.Get(ctx, types.NamespacedName{Name: provider.GetProviderSpec().ConfigMap, Namespace: namespace}, configMap); err != nil {
 		condOptions = append(condOptions,
 			conditions.WithReasonAndMessage("ConfigMapFound", err.Error()),
 			conditions.False(),
 		)
 	} else {
 		condOptions = append(condOptions,
-			conditions.WithReasonAndMessage("ConfigMapFound", fmt.Sprintf("ConfigMap %s found", provider.Spec.ConfigMap)),
+			conditions.WithReasonAndMessage("ConfigMapFound", fmt.Sprintf("ConfigMap %s found", provider.GetProviderSpec().ConfigMap)),
 			conditions.True(),
 		)
 	}
 
-	_ = conditions.PatchConditions(ctx, r.Client, provider, ddnsv1alpha1.ProviderConditionTypeConfigMap, condOptions...)
+	set.Set(ddnsv1alpha1.ProviderConditionTypeConfigMap, condOptions...)
 
 	return configMap, err
 }
 
+// inlineConfigMap builds an in-memory ConfigMap from the inline config of the Provider
+// so the ClientFactory can treat it the same way as a referenced ConfigMap
+func inlineConfigMap(spec *ddnsv1alpha1.ProviderSpec) (*corev1.ConfigMap, error) {
+	if spec.Config.Records != nil {
+		return recordsConfigMap(spec.Config.Records)
+	}
+
+	if spec.Config.Raw == nil {
+		return zonesConfigMap(spec.Name, spec.Config.Zones)
+	}
+
+	return &corev1.ConfigMap{
+		Data: map[string]string{
+			"config": string(spec.Config.Raw.Raw),
+		},
+	}, nil
+}
+
+// fetchClient builds the client of the Provider from its secret and config map
+// It also returns the observed resources and reports in the ResourcesChanged condition if they changed since the last successful reconciliation
+// The Secret, ConfigMap, ResourcesChanged and Client conditions are set in the status, which is applied with the rest of it
+// at the end of the reconciliation
 func (r *ProviderReconciler) fetchClient(
 	ctx context.Context,
-	req ctrl.Request,
-	provider *ddnsv1alpha1.Provider,
-) (clients.Client, error) {
-	secret, err := r.fetchSecret(ctx, req, provider)
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+) (clients.Client, ddnsv1alpha1.ObservedResources, error) {
+	set := conditions.PatchConditionsSet(provider)
+
+	secret, err := r.fetchSecret(ctx, namespace, provider, set)
 	if err != nil {
-		return nil, err
+		return nil, ddnsv1alpha1.ObservedResources{}, err
 	}
 
-	configMap, err := r.fetchConfig(ctx, req, provider)
+	configMap, err := r.fetchConfig(ctx, namespace, provider, set)
 	if err != nil {
-		return nil, err
+		return nil, ddnsv1alpha1.ObservedResources{}, err
+	}
+
+	observedConfigMap := configMap
+	if provider.GetProviderSpec().Config != nil {
+		observedConfigMap = nil
+	}
+
+	observed := observedResources(secret, observedConfigMap)
+
+	if last := provider.GetProviderStatus().ObservedResources; last != nil {
+		set.Set(ddnsv1alpha1.ProviderConditionTypeResourcesChanged, resourcesChangedOptions(last, observed)...)
 	}
 
 	condOptions := []conditions.ConditionOption{}
 
-	providerClient, err := r.ClientFactory(provider.Spec.Name, secret, configMap, log.FromContext(ctx))
+	clientCtx, span := tracing.Start(ctx, "Provider.CreateClient", attribute.String("ddns.provider", provider.GetProviderSpec().Name))
+
+	var providerClient clients.Client
+	if len(provider.GetProviderSpec().Backends) > 0 {
+		providerClient, err = r.backendsClient(clientCtx, namespace, provider, secret, configMap)
+	} else {
+		providerClient, err = r.ClientFactory(clientCtx, provider.GetProviderSpec().Name, secret, configMap, log.FromContext(ctx))
+	}
+
+	tracing.End(span, err)
+
 	if err != nil {
 		condOptions = append(condOptions,
 			conditions.WithReasonAndMessage("ClientCreated", err.Error()),
@@ -227,76 +745,407 @@ func (r *ProviderReconciler) fetchClient(
 		)
 	}
 
-	_ = conditions.PatchConditions(ctx, r.Client, provider, ddnsv1alpha1.ProviderConditionTypeClient, condOptions...)
+	set.Set(ddnsv1alpha1.ProviderConditionTypeClient, condOptions...)
+
+	return providerClient, observed, err
+}
+
+// setDryRun will report the changes that would have been made in the DryRun condition
+// If the Provider is not in DryRun mode, the condition is removed
+func setDryRun(set *conditions.ConditionsSet, provider ddnsv1alpha1.ProviderObject, changes []string) {
+	if !provider.GetProviderSpec().DryRun {
+		set.Remove(ddnsv1alpha1.ProviderConditionTypeDryRun)
+		return
+	}
+
+	if len(changes) == 0 {
+		set.Set(ddnsv1alpha1.ProviderConditionTypeDryRun,
+			conditions.WithReasonAndMessage("NoChanges", "Records are in sync, nothing would be updated"),
+			conditions.True(),
+		)
+		return
+	}
 
-	return providerClient, err
+	set.Set(ddnsv1alpha1.ProviderConditionTypeDryRun,
+		conditions.WithReasonAndMessage("PendingChanges", fmt.Sprintf("Would update %s", strings.Join(changes, ", "))),
+		conditions.True(),
+	)
 }
 
-func (r *ProviderReconciler) patchStatus(
+// setUpdateWindow reports in the UpdateWindow condition if the update window is open, or which updates are deferred until it opens
+// If the Provider has no UpdateWindows, the condition is removed
+func setUpdateWindow(
+	set *conditions.ConditionsSet,
+	provider ddnsv1alpha1.ProviderObject,
+	open bool,
+	next time.Time,
+	deferred []string,
+) {
+	if len(provider.GetProviderSpec().UpdateWindows) == 0 {
+		set.Remove(ddnsv1alpha1.ProviderConditionTypeUpdateWindow)
+		return
+	}
+
+	if open {
+		set.Set(ddnsv1alpha1.ProviderConditionTypeUpdateWindow,
+			conditions.WithReasonAndMessage("WindowOpen", "An update window is open, records are updated"),
+			conditions.True(),
+		)
+		return
+	}
+
+	opens := "no update window opens again"
+	if !next.IsZero() {
+		opens = fmt.Sprintf("the next update window opens at %s", next.Format(time.RFC3339))
+	}
+
+	if len(deferred) == 0 {
+		set.Set(ddnsv1alpha1.ProviderConditionTypeUpdateWindow,
+			conditions.WithReasonAndMessage("WindowClosed", fmt.Sprintf("Records are in sync, %s", opens)),
+			conditions.False(),
+		)
+		return
+	}
+
+	set.Set(ddnsv1alpha1.ProviderConditionTypeUpdateWindow,
+		conditions.WithReasonAndMessage("UpdatesDeferred", fmt.Sprintf("Would update %s, %s", strings.Join(deferred, ", "), opens)),
+		conditions.False(),
+	)
+}
+
+// setStatus changes the status of the provider in memory. The reconciliation applies all the changes at once when it
+// returns, see applyStatus
+func (r *ProviderReconciler) setStatus(provider ddnsv1alpha1.ProviderObject, patches ...func(ddnsv1alpha1.ProviderObject) bool) {
+	for _, patch := range patches {
+		patch(provider)
+	}
+}
+
+// applyStatus applies the status of the provider if the reconciliation changed it since original. It is not canceled
+// together with ctx, like the sync, so the records a stopped controller finished updating are still reported
+func (r *ProviderReconciler) applyStatus(
 	ctx context.Context,
-	provider *ddnsv1alpha1.Provider,
-	apply func(*ddnsv1alpha1.Provider) bool,
+	provider ddnsv1alpha1.ProviderObject,
+	original *ddnsv1alpha1.ProviderStatus,
 ) error {
-	patch := client.MergeFrom(provider.DeepCopy())
-	if apply(provider) {
-		if err := r.Status().Patch(ctx, provider, patch); err != nil {
-			return err
+	if equality.Semantic.DeepEqual(original, provider.GetProviderStatus()) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusApplyTimeout)
+	defer cancel()
+
+	return conditions.ApplyStatus(ctx, r.Client, provider)
+}
+
+// recordStatuses returns the state of the records at the provider, compared to the public IP
+func recordStatuses(records []clients.DNSRecord, publicIp string) []ddnsv1alpha1.RecordStatus {
+	statuses := make([]ddnsv1alpha1.RecordStatus, 0, len(records))
+	for _, record := range records {
+		statuses = append(statuses, ddnsv1alpha1.RecordStatus{
+			FQDN:         record.Name,
+			Type:         record.Type,
+			CurrentValue: record.Content,
+			DesiredValue: publicIp,
+			Synced:       record.Content == publicIp && !record.ProxiedDrift,
+			ProxiedDrift: record.ProxiedDrift,
+		})
+	}
+
+	return statuses
+}
+
+// desyncedChanges describes what has to change for the records of a single type to be in sync, e.g.
+// "A records from (1.2.3.3) to (1.2.3.4)" if the IP changed and "proxied setting of www.example.com (A)" for every record
+// whose proxied setting drifted
+func desyncedChanges(recordType, providerIp, publicIp string, records []clients.DNSRecord) []string {
+	changes := []string{}
+	if publicIp != providerIp {
+		changes = append(changes, fmt.Sprintf("%s records from (%s) to (%s)", recordType, providerIp, publicIp))
+	}
+
+	return append(changes, proxiedDrifted(records)...)
+}
+
+// getRecords returns the records of the type at the provider
+func getRecords(
+	ctx context.Context,
+	provider ddnsv1alpha1.ProviderObject,
+	providerClient clients.Client,
+	recordType string,
+) ([]clients.DNSRecord, error) {
+	return callProvider(ctx, provider, "GetRecords", recordType, func() ([]clients.DNSRecord, error) {
+		return providerClient.GetRecords(recordType)
+	})
+}
+
+// updateRecords sets the IP of the given records of a single type that are out of sync, including the ones with the wrong
+// proxied setting, if the client can update single records. Other clients update all of their records of the recordType
+func updateRecords(providerClient clients.Client, ip string, recordType string, dnsRecords []clients.DNSRecord) error {
+	updater, ok := providerClient.(clients.RecordsUpdater)
+	if !ok {
+		return providerClient.SetIp(ip, recordType)
+	}
+
+	drifted := []clients.DNSRecord{}
+	for _, record := range dnsRecords {
+		if record.Content != ip || record.ProxiedDrift {
+			drifted = append(drifted, record)
 		}
 	}
 
-	return nil
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	return updater.UpdateRecords(ip, drifted)
 }
 
 // =================================================== SETUP FUNCTIONS ===================================================
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&ddnsv1alpha1.Provider{}).
-		// WithEventFilter will only trigger the reconcile function if the observed generation is different from the new generation
-		WithEventFilter(predicate.Funcs{
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				newGeneration := e.ObjectNew.GetGeneration()
-				observedGeneration := e.ObjectNew.DeepCopyObject().(*ddnsv1alpha1.Provider).Status.ObservedGeneration
+	r.records = &providerRecords{}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&ddnsv1alpha1.Provider{}, builder.WithPredicates(providerEventFilter())).
+		WithOptions(r.ControllerOptions.options()).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.providersForResource)).
+		WatchesMetadata(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.providersForResource)).
+		Watches(&ddnsv1alpha1.Notifier{}, handler.EnqueueRequestsFromMapFunc(r.providersForNotifier),
+			builder.WithPredicates(notifierEventFilter()))
+
+	if !r.NamespaceScoped {
+		controllerBuilder = controllerBuilder.Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.providersForNode), builder.WithPredicates(nodeEventFilter())).
+			Watches(&ddnsv1alpha1.ClusterNotifier{}, handler.EnqueueRequestsFromMapFunc(r.providersForNotifier),
+				builder.WithPredicates(notifierEventFilter()))
+	}
 
-				return observedGeneration != newGeneration
-			},
-		}).
-		Complete(r)
+	return controllerBuilder.Complete(r)
+}
+
+// providersForResource returns a list of requests for the Providers in the namespace of the Secret or ConfigMap that read it,
+// so changes to the credentials or config take effect immediately
+func (r *ProviderReconciler) providersForResource(ctx context.Context, obj client.Object) []reconcile.Request {
+	providers := &ddnsv1alpha1.ProviderList{}
+	if err := r.List(ctx, providers, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Providers")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, provider := range providers.Items {
+		if providerUsesResource(&provider.Spec, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&provider)})
+		}
+	}
+
+	return requests
+}
+
+// providerEventFilter only triggers the reconcile function for the updates of a provider that need one:
+//   - spec changes, i.e. a new generation, or a generation that was not reconciled successfully yet
+//   - a sync requested with the SyncNowAnnotation
+//   - periodic resyncs of the cache, which do not change the resourceVersion
+//   - a status that was reset outside of the controller, e.g. by restoring a backup
+//
+// The status updates of the controller itself are filtered out, as the provider requeues itself after every reconciliation.
+// Events of the Secrets and ConfigMaps are mapped to the providers separately and are not filtered
+func providerEventFilter() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldProvider := e.ObjectOld.(ddnsv1alpha1.ProviderObject)
+			provider := e.ObjectNew.(ddnsv1alpha1.ProviderObject)
+
+			if oldProvider.GetResourceVersion() == provider.GetResourceVersion() {
+				return true
+			}
+
+			return oldProvider.GetGeneration() != provider.GetGeneration() ||
+				provider.GetProviderStatus().ObservedGeneration != provider.GetGeneration() ||
+				syncRequested(provider) ||
+				statusReset(oldProvider, provider)
+		},
+	}
+}
+
+// statusReset returns true if the status of the provider was reset outside of the controller, which never clears the
+// LastSyncTime or removes the Ready condition once they are set
+func statusReset(oldProvider, provider ddnsv1alpha1.ProviderObject) bool {
+	oldStatus := oldProvider.GetProviderStatus()
+	status := provider.GetProviderStatus()
+
+	if oldStatus.LastSyncTime != nil && status.LastSyncTime == nil {
+		return true
+	}
+
+	return meta.FindStatusCondition(oldStatus.Conditions, ddnsv1alpha1.ProviderConditionTypeReady) != nil &&
+		meta.FindStatusCondition(status.Conditions, ddnsv1alpha1.ProviderConditionTypeReady) == nil
+}
+
+// syncRequested returns true if the SyncNowAnnotation of the provider has a value that was not handled yet
+func syncRequested(provider ddnsv1alpha1.ProviderObject) bool {
+	value, ok := provider.GetAnnotations()[ddnsv1alpha1.SyncNowAnnotation]
+
+	return ok && value != provider.GetProviderStatus().LastHandledSyncNow
 }
 
 // =================================================== PATCH FUNCTIONS ===================================================
 
-func (p ProviderReconciler) patchProviderIp(providerIp string) func(provider *ddnsv1alpha1.Provider) bool {
-	return func(provider *ddnsv1alpha1.Provider) bool {
-		if provider.Status.ProviderIP == providerIp {
+func (p ProviderReconciler) patchProviderIp(family ipFamily, providerIp string) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		current := family.providerIp(provider.GetProviderStatus())
+		if *current == providerIp {
+			return false
+		}
+
+		*current = providerIp
+
+		return true
+	}
+}
+
+func (p ProviderReconciler) patchPublicIp(family ipFamily, publicIp string) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		current := family.publicIp(provider.GetProviderStatus())
+		if *current == publicIp {
+			return false
+		}
+
+		*current = publicIp
+
+		return true
+	}
+}
+
+func (p ProviderReconciler) patchObservedGeneration() func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		if provider.GetProviderStatus().ObservedGeneration == provider.GetGeneration() {
+			return false
+		}
+		provider.GetProviderStatus().ObservedGeneration = provider.GetGeneration()
+		return true
+	}
+}
+
+func (p ProviderReconciler) patchObservedResources(observed ddnsv1alpha1.ObservedResources) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+		if status.ObservedResources != nil && *status.ObservedResources == observed {
 			return false
 		}
 
-		provider.Status.ProviderIP = providerIp
+		status.ObservedResources = &observed
 
 		return true
 	}
 }
 
-func (p ProviderReconciler) patchPublicIp(publicIp string) func(provider *ddnsv1alpha1.Provider) bool {
-	return func(provider *ddnsv1alpha1.Provider) bool {
-		if provider.Status.PublicIP == publicIp {
+// patchLastHandledSyncNow marks the current value of the SyncNowAnnotation as handled.
+// It is patched before syncing, so the status updates of a failing sync do not trigger it again.
+func (p ProviderReconciler) patchLastHandledSyncNow() func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		if !syncRequested(provider) {
 			return false
 		}
 
-		provider.Status.PublicIP = publicIp
+		provider.GetProviderStatus().LastHandledSyncNow = provider.GetAnnotations()[ddnsv1alpha1.SyncNowAnnotation]
+
+		return true
+	}
+}
+
+func (p ProviderReconciler) patchLastSyncTime() func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		now := metav1.Now()
+		provider.GetProviderStatus().LastSyncTime = &now
 
 		return true
 	}
 }
 
-func (p ProviderReconciler) patchObservedGeneration() func(provider *ddnsv1alpha1.Provider) bool {
-	return func(provider *ddnsv1alpha1.Provider) bool {
-		if provider.Status.ObservedGeneration == provider.GetGeneration() {
+// patchLastHeartbeatTime sets the LastHeartbeatTime, unless it was set less than the HeartbeatInterval ago
+func (p ProviderReconciler) patchLastHeartbeatTime(now time.Time) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+		if p.HeartbeatInterval <= 0 || (status.LastHeartbeatTime != nil && now.Sub(status.LastHeartbeatTime.Time) < p.HeartbeatInterval) {
 			return false
 		}
-		provider.Status.ObservedGeneration = provider.GetGeneration()
+
+		status.LastHeartbeatTime = &metav1.Time{Time: now}
+
 		return true
 	}
 }
+
+// patchVersions stamps the version of the controller and of the libraries of the clients of the provider, if they changed
+func (p ProviderReconciler) patchVersions() func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+		controllerVersion := version.Controller()
+		clientVersion := clientVersions(provider.GetProviderSpec())
+
+		if status.ControllerVersion == controllerVersion && status.ClientVersion == clientVersion {
+			return false
+		}
+
+		status.ControllerVersion = controllerVersion
+		status.ClientVersion = clientVersion
+
+		return true
+	}
+}
+
+func (p ProviderReconciler) patchLastIPChangeTime() func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		now := metav1.Now()
+		provider.GetProviderStatus().LastIPChangeTime = &now
+
+		return true
+	}
+}
+
+func (p ProviderReconciler) patchSyncResult(err error) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+		if err != nil {
+			status.FailedSyncCount++
+			status.ConsecutiveFailures++
+			status.LastError = err.Error()
+			status.Message = fmt.Sprintf("Sync failed: %s", err)
+		} else {
+			status.SyncCount++
+			status.ConsecutiveFailures = 0
+			status.LastError = ""
+			meta.RemoveStatusCondition(&status.Conditions, ddnsv1alpha1.ProviderConditionTypeRetrying)
+			status.Message = recordsMessage(status.Records)
+		}
+
+		return true
+	}
+}
+
+// patchRecords sets the state of the records and since when they are out of sync, if they are
+func (p ProviderReconciler) patchRecords(records []ddnsv1alpha1.RecordStatus, outOfSync bool) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+		changed := false
+
+		if !equality.Semantic.DeepEqual(status.Records, records) {
+			status.Records = records
+			changed = true
+		}
+
+		if outOfSync && status.OutOfSyncSince == nil {
+			now := metav1.Now()
+			status.OutOfSyncSince = &now
+			changed = true
+		} else if !outOfSync && status.OutOfSyncSince != nil {
+			status.OutOfSyncSince = nil
+			changed = true
+		}
+
+		return changed
+	}
+}
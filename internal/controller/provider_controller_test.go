@@ -24,18 +24,25 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
 	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/version"
 )
 
 var _ = Describe("Provider Controller", func() {
@@ -125,7 +132,7 @@ var _ = Describe("Provider Controller", func() {
 						Name:          "Cloudflare",
 						SecretName:    secretNamespacedName.Name,
 						ConfigMap:     configMapNamespacedName.Name,
-						RetryInterval: 123,
+						RetryInterval: ptr.To(intstr.FromInt32(123)),
 						NotifierRefs:  []ddnsv1alpha1.ResourceRef{},
 					},
 					Status: ddnsv1alpha1.ProviderStatus{},
@@ -140,10 +147,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler = &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{}, nil
 				},
 			}
@@ -159,7 +166,7 @@ var _ = Describe("Provider Controller", func() {
 			Expect(k8sClient.Get(ctx, configMapNamespacedName, configMapResource)).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance Provider and related resources")
-			Expect(k8sClient.Delete(ctx, providerResource)).To(Succeed())
+			deleteProvider(ctx, providerResource)
 			Expect(k8sClient.Delete(ctx, secretResource)).To(Succeed())
 			Expect(k8sClient.Delete(ctx, configMapResource)).To(Succeed())
 		})
@@ -174,12 +181,68 @@ var _ = Describe("Provider Controller", func() {
 
 			err = k8sClient.Get(ctx, providerNamespacedName, provider)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(provider.Status.SyncCount).To(Equal(int64(1)))
+			Expect(provider.Status.FailedSyncCount).To(Equal(int64(0)))
+		})
+
+		It("should apply the status once per reconciliation and return the error if it fails", func() {
+			patches := 0
+			controllerReconciler.Client = statusPatchCounter{Client: k8sClient, patches: &patches}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(Equal(1))
+
+			By("Returning the error of the apply")
+			patches = 0
+			controllerReconciler.Client = statusPatchCounter{
+				Client:  k8sClient,
+				patches: &patches,
+				err:     fmt.Errorf("the server is unavailable"),
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(MatchError(ContainSubstring("unable to update the status: the server is unavailable")))
+			Expect(patches).To(Equal(1))
+		})
+
+		It("should summarize the state of the records in the message", func() {
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.Message).To(Equal("All 1 records synced to " + dummyIp))
+
+			Expect(recordsMessage(nil)).To(Equal("No records managed"))
+
+			Expect(recordsMessage([]ddnsv1alpha1.RecordStatus{
+				{FQDN: "example.com", Type: "A", CurrentValue: "1.2.3.4", DesiredValue: "1.2.3.4", Synced: true},
+				{FQDN: "example.com", Type: "AAAA", CurrentValue: "::1", DesiredValue: "::1", Synced: true},
+			})).To(Equal("All 2 records synced to 1.2.3.4, ::1"))
+
+			Expect(recordsMessage([]ddnsv1alpha1.RecordStatus{
+				{FQDN: "example.com", Type: "A", CurrentValue: "1.2.3.4", DesiredValue: "1.2.3.4", Synced: true},
+				{FQDN: "www.example.com", Type: "A", CurrentValue: "1.2.3.3", DesiredValue: "1.2.3.4"},
+				{FQDN: "vpn.example.com", Type: "A", DesiredValue: "1.2.3.4"},
+			})).To(Equal("2 of 3 records out of sync: www.example.com (A) is 1.2.3.3 instead of 1.2.3.4, vpn.example.com (A) does not exist"))
+
+			Expect(recordsMessage([]ddnsv1alpha1.RecordStatus{
+				{FQDN: "example.com", Type: "A", CurrentValue: "1.2.3.4", DesiredValue: "1.2.3.4", ProxiedDrift: true},
+			})).To(Equal("1 of 1 records out of sync: example.com (A) has the wrong proxied setting"))
 		})
 
 		It("should successfully requeue the reqeust for an interval equal to the spec", func() {
 			By("Reconciling the created resource")
 
 			provider := &ddnsv1alpha1.Provider{}
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
 
 			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
 			Expect(err).NotTo(HaveOccurred())
@@ -190,6 +253,64 @@ var _ = Describe("Provider Controller", func() {
 			Expect(result.RequeueAfter).To(Equal(time.Second * 123))
 		})
 
+		It("should add up to the RequeueJitter of the interval to it", func() {
+			controllerReconciler.RequeueJitter = 0.5
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">=", time.Second*123))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Second*123*3/2))
+		})
+
+		It("should accept the interval as a duration", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.RetryInterval = ptr.To(intstr.FromString("1h"))
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+		})
+
+		It("should reject an interval shorter than a minute", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			provider.Spec.RetryInterval = ptr.To(intstr.FromString("30s"))
+			Expect(k8sClient.Update(ctx, provider)).NotTo(Succeed())
+
+			provider.Spec.RetryInterval = ptr.To(intstr.FromInt32(30))
+			Expect(k8sClient.Update(ctx, provider)).NotTo(Succeed())
+		})
+
+		It("should default the interval to 15 minutes", func() {
+			resource := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "provider-with-default-interval",
+					Namespace: "default",
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:       "Cloudflare",
+					SecretName: secretNamespacedName.Name,
+					ConfigMap:  configMapNamespacedName.Name,
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			defer deleteProvider(ctx, resource)
+
+			Expect(resource.Spec.GetRetryInterval()).To(Equal(15 * time.Minute))
+		})
+
 		It("should set correct conditions", func() {
 			By("Reconciling the created resource")
 
@@ -202,10 +323,14 @@ var _ = Describe("Provider Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(provider.Status.ObservedGeneration).To(Equal(int64(1)))
-			Expect(provider.Status.Conditions).To(HaveLen(3))
+			Expect(provider.Status.Conditions).To(HaveLen(7))
 			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, "ConfigMap")).To(BeTrue())
 			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, "Secret")).To(BeTrue())
 			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, "Client")).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, "Ready")).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "ResourcesChanged")).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "Degraded")).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, "Synced")).To(BeTrue())
 
 			secretCondition := meta.FindStatusCondition(provider.Status.Conditions, "Secret")
 			Expect(secretCondition.Message).To(Equal(fmt.Sprintf("Secret %s found", secretNamespacedName.Name)))
@@ -217,6 +342,165 @@ var _ = Describe("Provider Controller", func() {
 			Expect(clientCondition.Message).To(Equal("Client created successfully"))
 		})
 
+		It("should sync when the sync-now annotation changes", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			// updated returns the provider as it is after an update, which changes the resourceVersion
+			updated := func(provider *ddnsv1alpha1.Provider) *ddnsv1alpha1.Provider {
+				provider = provider.DeepCopy()
+				provider.ResourceVersion += "0"
+				return provider
+			}
+
+			filter := providerEventFilter()
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: provider, ObjectNew: updated(provider)})).To(BeFalse())
+
+			By("Requesting a sync")
+			requested := provider.DeepCopy()
+			requested.SetAnnotations(map[string]string{ddnsv1alpha1.SyncNowAnnotation: "1"})
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: provider, ObjectNew: updated(requested)})).To(BeTrue())
+			Expect(k8sClient.Update(ctx, requested)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.LastHandledSyncNow).To(Equal("1"))
+			Expect(provider.Status.SyncCount).To(Equal(int64(2)))
+
+			By("Not syncing again for the same value")
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: provider, ObjectNew: updated(provider)})).To(BeFalse())
+		})
+
+		It("should only filter out the status updates of the controller itself", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			filter := providerEventFilter()
+
+			By("Filtering out a status update")
+			statusUpdate := provider.DeepCopy()
+			statusUpdate.ResourceVersion += "0"
+			statusUpdate.Status.SyncCount++
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: provider, ObjectNew: statusUpdate})).To(BeFalse())
+
+			By("Letting a periodic resync through")
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: provider, ObjectNew: provider.DeepCopy()})).To(BeTrue())
+
+			By("Letting a spec change through")
+			specChange := statusUpdate.DeepCopy()
+			specChange.Generation++
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: provider, ObjectNew: specChange})).To(BeTrue())
+
+			By("Letting a status reset through")
+			statusReset := statusUpdate.DeepCopy()
+			statusReset.Status = ddnsv1alpha1.ProviderStatus{ObservedGeneration: provider.Generation}
+			Expect(filter.Update(event.UpdateEvent{ObjectOld: provider, ObjectNew: statusReset})).To(BeTrue())
+		})
+
+		It("should requeue the Providers that read a changed Secret or ConfigMap", func() {
+			request := reconcile.Request{NamespacedName: providerNamespacedName}
+			resource := func(obj client.Object, name, namespace string) client.Object {
+				obj.SetName(name)
+				obj.SetNamespace(namespace)
+				return obj
+			}
+
+			Expect(controllerReconciler.providersForResource(ctx, resource(&corev1.Secret{}, secretNamespacedName.Name, "default"))).
+				To(Equal([]reconcile.Request{request}))
+			Expect(controllerReconciler.providersForResource(ctx, resource(&corev1.ConfigMap{}, configMapNamespacedName.Name, "default"))).
+				To(Equal([]reconcile.Request{request}))
+			Expect(controllerReconciler.providersForResource(ctx, resource(&corev1.Secret{}, secretNamespacedName.Name, "kube-system"))).
+				To(BeEmpty())
+			Expect(controllerReconciler.providersForResource(ctx, resource(&corev1.Secret{}, "other-secret", "default"))).
+				To(BeEmpty())
+
+			By("Mapping the metadata of the Secrets and ConfigMaps that are watched")
+			metadata := &metav1.PartialObjectMetadata{}
+			metadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+			Expect(controllerReconciler.providersForResource(ctx, resource(metadata, secretNamespacedName.Name, "default"))).
+				To(Equal([]reconcile.Request{request}))
+
+			metadata = &metav1.PartialObjectMetadata{}
+			metadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+			Expect(controllerReconciler.providersForResource(ctx, resource(metadata, secretNamespacedName.Name, "default"))).
+				To(BeEmpty())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.Backends = []ddnsv1alpha1.ProviderBackend{{
+				DomainSuffix: "example.org",
+				Client:       "Cloudflare",
+				SecretRef:    ddnsv1alpha1.SecretRef{Name: "backend-secret"},
+				ConfigRef:    &ddnsv1alpha1.ConfigMapRef{Name: "backend-config"},
+			}}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			Expect(controllerReconciler.providersForResource(ctx, resource(&corev1.Secret{}, "backend-secret", "default"))).
+				To(Equal([]reconcile.Request{request}))
+			Expect(controllerReconciler.providersForResource(ctx, resource(&corev1.ConfigMap{}, "backend-config", "default"))).
+				To(Equal([]reconcile.Request{request}))
+		})
+
+		It("should report if the Secret or ConfigMap changed since the last successful sync", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.ObservedResources).NotTo(BeNil())
+			Expect(provider.Status.ObservedResources.SecretHash).NotTo(BeEmpty())
+			Expect(provider.Status.ObservedResources.ConfigMapHash).NotTo(BeEmpty())
+			observed := *provider.Status.ObservedResources
+
+			By("Rotating the Secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			secret.Data["apiToken"] = []byte("rotated")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			By("Failing to sync with the new Secret")
+			failingReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return nil, fmt.Errorf("cannot create client")
+				},
+			}
+
+			_, err = failingReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(*provider.Status.ObservedResources).To(Equal(observed))
+
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "ResourcesChanged")
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("Secret changed since the last successful reconciliation"))
+
+			By("Syncing successfully with the new Secret")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.ObservedResources.SecretHash).NotTo(Equal(observed.SecretHash))
+			Expect(provider.Status.ObservedResources.ConfigMapHash).To(Equal(observed.ConfigMapHash))
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "ResourcesChanged")).To(BeTrue())
+		})
+
 		It("should set correct IPs if ProviderIP is empty", func() {
 			By("Reconciling the created resource")
 
@@ -225,10 +509,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: "",
 					}, nil
@@ -254,10 +538,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: dummyProviderIP,
 						SetIPInterceptor: func(ip string) {
@@ -278,6 +562,113 @@ var _ = Describe("Provider Controller", func() {
 
 			Expect(provider.Status.PublicIP).To(Equal(dummyIp))
 			Expect(provider.Status.ProviderIP).To(Equal(dummyIp))
+			Expect(provider.Status.LastSyncTime).NotTo(BeNil())
+			Expect(provider.Status.LastIPChangeTime).NotTo(BeNil())
+		})
+
+		It("should set both IP families for a DualStack provider", func() {
+			By("Reconciling the created resource")
+
+			dummyIpv6 := "::1"
+			setIps := []string{}
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.IPVersion = ddnsv1alpha1.IPVersionDualStack
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				IPv6Provider: func(ctx context.Context, c string) (string, error) {
+					return dummyIpv6, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP:   dummyProviderIP,
+						IPv6: "::2",
+						SetIPInterceptor: func(ip string) {
+							setIps = append(setIps, ip)
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(provider.Status.PublicIP).To(Equal(dummyIp))
+			Expect(provider.Status.ProviderIP).To(Equal(dummyIp))
+			Expect(provider.Status.PublicIPv6).To(Equal(dummyIpv6))
+			Expect(provider.Status.ProviderIPv6).To(Equal(dummyIpv6))
+			Expect(setIps).To(Equal([]string{dummyIp, dummyIpv6}))
+		})
+
+		It("should adopt the existing records without changing them until the public IP changes", func() {
+			publicIp := dummyIp
+			adopted := []clients.DNSRecord{}
+			setIps := []string{}
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.AdoptExisting = true
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return publicIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockRecordsAdopter{
+						MockClient: MockClient{
+							IP: dummyProviderIP,
+							SetIPInterceptor: func(ip string) {
+								setIps = append(setIps, ip)
+							},
+						},
+						AdoptInterceptor: func(records []clients.DNSRecord) {
+							adopted = append(adopted, records...)
+						},
+					}, nil
+				},
+			}
+
+			By("Adopting the records on the first reconciliation")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			Expect(adopted).To(HaveLen(1))
+			Expect(setIps).To(BeEmpty())
+			Expect(provider.Status.AdoptedTime).NotTo(BeNil())
+			Expect(provider.Status.AdoptedPublicIP).To(Equal(dummyIp))
+			Expect(provider.Status.Records).To(HaveLen(1))
+			Expect(provider.Status.Records[0].DesiredValue).To(Equal(dummyProviderIP))
+			Expect(provider.Status.Records[0].Synced).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, ddnsv1alpha1.ProviderConditionTypeSynced)).To(BeTrue())
+
+			By("Leaving the records as they are while the public IP does not change")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adopted).To(HaveLen(1))
+			Expect(setIps).To(BeEmpty())
+
+			By("Updating the records once the public IP changes")
+			publicIp = "127.0.0.3"
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+
+			Expect(setIps).To(Equal([]string{"127.0.0.3"}))
+			Expect(provider.Status.AdoptedPublicIP).To(BeEmpty())
 		})
 
 		It("should set correct IPs if called multiple times", func() {
@@ -289,10 +680,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: dummyProviderIP,
 						SetIPInterceptor: func(ip string) {
@@ -349,7 +740,7 @@ var _ = Describe("Provider Controller", func() {
 						Name:          "Cloudflare",
 						SecretName:    secretNamespacedName.Name,
 						ConfigMap:     configMapNamespacedName.Name,
-						RetryInterval: 900,
+						RetryInterval: ptr.To(intstr.FromInt32(900)),
 						NotifierRefs:  []ddnsv1alpha1.ResourceRef{},
 					},
 					// Currently pointing to one IP, but it will be changed in the test
@@ -363,7 +754,7 @@ var _ = Describe("Provider Controller", func() {
 
 				// Cleanup the resource after the test
 				defer func() {
-					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).NotTo(HaveOccurred())
+					deleteProvider(ctx, resource)
 				}()
 			} else {
 				Expect(err).NotTo(HaveOccurred())
@@ -372,10 +763,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: dummyProviderIP,
 						SetIPInterceptor: func(ip string) {
@@ -422,7 +813,7 @@ var _ = Describe("Provider Controller", func() {
 						Name:          "Cloudflare",
 						SecretName:    secretNamespacedName.Name,
 						ConfigMap:     "unexisting-configmap",
-						RetryInterval: 900,
+						RetryInterval: ptr.To(intstr.FromInt32(900)),
 						NotifierRefs:  []ddnsv1alpha1.ResourceRef{},
 					},
 					// Currently pointing to one IP, but it will be changed in the test
@@ -433,7 +824,7 @@ var _ = Describe("Provider Controller", func() {
 
 				// Cleanup the resource after the test
 				defer func() {
-					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).NotTo(HaveOccurred())
+					deleteProvider(ctx, resource)
 				}()
 			} else {
 				Expect(err).NotTo(HaveOccurred())
@@ -445,8 +836,9 @@ var _ = Describe("Provider Controller", func() {
 			err = k8sClient.Get(ctx, providerNamespacedName, provider)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(provider.Status.Conditions).To(HaveLen(2))
+			Expect(provider.Status.Conditions).To(HaveLen(4))
 			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "ConfigMap")).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "Ready")).To(BeTrue())
 
 			condition := meta.FindStatusCondition(provider.Status.Conditions, "ConfigMap")
 			Expect(condition.Message).To(Equal("configmaps \"unexisting-configmap\" not found"))
@@ -475,7 +867,7 @@ var _ = Describe("Provider Controller", func() {
 						Name:          "Cloudflare",
 						SecretName:    "unexisting-secret",
 						ConfigMap:     configMapNamespacedName.Name,
-						RetryInterval: 900,
+						RetryInterval: ptr.To(intstr.FromInt32(900)),
 						NotifierRefs:  []ddnsv1alpha1.ResourceRef{},
 					},
 					// Currently pointing to one IP, but it will be changed in the test
@@ -486,7 +878,7 @@ var _ = Describe("Provider Controller", func() {
 
 				// Cleanup the resource after the test
 				defer func() {
-					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).NotTo(HaveOccurred())
+					deleteProvider(ctx, resource)
 				}()
 			} else {
 				Expect(err).NotTo(HaveOccurred())
@@ -498,122 +890,1503 @@ var _ = Describe("Provider Controller", func() {
 			err = k8sClient.Get(ctx, providerNamespacedName, provider)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(provider.Status.Conditions).To(HaveLen(1))
+			Expect(provider.Status.Conditions).To(HaveLen(3))
 			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "Secret")).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "Ready")).To(BeTrue())
 
 			condition := meta.FindStatusCondition(provider.Status.Conditions, "Secret")
 			Expect(condition.Message).To(Equal("secrets \"unexisting-secret\" not found"))
 		})
 
-		It("should not reconcile if cannot fetch public IP", func() {
+		It("should use the inline config instead of a ConfigMap", func() {
+			By("Reconciling the created resource")
+
+			// Overwrite the providerNamespacedName to create a new resource
+			providerNamespacedName := types.NamespacedName{
+				Name:      "provider-with-inline-config",
+				Namespace: "default",
+			}
+
+			var receivedConfig string
 			provider := &ddnsv1alpha1.Provider{}
-			var err error
 
-			controllerReconciler := &ProviderReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
-					return "", fmt.Errorf("cannot fetch public IP")
+			resource := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      providerNamespacedName.Name,
+					Namespace: providerNamespacedName.Namespace,
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
-					return MockClient{
-						IP: "",
-					}, nil
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:          "Cloudflare",
+					SecretName:    secretNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(900)),
+					Config: &ddnsv1alpha1.ProviderConfig{
+						Zones: []ddnsv1alpha1.ZoneConfig{
+							{
+								Name:    "example.com",
+								Records: []ddnsv1alpha1.RecordConfig{{Name: "example.com", Proxied: true}},
+							},
+						},
+					},
 				},
 			}
 
-			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("cannot fetch public IP"))
-
-			err = k8sClient.Get(ctx, providerNamespacedName, provider)
-			Expect(err).NotTo(HaveOccurred())
-
-			Expect(provider.Status.PublicIP).To(Equal(""))
-		})
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 
-		It("should not reconcile if the ClientFactory cannot create a provider", func() {
-			provider := &ddnsv1alpha1.Provider{}
-			var err error
+			// Cleanup the resource after the test
+			defer func() {
+				deleteProvider(ctx, resource)
+			}()
 
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
-					return nil, fmt.Errorf("cannot create client")
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					receivedConfig = configMap.Data["config"]
+					return MockClient{IP: dummyIp}, nil
 				},
 			}
 
-			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("cannot create client"))
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(receivedConfig).To(MatchJSON(`{"cloudflare":{"zones":[{"name":"example.com","records":[{"name":"example.com","proxied":true}]}]}}`))
 
 			err = k8sClient.Get(ctx, providerNamespacedName, provider)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(provider.Status.Conditions).To(HaveLen(3))
-			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "Client")).To(BeTrue())
-
-			condition := meta.FindStatusCondition(provider.Status.Conditions, "Client")
-			Expect(condition.Message).To(Equal("cannot create client"))
+			configMapCondition := meta.FindStatusCondition(provider.Status.Conditions, "ConfigMap")
+			Expect(configMapCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(configMapCondition.Reason).To(Equal("InlineConfig"))
 		})
 
-		It("should not reconcile if the ProviderIP cannot be fetched", func() {
-			provider := &ddnsv1alpha1.Provider{}
-			var err error
+		It("should pass the provider-neutral records to the client", func() {
+			providerNamespacedName := types.NamespacedName{
+				Name:      "provider-with-records",
+				Namespace: "default",
+			}
+
+			var receivedRecords string
+
+			resource := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      providerNamespacedName.Name,
+					Namespace: providerNamespacedName.Namespace,
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:          "Cloudflare",
+					SecretName:    secretNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(900)),
+					Config: &ddnsv1alpha1.ProviderConfig{
+						Records: []ddnsv1alpha1.ManagedRecord{
+							{Zone: "example.com", Name: "example.com", Type: "A", TTL: 300, Proxied: true},
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			// Cleanup the resource after the test
+			defer func() {
+				deleteProvider(ctx, resource)
+			}()
 
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
-					return MockClient{
-						IP:         "",
-						GetIPError: fmt.Errorf("cannot get IP"),
-					}, nil
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					receivedRecords = configMap.Data["records"]
+					return MockClient{IP: dummyIp}, nil
 				},
 			}
 
-			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("cannot get IP"))
-
-			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(provider.Status.PublicIP).To(Equal(dummyIp))
-			Expect(provider.Status.ProviderIP).To(Equal(""))
+			Expect(receivedRecords).To(MatchJSON(`[{"zone":"example.com","name":"example.com","type":"A","ttl":300,"proxied":true}]`))
 		})
 
-		It("should not reconcile if the ProviderIP cannot be set", func() {
-			provider := &ddnsv1alpha1.Provider{}
-			var err error
+		It("should route the records to the backends by domain suffix", func() {
+			providerNamespacedName := types.NamespacedName{
+				Name:      "provider-with-backends",
+				Namespace: "default",
+			}
+
+			backendSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend-secret", Namespace: "default"},
+				StringData: map[string]string{"token": "backend-token"},
+			}
+			Expect(k8sClient.Create(ctx, backendSecret)).To(Succeed())
+
+			resource := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      providerNamespacedName.Name,
+					Namespace: providerNamespacedName.Namespace,
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:          "Cloudflare",
+					SecretName:    secretNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(900)),
+					Config: &ddnsv1alpha1.ProviderConfig{
+						Records: []ddnsv1alpha1.ManagedRecord{
+							{Zone: "example.com", Name: "example.com"},
+							{Zone: "example.org", Name: "www.example.org"},
+						},
+					},
+					Backends: []ddnsv1alpha1.ProviderBackend{{
+						DomainSuffix: "example.org",
+						Client:       "Cloudflare",
+						SecretRef:    ddnsv1alpha1.SecretRef{Name: backendSecret.Name, Keys: map[string]string{"apiToken": "token"}},
+					}},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			defer func() {
+				deleteProvider(ctx, resource)
+				Expect(k8sClient.Delete(ctx, backendSecret)).To(Succeed())
+			}()
+
+			receivedRecords := map[string]string{}
 
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
-					return MockClient{
-						IP:         "",
-						SetIPError: fmt.Errorf("cannot set IP"),
-					}, nil
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					receivedRecords[string(secret.Data["apiToken"])] = configMap.Data["records"]
+					return MockClient{IP: dummyIp}, nil
 				},
 			}
 
-			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("cannot set IP"))
-
-			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
 			Expect(err).NotTo(HaveOccurred())
+
+			Expect(receivedRecords).To(HaveLen(2))
+			Expect(receivedRecords["test-token"]).To(MatchJSON(`[{"zone":"example.com","name":"example.com"}]`))
+			Expect(receivedRecords["backend-token"]).To(MatchJSON(`[{"zone":"example.org","name":"www.example.org"}]`))
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.Records).To(HaveLen(2))
+		})
+
+		It("should require provider-neutral records for backends", func() {
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.Backends = []ddnsv1alpha1.ProviderBackend{{
+				DomainSuffix: "example.org",
+				Client:       "Cloudflare",
+				SecretRef:    ddnsv1alpha1.SecretRef{Name: secretNamespacedName.Name},
+			}}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(MatchError("backends require the records of the Provider to be set in `records`"))
+		})
+
+		It("should reject an inline config with both zones and records", func() {
+			resource := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "provider-with-zones-and-records",
+					Namespace: "default",
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:          "Cloudflare",
+					SecretName:    secretNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(900)),
+					Config: &ddnsv1alpha1.ProviderConfig{
+						Zones: []ddnsv1alpha1.ZoneConfig{
+							{
+								Name:    "example.com",
+								Records: []ddnsv1alpha1.RecordConfig{{Name: "example.com"}},
+							},
+						},
+						Records: []ddnsv1alpha1.ManagedRecord{{Zone: "example.com", Name: "example.com"}},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).NotTo(Succeed())
+		})
+
+		It("should read the secret keys configured in the SecretRef", func() {
+			By("Reconciling the created resource")
+
+			// Overwrite the providerNamespacedName to create a new resource
+			providerNamespacedName := types.NamespacedName{
+				Name:      "provider-with-secret-ref",
+				Namespace: "default",
+			}
+
+			var receivedToken string
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "external-secret",
+					Namespace: providerNamespacedName.Namespace,
+				},
+				StringData: map[string]string{
+					"cloudflare-token": "external-token",
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			resource := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      providerNamespacedName.Name,
+					Namespace: providerNamespacedName.Namespace,
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name: "Cloudflare",
+					SecretRef: &ddnsv1alpha1.SecretRef{
+						Name: secret.Name,
+						Keys: map[string]string{"apiToken": "cloudflare-token"},
+					},
+					ConfigMap:     configMapNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(900)),
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			// Cleanup the resources after the test
+			defer func() {
+				deleteProvider(ctx, resource)
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).NotTo(HaveOccurred())
+			}()
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					receivedToken = string(secret.Data["apiToken"])
+					return MockClient{IP: dummyIp}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(receivedToken).To(Equal("external-token"))
+		})
+
+		It("should reject a Provider without a ConfigMap or an inline config", func() {
+			resource := &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "provider-without-config",
+					Namespace: "default",
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:          "Cloudflare",
+					SecretName:    secretNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(900)),
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).NotTo(Succeed())
+		})
+
+		It("should not do anything if the Provider is suspended", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.Suspend = true
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					Fail("IPProvider should not be called for a suspended Provider")
+					return "", nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					Fail("ClientFactory should not be called for a suspended Provider")
+					return nil, nil
+				},
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.PublicIP).To(BeEmpty())
+			Expect(provider.Status.Conditions).To(BeEmpty())
+		})
+
+		It("should update the heartbeat of a suspended Provider at most once per HeartbeatInterval", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.Suspend = true
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				HeartbeatInterval: time.Hour,
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.LastHeartbeatTime).NotTo(BeNil())
+			heartbeat := provider.Status.LastHeartbeatTime.DeepCopy()
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.LastHeartbeatTime).To(Equal(heartbeat))
+		})
+
+		It("should report the notifierRefs that do not point at existing notifiers", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.NotifierRefs = []ddnsv1alpha1.ResourceRef{
+				{Name: "test-referenced-notifier"},
+				{Name: "test-other-notifier", Namespace: "kube-system"},
+			}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			Expect(controllerReconciler.patchNotifierRefs(ctx, "default", provider)).To(Succeed())
+
+			condition := meta.FindStatusCondition(provider.Status.Conditions, ddnsv1alpha1.ProviderConditionTypeNotifierRefs)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("Not notified through Notifier default/test-referenced-notifier (not found), " +
+				"Notifier kube-system/test-other-notifier (cross-namespace notifierRefs are not allowed)"))
+
+			By("Reporting the notifierRefs as found once the notifier exists")
+			notifier := &ddnsv1alpha1.Notifier{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-referenced-notifier", Namespace: "default"},
+				Spec:       ddnsv1alpha1.NotifierSpec{Name: "Webhook", SecretName: "webhook"},
+			}
+			Expect(k8sClient.Create(ctx, notifier)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, notifier)
+
+			provider.Spec.NotifierRefs = provider.Spec.NotifierRefs[:1]
+			Expect(controllerReconciler.patchNotifierRefs(ctx, "default", provider)).To(Succeed())
+
+			condition = meta.FindStatusCondition(provider.Status.Conditions, ddnsv1alpha1.ProviderConditionTypeNotifierRefs)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+			By("Removing the condition once the Provider has no notifierRefs")
+			provider.Spec.NotifierRefs = nil
+			Expect(controllerReconciler.patchNotifierRefs(ctx, "default", provider)).To(Succeed())
+			Expect(meta.FindStatusCondition(provider.Status.Conditions, ddnsv1alpha1.ProviderConditionTypeNotifierRefs)).To(BeNil())
+		})
+
+		It("should stamp the versions of the controller and of the client library on every reconciliation", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.Suspend = true
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.ControllerVersion).To(Equal(version.Controller()))
+			Expect(provider.Status.ClientVersion).To(HavePrefix("github.com/cloudflare/cloudflare-go@v"))
+		})
+
+		It("should not set the IP but report the changes if the Provider is in DryRun mode", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.DryRun = true
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: dummyProviderIP,
+						SetIPInterceptor: func(ip string) {
+							Fail("SetIp should not be called in DryRun mode")
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.PublicIP).To(Equal(dummyIp))
+			Expect(provider.Status.ProviderIP).To(Equal(dummyProviderIP))
+			Expect(provider.Status.LastIPChangeTime).To(BeNil())
+			Expect(provider.Status.Records).To(Equal([]ddnsv1alpha1.RecordStatus{{
+				FQDN:         "example.com",
+				Type:         "A",
+				CurrentValue: dummyProviderIP,
+				DesiredValue: dummyIp,
+				Synced:       false,
+			}}))
+
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "DryRun")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("PendingChanges"))
+			Expect(condition.Message).To(Equal(fmt.Sprintf("Would update A records from (%s) to (%s)", dummyProviderIP, dummyIp)))
+
+			By("Reporting the records as out of sync")
+			synced := meta.FindStatusCondition(provider.Status.Conditions, "Synced")
+			Expect(synced).NotTo(BeNil())
+			Expect(synced.Status).To(Equal(metav1.ConditionFalse))
+			Expect(synced.Message).To(Equal(fmt.Sprintf("1 of 1 records out of sync: example.com (A) is %s instead of %s", dummyProviderIP, dummyIp)))
+		})
+
+		It("should defer the update of the IP until an update window opens", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.UpdateWindows = []ddnsv1alpha1.UpdateWindow{{
+				Schedule: "0 0 30 2 *",
+				Duration: metav1.Duration{Duration: time.Hour},
+			}}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: dummyProviderIP,
+						SetIPInterceptor: func(ip string) {
+							Fail("SetIp should not be called outside of the update windows")
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.PublicIP).To(Equal(dummyIp))
+			Expect(provider.Status.ProviderIP).To(Equal(dummyProviderIP))
+
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "UpdateWindow")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("UpdatesDeferred"))
+			Expect(condition.Message).To(Equal(fmt.Sprintf("Would update A records from (%s) to (%s), no update window opens again", dummyProviderIP, dummyIp)))
+		})
+
+		It("should update the IP while an update window is open", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.UpdateWindows = []ddnsv1alpha1.UpdateWindow{{
+				Schedule: "* * * * *",
+				Duration: metav1.Duration{Duration: time.Minute},
+				TimeZone: "Europe/Sofia",
+			}}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			setIp := ""
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: dummyProviderIP,
+						SetIPInterceptor: func(ip string) {
+							setIp = ip
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(setIp).To(Equal(dummyIp))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "UpdateWindow")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("WindowOpen"))
+		})
+
+		It("should skip the IP detection and set the override IP if overrideIP is set", func() {
+			const overrideIP = "127.0.0.10"
+			provider := &ddnsv1alpha1.Provider{}
+			setIp := ""
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.OverrideIP = overrideIP
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					Fail("IPProvider should not be called when overrideIP is set")
+					return "", nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: dummyProviderIP,
+						SetIPInterceptor: func(ip string) {
+							setIp = ip
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(setIp).To(Equal(overrideIP))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.PublicIP).To(Equal(overrideIP))
+		})
+
+		It("should take the public IP from the addresses of the Nodes of the nodeAddress", func() {
+			const nodeIP = "203.0.113.10"
+			provider := &ddnsv1alpha1.Provider{}
+			setIp := ""
+
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-provider-ingress-node",
+				Labels: map[string]string{"node-role.kubernetes.io/ingress": ""},
+			}}
+			Expect(k8sClient.Create(ctx, node)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, node)).To(Succeed()) }()
+
+			node.Status.Addresses = []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.10"},
+				{Type: corev1.NodeExternalIP, Address: nodeIP},
+			}
+			node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, node)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.NodeAddress = &ddnsv1alpha1.NodeAddressSource{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"node-role.kubernetes.io/ingress": ""}},
+			}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					Fail("IPProvider should not be called when nodeAddress is set")
+					return "", nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: dummyProviderIP,
+						SetIPInterceptor: func(ip string) {
+							setIp = ip
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(setIp).To(Equal(nodeIP))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.PublicIP).To(Equal(nodeIP))
+
+			By("Requeuing the Provider when the address of the Node changes")
+			Expect(controllerReconciler.providersForNode(ctx, node)).To(ContainElement(reconcile.Request{NamespacedName: providerNamespacedName}))
+		})
+
+		It("should prefer the addresses of Ready Nodes", func() {
+			ready := []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+			nodes := []corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "a"},
+					Status:     corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: corev1.NodeExternalIP, Address: "203.0.113.1"}}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "b"},
+					Status: corev1.NodeStatus{Conditions: ready, Addresses: []corev1.NodeAddress{
+						{Type: corev1.NodeExternalIP, Address: "203.0.113.2"},
+						{Type: corev1.NodeExternalIP, Address: "2001:db8::2"},
+					}},
+				},
+			}
+
+			Expect(nodeIp(nodes, corev1.NodeExternalIP, false)).To(Equal("203.0.113.2"))
+			Expect(nodeIp(nodes, corev1.NodeExternalIP, true)).To(Equal("2001:db8::2"))
+
+			_, err := nodeIp(nodes, corev1.NodeInternalIP, false)
+			Expect(err).To(MatchError(ContainSubstring("none of the 2 selected Nodes")))
+		})
+
+		It("should fail if overrideIP is not a valid IP address", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.OverrideIP = "not-an-ip"
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{IP: dummyIp}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(MatchError(ContainSubstring("not a valid IP address")))
+		})
+
+		It("should add a finalizer and orphan the records on deletion by default", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: dummyIp,
+						DeleteInterceptor: func(recordType string) {
+							Fail("DeleteRecords should not be called with the Orphan policy")
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Finalizers).To(ContainElement(ddnsv1alpha1.ProviderFinalizer))
+
+			By("Deleting the Provider")
+			Expect(k8sClient.Delete(ctx, provider)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Recreating the Provider for the AfterEach")
+			Expect(k8sClient.Create(ctx, &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      providerNamespacedName.Name,
+					Namespace: providerNamespacedName.Namespace,
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:          "Cloudflare",
+					SecretName:    secretNamespacedName.Name,
+					ConfigMap:     configMapNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(123)),
+				},
+			})).To(Succeed())
+		})
+
+		It("should delete the owned records on deletion with the Delete policy", func() {
+			deletedTypes := []string{}
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.DeletionPolicy = ddnsv1alpha1.DeletionPolicyDelete
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: dummyIp,
+						DeleteInterceptor: func(recordType string) {
+							deletedTypes = append(deletedTypes, recordType)
+						},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Deleting the Provider")
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, provider)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedTypes).To(Equal([]string{clients.RecordTypeA}))
+
+			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Recreating the Provider for the AfterEach")
+			Expect(k8sClient.Create(ctx, &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      providerNamespacedName.Name,
+					Namespace: providerNamespacedName.Namespace,
+				},
+				Spec: ddnsv1alpha1.ProviderSpec{
+					Name:          "Cloudflare",
+					SecretName:    secretNamespacedName.Name,
+					ConfigMap:     configMapNamespacedName.Name,
+					RetryInterval: ptr.To(intstr.FromInt32(123)),
+				},
+			})).To(Succeed())
+		})
+
+		It("should not reconcile if cannot fetch public IP", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			var err error
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return "", fmt.Errorf("cannot fetch public IP")
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP: "",
+					}, nil
+				},
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("cannot fetch public IP"))
+
+			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(provider.Status.PublicIP).To(Equal(""))
+			Expect(provider.Status.FailedSyncCount).To(Equal(int64(1)))
+			Expect(provider.Status.LastError).To(Equal("cannot fetch public IP"))
+			Expect(provider.Status.Message).To(Equal("Sync failed: cannot fetch public IP"))
+
+			By("Clearing the last error on the next successful reconciliation")
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return dummyIp, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.SyncCount).To(Equal(int64(1)))
+			Expect(provider.Status.FailedSyncCount).To(Equal(int64(1)))
+			Expect(provider.Status.LastError).To(BeEmpty())
+		})
+
+		It("should cancel a sync that takes longer than the SyncTimeout and report it", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			controllerReconciler := &ProviderReconciler{
+				Client:      k8sClient,
+				Scheme:      k8sClient.Scheme(),
+				SyncTimeout: 100 * time.Millisecond,
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					<-ctx.Done()
+					return "", ctx.Err()
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{IP: dummyIp}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(err.Error()).To(Equal("sync did not finish within 100ms: context deadline exceeded"))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.FailedSyncCount).To(Equal(int64(1)))
+			Expect(provider.Status.LastError).To(Equal("sync did not finish within 100ms: context deadline exceeded"))
+		})
+
+		It("should retry failures after the errorRetryInterval with backoff", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.RetryInterval = ptr.To(intstr.FromString("2m"))
+			provider.Spec.ErrorRetryInterval = ptr.To(intstr.FromString("30s"))
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return "", fmt.Errorf("cannot fetch public IP")
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{IP: dummyIp}, nil
+				},
+			}
+
+			for _, expected := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 2 * time.Minute} {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(expected))
+			}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.ConsecutiveFailures).To(Equal(int64(4)))
+			Expect(provider.Status.LastError).To(Equal("cannot fetch public IP"))
+
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "Retrying")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("BackingOff"))
+			Expect(condition.Message).To(Equal("4 consecutive failures, retrying in 2m0s: cannot fetch public IP"))
+
+			By("Resetting the backoff on the next successful reconciliation")
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return dummyIp, nil
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(2 * time.Minute))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.ConsecutiveFailures).To(BeZero())
+			Expect(meta.FindStatusCondition(provider.Status.Conditions, "Retrying")).To(BeNil())
+		})
+
+		It("should only report the Provider as Degraded once the failureThreshold is reached", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.FailureThreshold = 2
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return "", fmt.Errorf("cannot fetch public IP")
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "Degraded")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("BelowThreshold"))
+
+			By("Reaching the failureThreshold")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			condition = meta.FindStatusCondition(provider.Status.Conditions, "Degraded")
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("2 consecutive failures: cannot fetch public IP"))
+
+			By("Recovering on the next successful reconciliation")
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return dummyIp, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			condition = meta.FindStatusCondition(provider.Status.Conditions, "Degraded")
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("Healthy"))
+		})
+
+		It("should report the zones that failed and return an aggregated error", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp, GetIPError: &clients.ZoneError{Zone: "example.org", Err: fmt.Errorf("permission denied")}}, nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(MatchError("1 of 1 zones failed: zone example.org: permission denied"))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.Zones).To(HaveLen(1))
+			Expect(provider.Status.Zones[0].Name).To(Equal("example.org"))
+			condition := meta.FindStatusCondition(provider.Status.Zones[0].Conditions, "Ready")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("permission denied"))
+
+			By("Marking the zone as Ready once it syncs again")
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.Zones).To(BeEmpty())
+		})
+
+		It("should requeue sooner while the records are still out of sync after an update", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyProviderIP}, nil
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.OutOfSyncSince).NotTo(BeNil())
+
+			By("Clearing it once the records are in sync")
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Second * 123))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.OutOfSyncSince).To(BeNil())
+		})
+
+		It("should record how long the records were out of sync until they were updated", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyProviderIP}, nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.DesyncedSince).NotTo(BeNil())
+			Expect(provider.Status.LastDesyncDuration).To(BeNil())
+
+			By("Keeping it through failed reconciliations")
+			desyncedSince := provider.Status.DesyncedSince
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return "", fmt.Errorf("cannot fetch public IP")
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.DesyncedSince).To(Equal(desyncedSince))
+
+			By("Recording the duration once the records are in sync")
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return dummyIp, nil
+			}
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.DesyncedSince).To(BeNil())
+			Expect(provider.Status.LastDesyncDuration).NotTo(BeNil())
+
+			metric := &dto.Metric{}
+			observer := desyncDuration.WithLabelValues("Provider", providerNamespacedName.Namespace, providerNamespacedName.Name)
+			Expect(observer.(prometheus.Metric).Write(metric)).To(Succeed())
+			Expect(metric.GetHistogram().GetSampleCount()).To(BeNumerically(">=", 1))
+		})
+
+		It("should report the outcome of the reconciliations in the metrics", func() {
+			// metricValue returns the value of the counter or gauge of the Provider
+			metricValue := func(collector prometheus.Collector) float64 {
+				metric := &dto.Metric{}
+				Expect(collector.(prometheus.Metric).Write(metric)).To(Succeed())
+
+				if metric.GetGauge() != nil {
+					return metric.GetGauge().GetValue()
+				}
+
+				return metric.GetCounter().GetValue()
+			}
+
+			labels := []string{"Provider", providerNamespacedName.Namespace, providerNamespacedName.Name}
+			successes := metricValue(reconcileResults.WithLabelValues(append(labels, "success")...))
+			failures := metricValue(reconcileResults.WithLabelValues(append(labels, "error")...))
+			updates := metricValue(recordUpdates.WithLabelValues(append(labels, "A")...))
+			changes := metricValue(ipChanges.WithLabelValues(append(labels, "A")...))
+			apiErrors := metricValue(providerAPIErrors.WithLabelValues(labels...))
+
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyProviderIP}, nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(metricValue(reconcileResults.WithLabelValues(append(labels, "success")...))).To(Equal(successes + 1))
+			Expect(metricValue(recordUpdates.WithLabelValues(append(labels, "A")...))).To(Equal(updates + 1))
+			Expect(metricValue(ipChanges.WithLabelValues(append(labels, "A")...))).To(Equal(changes + 1))
+			Expect(metricValue(lastSyncTimestamp.WithLabelValues(labels...))).To(BeNumerically(">", 0))
+
+			By("Counting the failed calls to the provider")
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyProviderIP, GetIPError: fmt.Errorf("provider is unavailable")}, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(metricValue(reconcileResults.WithLabelValues(append(labels, "error")...))).To(Equal(failures + 1))
+			Expect(metricValue(providerAPIErrors.WithLabelValues(labels...))).To(Equal(apiErrors + 1))
+		})
+
+		It("should only verify the records at the provider every retryInterval between drift checks", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			getIpCalls := 0
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.DriftCheckInterval = ptr.To(intstr.FromInt32(60))
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp, GetIPInterceptor: func() { getIpCalls++ }}, nil
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(getIpCalls).NotTo(BeZero())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			syncCount := provider.Status.SyncCount
+
+			By("Only checking the public IP while it did not change")
+			getIpCalls = 0
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+			Expect(getIpCalls).To(BeZero())
+
+			By("Not counting the drift check as a sync")
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.SyncCount).To(Equal(syncCount))
+
+			By("Verifying the records as soon as the public IP changes")
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return dummyProviderIP, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getIpCalls).NotTo(BeZero())
+		})
+
+		It("should use the drift check interval of the controller for Providers that do not set one", func() {
+			getIpCalls := 0
+
+			controllerReconciler.DriftCheckInterval = time.Minute
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp, GetIPInterceptor: func() { getIpCalls++ }}, nil
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(getIpCalls).NotTo(BeZero())
+
+			By("Not calling the provider while the public IP did not change")
+			getIpCalls = 0
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getIpCalls).To(BeZero())
+		})
+
+		It("should reuse the records of the last sync while the public IP does not change", func() {
+			getIpCalls := 0
+			publicIp := dummyIp
+
+			controllerReconciler.records = &providerRecords{}
+			controllerReconciler.RecordsVerifyInterval = time.Hour
+			controllerReconciler.IPProvider = func(ctx context.Context, c string) (string, error) {
+				return publicIp, nil
+			}
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp, GetIPInterceptor: func() { getIpCalls++ }}, nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getIpCalls).NotTo(BeZero())
+
+			By("Not listing the records while the public IP did not change")
+			getIpCalls = 0
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Second * 123))
+			Expect(getIpCalls).To(BeZero())
+
+			provider := &ddnsv1alpha1.Provider{}
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(provider.Status.ProviderIP).To(Equal(dummyIp))
+			Expect(provider.Status.Records).To(HaveLen(1))
+			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, "Synced")).To(BeTrue())
+
+			By("Listing the records again once the public IP changed")
+			publicIp = dummyProviderIP
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getIpCalls).NotTo(BeZero())
+
+			By("Listing the records again once the verify interval passed")
+			publicIp = dummyIp
+			controllerReconciler.RecordsVerifyInterval = time.Nanosecond
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			getIpCalls = 0
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getIpCalls).NotTo(BeZero())
+		})
+
+		It("should verify the propagation of the records at the resolvers of the propagationCheck", func() {
+			provider := &ddnsv1alpha1.Provider{}
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			provider.Spec.PropagationCheck = &ddnsv1alpha1.PropagationCheck{}
+			Expect(k8sClient.Update(ctx, provider)).To(Succeed())
+			Expect(provider.Spec.PropagationCheck.Resolvers).To(Equal([]string{"1.1.1.1", "8.8.8.8"}))
+
+			resolved := dummyProviderIP
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyIp}, nil
+			}
+			controllerReconciler.Resolver = func(ctx context.Context, resolver, name, recordType string) ([]string, error) {
+				return []string{resolved}, nil
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "Propagated")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal(fmt.Sprintf(
+				"Waiting for example.com (A) at 1.1.1.1 is %[1]s, example.com (A) at 8.8.8.8 is %[1]s", dummyProviderIP,
+			)))
+
+			By("Marking the records as propagated once the resolvers return the new IP")
+			resolved = dummyIp
+
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Second * 123))
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(provider.Status.Conditions, "Propagated")).To(BeTrue())
+
+			By("Not resolving the records again until they are updated")
+			controllerReconciler.Resolver = func(ctx context.Context, resolver, name, recordType string) ([]string, error) {
+				Fail("the records should not be resolved again")
+				return nil, nil
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report records that were changed outside of the controller", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+
+			ip := dummyIp
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: ip}, nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(meta.FindStatusCondition(provider.Status.Conditions, "DriftDetected")).To(BeNil())
+
+			By("Detecting the record that was changed in the DNS provider")
+			ip = "10.0.0.1"
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "DriftDetected")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal(fmt.Sprintf("Changed outside of the controller: example.com (A) from %s to 10.0.0.1", dummyIp)))
+			// The changed record is updated back to the public IP as well
+			Expect(receivedEvents(recorder)).To(ContainElements(
+				fmt.Sprintf("Warning DriftDetected Record changed outside of the controller: example.com (A) from %s to 10.0.0.1", dummyIp),
+				fmt.Sprintf("Normal RecordUpdated Updated example.com A 10.0.0.1 → %s", dummyIp),
+			))
+
+			By("Clearing it once no more changes are detected")
+			ip = dummyIp
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, providerNamespacedName, provider)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "DriftDetected")).To(BeTrue())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should back off with the errorRetryInterval of the controller if the Provider sets none", func() {
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return "", fmt.Errorf("cannot fetch public IP")
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{IP: dummyIp}, nil
+				},
+				ErrorRetryInterval: 10 * time.Second,
+			}
+
+			for _, expected := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 123 * time.Second} {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(expected))
+			}
+		})
+
+		It("should not reconcile if the ClientFactory cannot create a provider", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			var err error
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return nil, fmt.Errorf("cannot create client")
+				},
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("cannot create client"))
+
+			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(provider.Status.Conditions).To(HaveLen(5))
+			Expect(meta.IsStatusConditionFalse(provider.Status.Conditions, "Client")).To(BeTrue())
+
+			condition := meta.FindStatusCondition(provider.Status.Conditions, "Client")
+			Expect(condition.Message).To(Equal("cannot create client"))
+
+			readyCondition := meta.FindStatusCondition(provider.Status.Conditions, "Ready")
+			Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(readyCondition.Reason).To(Equal("ReconcileFailed"))
+			Expect(readyCondition.Message).To(Equal("cannot create client"))
+		})
+
+		It("should not reconcile if the ProviderIP cannot be fetched", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			var err error
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP:         "",
+						GetIPError: fmt.Errorf("cannot get IP"),
+					}, nil
+				},
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("cannot get IP"))
+
+			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(provider.Status.PublicIP).To(Equal(dummyIp))
+			Expect(provider.Status.ProviderIP).To(Equal(""))
+		})
+
+		It("should not reconcile if the ProviderIP cannot be set", func() {
+			provider := &ddnsv1alpha1.Provider{}
+			var err error
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP:         "",
+						SetIPError: fmt.Errorf("cannot set IP"),
+					}, nil
+				},
+			}
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("cannot set IP"))
+
+			err = k8sClient.Get(ctx, providerNamespacedName, provider)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should record an event if the records cannot be synced", func() {
+			recorder := record.NewFakeRecorder(10)
+
+			controllerReconciler := &ProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				IPProvider: func(ctx context.Context, c string) (string, error) {
+					return dummyIp, nil
+				},
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					return MockClient{
+						IP:         "",
+						SetIPError: fmt.Errorf("cannot set IP"),
+					}, nil
+				},
+				Recorder: recorder,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(receivedEvents(recorder)).To(ContainElement("Warning SyncFailed Could not sync the records: cannot set IP"))
+		})
+
+		It("should record the changes of the public IP as IPChanges", func() {
+			var setIPError error
+			controllerReconciler.IPChangeHistoryLimit = 2
+			controllerReconciler.ClientFactory = func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				return MockClient{IP: dummyProviderIP, SetIPError: setIPError}, nil
+			}
+			DeferCleanup(func() {
+				Expect(k8sClient.DeleteAllOf(ctx, &ddnsv1alpha1.IPChange{}, client.InNamespace(providerNamespacedName.Namespace))).To(Succeed())
+			})
+
+			ipChanges := func() []ddnsv1alpha1.IPChange {
+				changes, err := controllerReconciler.listIPChanges(ctx, providerNamespacedName.Namespace, &ddnsv1alpha1.Provider{
+					ObjectMeta: metav1.ObjectMeta{Name: providerNamespacedName.Name, Namespace: providerNamespacedName.Namespace},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				return changes
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			changes := ipChanges()
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Labels).To(HaveKeyWithValue(ddnsv1alpha1.IPChangeProviderLabel, providerNamespacedName.Name))
+			Expect(changes[0].OwnerReferences).To(HaveLen(1))
+			Expect(changes[0].Spec.ProviderRef).To(Equal(ddnsv1alpha1.ProviderRef{Kind: ddnsv1alpha1.ProviderKind, Name: providerNamespacedName.Name}))
+			Expect(changes[0].Spec.Type).To(Equal("A"))
+			Expect(changes[0].Spec.OldIP).To(Equal(dummyProviderIP))
+			Expect(changes[0].Spec.NewIP).To(Equal(dummyIp))
+			Expect(changes[0].Spec.Records).To(Equal([]string{"example.com"}))
+			Expect(changes[0].Spec.Trigger).To(Equal(ddnsv1alpha1.IPChangeTriggerScheduled))
+			Expect(changes[0].Spec.Outcome).To(Equal(ddnsv1alpha1.IPChangeOutcomeUpdated))
+
+			By("Not recording the same change twice")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ipChanges()).To(HaveLen(1))
+
+			By("Recording a failed update")
+			setIPError = fmt.Errorf("cannot set IP")
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			changes = ipChanges()
+			Expect(changes).To(HaveLen(2))
+			Expect(changes[0].Spec.Outcome).To(Equal(ddnsv1alpha1.IPChangeOutcomeFailed))
+			Expect(changes[0].Spec.Message).To(Equal("cannot set IP"))
+
+			By("Deleting the oldest IPChanges beyond the limit")
+			setIPError = nil
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: providerNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			changes = ipChanges()
+			Expect(changes).To(HaveLen(2))
+			Expect(changes[0].Spec.Outcome).To(Equal(ddnsv1alpha1.IPChangeOutcomeUpdated))
+			Expect(changes[1].Spec.Outcome).To(Equal(ddnsv1alpha1.IPChangeOutcomeFailed))
 		})
 
 		It("should not reconcile if we cannot patch the public ip in the Status", func() {
@@ -628,10 +2401,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: clientWrapper,
 				Scheme: clientWrapper.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: "",
 					}, nil
@@ -659,10 +2432,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: clientWrapper,
 				Scheme: clientWrapper.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: dummyIp,
 					}, nil
@@ -690,10 +2463,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: clientWrapper,
 				Scheme: clientWrapper.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: "1.1.1.1",
 					}, nil
@@ -721,10 +2494,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: clientWrapper,
 				Scheme: clientWrapper.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{
 						IP: "1.1.1.1",
 					}, nil
@@ -762,10 +2535,10 @@ var _ = Describe("Provider Controller", func() {
 			controllerReconciler := &ProviderReconciler{
 				Client: k8sClient,
 				Scheme: k8sClient.Scheme(),
-				IPProvider: func(c string) (string, error) {
+				IPProvider: func(ctx context.Context, c string) (string, error) {
 					return dummyIp, nil
 				},
-				ClientFactory: func(name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
 					return MockClient{}, nil
 				},
 			}
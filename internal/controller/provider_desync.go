@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// desyncDuration is how long the records of a Provider were out of sync, from detecting that they differ from the public
+// IP to verifying that they were updated
+var desyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "ddns_provider_desync_duration_seconds",
+	Help:    "How long the records of a Provider were out of sync, from detecting the desync until the records were updated.",
+	Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 4 * 3600, 12 * 3600, 24 * 3600},
+}, []string{"kind", "namespace", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(desyncDuration)
+}
+
+// providerKind returns the kind of the provider, used to tell Providers and ClusterProviders apart in the metrics
+func providerKind(provider ddnsv1alpha1.ProviderObject) string {
+	if _, ok := provider.(*ddnsv1alpha1.ClusterProvider); ok {
+		return "ClusterProvider"
+	}
+
+	return "Provider"
+}
+
+// patchDesyncedSince sets since when the records are out of sync, unless they already were. It is kept through failed
+// reconciliations, so the desync is measured until the records are actually updated
+func (p ProviderReconciler) patchDesyncedSince() func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+		if status.DesyncedSince != nil {
+			return false
+		}
+
+		now := metav1.Now()
+		status.DesyncedSince = &now
+
+		return true
+	}
+}
+
+// patchResynced clears DesyncedSince once the records are verified to be in sync again, keeping how long they were
+// out of sync in LastDesyncDuration
+func (p ProviderReconciler) patchResynced(now time.Time) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+		if status.DesyncedSince == nil {
+			return false
+		}
+
+		status.LastDesyncDuration = &metav1.Duration{Duration: now.Sub(status.DesyncedSince.Time).Truncate(time.Second)}
+		status.DesyncedSince = nil
+
+		return true
+	}
+}
+
+// recordResynced reports how long the records were out of sync, once they are in sync again
+func (r *ProviderReconciler) recordResynced(ctx context.Context, provider ddnsv1alpha1.ProviderObject) {
+	since := provider.GetProviderStatus().DesyncedSince
+	if since == nil {
+		return
+	}
+
+	now := time.Now()
+	r.setStatus(provider, r.patchResynced(now))
+
+	duration := now.Sub(since.Time)
+	log.FromContext(ctx).Info("Records are in sync again", "after", duration.Truncate(time.Second))
+	desyncDuration.WithLabelValues(providerKind(provider), provider.GetNamespace(), provider.GetName()).Observe(duration.Seconds())
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// driftedRecords describes the records that were changed outside of the controller, e.g. "www.example.com (A) from 1.2.3.4 to 1.2.3.5".
+// A record drifted if it was in sync at the last reconciliation and now has a value that is neither the one it had then,
+// nor the public IP, or a proxied setting that it enforces was changed
+func driftedRecords(previous []ddnsv1alpha1.RecordStatus, records []clients.DNSRecord, publicIp string) []string {
+	drifted := []string{}
+
+	for _, record := range records {
+		for _, last := range previous {
+			if last.FQDN != record.Name || last.Type != record.Type || !last.Synced {
+				continue
+			}
+
+			if record.Content == last.CurrentValue || record.Content == publicIp {
+				if record.ProxiedDrift {
+					drifted = append(drifted, fmt.Sprintf("%s (%s) proxied setting was changed", record.Name, record.Type))
+				}
+
+				break
+			}
+
+			if record.Content == "" {
+				drifted = append(drifted, fmt.Sprintf("%s (%s) was deleted", record.Name, record.Type))
+			} else {
+				drifted = append(drifted, fmt.Sprintf("%s (%s) from %s to %s", record.Name, record.Type, last.CurrentValue, record.Content))
+			}
+
+			break
+		}
+	}
+
+	return drifted
+}
+
+// proxiedDrifted describes the records whose proxied setting differs from the one they enforce, e.g.
+// "proxied setting of www.example.com (A)"
+func proxiedDrifted(records []clients.DNSRecord) []string {
+	drifted := []string{}
+	for _, record := range records {
+		if record.ProxiedDrift {
+			drifted = append(drifted, fmt.Sprintf("proxied setting of %s (%s)", record.Name, record.Type))
+		}
+	}
+
+	return drifted
+}
+
+// patchDriftDetected reports the records that were changed outside of the controller in the DriftDetected condition and
+// records an event for every one of them. The condition is only added once drift is detected and is set to False by the
+// next reconciliation that detects none
+func (r *ProviderReconciler) patchDriftDetected(ctx context.Context, provider ddnsv1alpha1.ProviderObject, drifted []string) {
+	if len(drifted) == 0 {
+		if !meta.IsStatusConditionTrue(provider.GetProviderStatus().Conditions, ddnsv1alpha1.ProviderConditionTypeDriftDetected) {
+			return
+		}
+
+		conditions.PatchConditionsSet(provider).Set(ddnsv1alpha1.ProviderConditionTypeDriftDetected,
+			conditions.WithReasonAndMessage("NoDrift", "No records were changed outside of the controller"),
+			conditions.False(),
+		)
+
+		return
+	}
+
+	log.FromContext(ctx).Info("Records were changed outside of the controller", "records", drifted)
+
+	for _, record := range drifted {
+		r.eventf(provider, corev1.EventTypeWarning, "DriftDetected", "Record changed outside of the controller: %s", record)
+	}
+
+	conditions.PatchConditionsSet(provider).Set(ddnsv1alpha1.ProviderConditionTypeDriftDetected,
+		conditions.WithReasonAndMessage("RecordsChanged", fmt.Sprintf("Changed outside of the controller: %s", strings.Join(drifted, ", "))),
+		conditions.True(),
+	)
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// eventf records an event on the provider, if there is a Recorder
+func (r *ProviderReconciler) eventf(provider ddnsv1alpha1.ProviderObject, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(provider, eventType, reason, messageFmt, args...)
+	}
+}
+
+// recordUpdatedEvents records a Normal event for every record of a single type that was updated at the provider, e.g.
+// "Updated example.com A 1.2.3.4 → 5.6.7.8". The records are the ones read before the update. Records in the zones that
+// failed to update are left out
+func (r *ProviderReconciler) recordUpdatedEvents(
+	provider ddnsv1alpha1.ProviderObject,
+	publicIp string,
+	dnsRecords []clients.DNSRecord,
+	zoneErrs map[string]error,
+) {
+	for _, record := range dnsRecords {
+		if _, failed := zoneErrs[record.Zone]; failed {
+			continue
+		}
+
+		if record.Content != publicIp {
+			r.eventf(provider, corev1.EventTypeNormal, "RecordUpdated", "Updated %s %s %s → %s", record.Name, record.Type, record.Content, publicIp)
+		} else if record.ProxiedDrift {
+			r.eventf(provider, corev1.EventTypeNormal, "RecordUpdated", "Updated the proxied setting of %s %s to %t", record.Name, record.Type, record.Proxied)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// newIPChange describes the change of the records of a single type of the provider from providerIp to publicIp
+func newIPChange(
+	provider ddnsv1alpha1.ProviderObject,
+	recordType, providerIp, publicIp string,
+	dnsRecords []clients.DNSRecord,
+	syncNow bool,
+) ddnsv1alpha1.IPChangeSpec {
+	trigger := ddnsv1alpha1.IPChangeTriggerScheduled
+	if syncNow {
+		trigger = ddnsv1alpha1.IPChangeTriggerSyncRequested
+	}
+
+	records := []string{}
+	for _, record := range dnsRecords {
+		if record.Content != publicIp && !slices.Contains(records, record.Name) {
+			records = append(records, record.Name)
+		}
+	}
+
+	return ddnsv1alpha1.IPChangeSpec{
+		ProviderRef: ddnsv1alpha1.ProviderRef{Kind: providerKind(provider), Name: provider.GetName()},
+		Type:        recordType,
+		OldIP:       providerIp,
+		NewIP:       publicIp,
+		Records:     records,
+		Trigger:     trigger,
+		Time:        metav1.NowMicro(),
+	}
+}
+
+// recordIPChange creates an IPChange in the namespace with the outcome of the change, unless the last IPChange of the
+// provider for the same type of records already recorded it, e.g. in every reconciliation in dry run.
+// No IPChanges are recorded if the IPChangeHistoryLimit is 0. Failing to record one is only logged, so it does not hold
+// up the sync
+func (r *ProviderReconciler) recordIPChange(
+	ctx context.Context,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+	change ddnsv1alpha1.IPChangeSpec,
+	outcome ddnsv1alpha1.IPChangeOutcome,
+	err error,
+) {
+	if r.IPChangeHistoryLimit <= 0 {
+		return
+	}
+
+	change.Outcome = outcome
+	if err != nil {
+		change.Message = err.Error()
+	}
+
+	ipChanges, listErr := r.listIPChanges(ctx, namespace, provider)
+	if listErr != nil {
+		log.FromContext(ctx).Error(listErr, "unable to list the IPChanges of the provider")
+		return
+	}
+
+	for _, last := range ipChanges {
+		if last.Spec.Type != change.Type {
+			continue
+		}
+
+		if last.Spec.OldIP == change.OldIP && last.Spec.NewIP == change.NewIP && last.Spec.Outcome == change.Outcome {
+			return
+		}
+
+		break
+	}
+
+	ipChange := &ddnsv1alpha1.IPChange{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: provider.GetName() + "-",
+			Namespace:    namespace,
+			Labels:       map[string]string{ddnsv1alpha1.IPChangeProviderLabel: provider.GetName()},
+		},
+		Spec: change,
+	}
+
+	// The IPChanges are garbage collected with their provider
+	if err := controllerutil.SetOwnerReference(provider, ipChange, r.Scheme); err != nil {
+		log.FromContext(ctx).Error(err, "unable to set the owner of the IPChange")
+		return
+	}
+
+	if err := r.Create(ctx, ipChange); err != nil {
+		log.FromContext(ctx).Error(err, "unable to record the IPChange", "type", change.Type, "outcome", change.Outcome)
+	}
+}
+
+// pruneIPChanges deletes the IPChanges of the provider in the namespace beyond the IPChangeHistoryLimit, the oldest ones
+// first, and the ones older than the IPChangeTTL. Failing to delete them is only logged
+func (r *ProviderReconciler) pruneIPChanges(ctx context.Context, namespace string, provider ddnsv1alpha1.ProviderObject) {
+	if r.IPChangeHistoryLimit <= 0 {
+		return
+	}
+
+	ipChanges, err := r.listIPChanges(ctx, namespace, provider)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "unable to list the IPChanges of the provider")
+		return
+	}
+
+	for i, ipChange := range ipChanges {
+		expired := r.IPChangeTTL > 0 && time.Since(ipChange.Spec.Time.Time) > r.IPChangeTTL
+		if i < r.IPChangeHistoryLimit && !expired {
+			continue
+		}
+
+		if err := r.Delete(ctx, &ipChange); client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).Error(err, "unable to delete the IPChange", "name", ipChange.Name)
+		}
+	}
+}
+
+// listIPChanges lists the IPChanges of the provider in the namespace, the latest first
+func (r *ProviderReconciler) listIPChanges(
+	ctx context.Context,
+	namespace string,
+	provider ddnsv1alpha1.ProviderObject,
+) ([]ddnsv1alpha1.IPChange, error) {
+	list := &ddnsv1alpha1.IPChangeList{}
+	if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels{
+		ddnsv1alpha1.IPChangeProviderLabel: provider.GetName(),
+	}); err != nil {
+		return nil, err
+	}
+
+	// Providers and ClusterProviders of the same name share the label
+	ipChanges := slices.DeleteFunc(list.Items, func(ipChange ddnsv1alpha1.IPChange) bool {
+		return ipChange.Spec.ProviderRef.Kind != providerKind(provider)
+	})
+
+	slices.SortStableFunc(ipChanges, func(a, b ddnsv1alpha1.IPChange) int {
+		if c := b.Spec.Time.Compare(a.Spec.Time.Time); c != 0 {
+			return c
+		}
+
+		return strings.Compare(b.Name, a.Name)
+	})
+
+	return ipChanges, nil
+}
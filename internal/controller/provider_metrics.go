@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/tracing"
+)
+
+// The metrics of the Providers are labeled by the kind, namespace and name of the Provider, like desyncDuration, so
+// alerts can be set up for every Provider, e.g. on no successful sync within an hour
+var (
+	reconcileResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ddns_provider_reconcile_total",
+		Help: "How many times a Provider was reconciled, by result: success or error.",
+	}, []string{"kind", "namespace", "name", "result"})
+
+	recordUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ddns_provider_record_updates_total",
+		Help: "How many times the records of a Provider were updated at the provider, by record type.",
+	}, []string{"kind", "namespace", "name", "type"})
+
+	ipChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ddns_provider_ip_changes_total",
+		Help: "How many times the records of a Provider were pointed at a new public IP, by record type.",
+	}, []string{"kind", "namespace", "name", "type"})
+
+	lastSyncTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ddns_provider_last_sync_timestamp_seconds",
+		Help: "When the records of a Provider were last verified to be in sync, as a Unix timestamp.",
+	}, []string{"kind", "namespace", "name"})
+
+	providerAPIErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ddns_provider_api_errors_total",
+		Help: "How many calls to the API of the provider of a Provider failed.",
+	}, []string{"kind", "namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileResults, recordUpdates, ipChanges, lastSyncTimestamp, providerAPIErrors)
+}
+
+// metricLabels returns the kind, namespace and name of the provider, followed by the given labels
+func metricLabels(provider ddnsv1alpha1.ProviderObject, labels ...string) []string {
+	return append([]string{providerKind(provider), provider.GetNamespace(), provider.GetName()}, labels...)
+}
+
+// recordReconcileResult counts the reconciliation of the provider as a success or an error
+func recordReconcileResult(provider ddnsv1alpha1.ProviderObject, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	reconcileResults.WithLabelValues(metricLabels(provider, result)...).Inc()
+}
+
+// recordLastSync reports when the records of the provider were last verified to be in sync
+func recordLastSync(provider ddnsv1alpha1.ProviderObject, syncTime time.Time) {
+	lastSyncTimestamp.WithLabelValues(metricLabels(provider)...).Set(float64(syncTime.Unix()))
+}
+
+// countAPIError counts a failed call to the API of the provider and returns the error
+func countAPIError(provider ddnsv1alpha1.ProviderObject, err error) error {
+	if err != nil {
+		providerAPIErrors.WithLabelValues(metricLabels(provider)...).Inc()
+	}
+
+	return err
+}
+
+// callProvider calls the API of the provider in a span of the operation on the records of the type, counting the call in
+// the metrics if it fails
+func callProvider[T any](
+	ctx context.Context,
+	provider ddnsv1alpha1.ProviderObject,
+	operation string,
+	recordType string,
+	call func() (T, error),
+) (T, error) {
+	_, span := tracing.Start(ctx, "Provider."+operation, attribute.String("dns.record_type", recordType))
+	result, err := call()
+	tracing.End(span, err)
+
+	return result, countAPIError(provider, err)
+}
+
+// callProviderErr is callProvider for the calls that only return an error
+func callProviderErr(ctx context.Context, provider ddnsv1alpha1.ProviderObject, operation, recordType string, call func() error) error {
+	_, err := callProvider(ctx, provider, operation, recordType, func() (struct{}, error) { return struct{}{}, call() })
+
+	return err
+}
+
+// deleteProviderMetrics removes the metrics of a deleted provider, so they are not reported until the controller restarts
+func deleteProviderMetrics(provider ddnsv1alpha1.ProviderObject) {
+	labels := prometheus.Labels{"kind": providerKind(provider), "namespace": provider.GetNamespace(), "name": provider.GetName()}
+
+	for _, vec := range []*prometheus.MetricVec{
+		reconcileResults.MetricVec, recordUpdates.MetricVec, ipChanges.MetricVec, lastSyncTimestamp.MetricVec, providerAPIErrors.MetricVec,
+		desyncDuration.MetricVec,
+	} {
+		vec.DeletePartialMatch(labels)
+	}
+}
@@ -2,7 +2,13 @@ package controller
 
 import (
 	"context"
+	"slices"
 
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -10,24 +16,109 @@ type MockClient struct {
 	SetIPError       error
 	GetIPError       error
 	IP               string
+	IPv6             string
+	DeleteError      error
 	SetIPInterceptor func(string)
 	GetIPInterceptor func()
+	// DeleteInterceptor is called with the recordType of the records being deleted
+	DeleteInterceptor func(string)
 }
 
-func (c MockClient) GetIp() ([]string, error) {
+func (c MockClient) GetIp(recordType string) ([]string, error) {
 	if c.GetIPInterceptor != nil {
 		c.GetIPInterceptor()
 	}
+	if recordType == clients.RecordTypeAAAA {
+		return []string{c.IPv6}, c.GetIPError
+	}
 	return []string{c.IP}, c.GetIPError
 }
 
-func (c MockClient) SetIp(ip string) error {
+func (c MockClient) GetRecords(recordType string) ([]clients.DNSRecord, error) {
+	ips, err := c.GetIp(recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	return []clients.DNSRecord{{Zone: "example.com", Name: "example.com", Type: recordType, Content: ips[0]}}, nil
+}
+
+func (c MockClient) SetIp(ip string, recordType string) error {
 	if c.SetIPInterceptor != nil {
 		c.SetIPInterceptor(ip)
 	}
 	return c.SetIPError
 }
 
+func (c MockClient) DeleteRecords(recordType string) error {
+	if c.DeleteInterceptor != nil {
+		c.DeleteInterceptor(recordType)
+	}
+	return c.DeleteError
+}
+
+// MockRecordsUpdater is a MockClient with fixed records that can update some of them only
+type MockRecordsUpdater struct {
+	MockClient
+	Records []clients.DNSRecord
+	// UpdateInterceptor is called with the records being updated
+	UpdateInterceptor func([]clients.DNSRecord)
+}
+
+func (c MockRecordsUpdater) GetRecords(recordType string) ([]clients.DNSRecord, error) {
+	return c.Records, c.GetIPError
+}
+
+func (c MockRecordsUpdater) UpdateRecords(ip string, records []clients.DNSRecord) error {
+	if c.UpdateInterceptor != nil {
+		c.UpdateInterceptor(records)
+	}
+	return c.SetIPError
+}
+
+// MockRecordsAdopter is a MockClient that can adopt existing records
+type MockRecordsAdopter struct {
+	MockClient
+	// AdoptInterceptor is called with the records being adopted
+	AdoptInterceptor func([]clients.DNSRecord)
+}
+
+func (c MockRecordsAdopter) AdoptRecords(records []clients.DNSRecord) error {
+	if c.AdoptInterceptor != nil {
+		c.AdoptInterceptor(records)
+	}
+	return c.SetIPError
+}
+
+// MockRecordClient is a MockClient that can also manage single records
+type MockRecordClient struct {
+	MockClient
+
+	Record                  *clients.DNSRecord
+	GetRecordError          error
+	UpsertError             error
+	UpsertInterceptor       func(clients.DNSRecord)
+	DeleteRecordInterceptor func(clients.DNSRecord)
+}
+
+func (c MockRecordClient) GetRecord(record clients.DNSRecord) (*clients.DNSRecord, error) {
+	return c.Record, c.GetRecordError
+}
+
+func (c MockRecordClient) UpsertRecord(record clients.DNSRecord) error {
+	if c.UpsertInterceptor != nil {
+		c.UpsertInterceptor(record)
+	}
+	return c.UpsertError
+}
+
+func (c MockRecordClient) DeleteRecord(record clients.DNSRecord) error {
+	if c.DeleteRecordInterceptor != nil {
+		c.DeleteRecordInterceptor(record)
+	}
+	return c.DeleteError
+}
+
 type ClientWrapper struct {
 	client.Client
 
@@ -79,3 +170,55 @@ func (s *StatusWriterWrapper) Patch(ctx context.Context, obj client.Object, patc
 
 	return s.StatusWriter.Patch(ctx, obj, patch, opts...)
 }
+
+// IndexedClient emulates the field indexes of the cache of a manager for a client that talks to the API server directly,
+// as the API server does not know about them. Lists with a field selector on a registered index are filtered in memory
+type IndexedClient struct {
+	client.Client
+
+	indexes map[string]client.IndexerFunc
+}
+
+func (c *IndexedClient) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	if c.indexes == nil {
+		c.indexes = make(map[string]client.IndexerFunc)
+	}
+
+	c.indexes[field] = extractValue
+
+	return nil
+}
+
+func (c *IndexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	if listOpts.FieldSelector == nil || len(listOpts.FieldSelector.Requirements()) != 1 {
+		return c.Client.List(ctx, list, opts...)
+	}
+
+	requirement := listOpts.FieldSelector.Requirements()[0]
+	extractValue, ok := c.indexes[requirement.Field]
+	if !ok || (requirement.Operator != selection.Equals && requirement.Operator != selection.DoubleEquals) {
+		return c.Client.List(ctx, list, opts...)
+	}
+
+	listOpts.FieldSelector = nil
+	if err := c.Client.List(ctx, list, listOpts); err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	matching := []runtime.Object{}
+	for _, item := range items {
+		if slices.Contains(extractValue(item.(client.Object)), requirement.Value) {
+			matching = append(matching, item)
+		}
+	}
+
+	return meta.SetList(list, matching)
+}
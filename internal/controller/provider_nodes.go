@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// nodeIpProvider returns an IPProvider that takes the IP of the family from the addresses of the Nodes selected by the
+// NodeAddress of a Provider, instead of detecting it
+func (r *ProviderReconciler) nodeIpProvider(source *ddnsv1alpha1.NodeAddressSource, ipv6 bool) IPProvider {
+	return func(ctx context.Context, _ string) (string, error) {
+		if r.NamespaceScoped {
+			return "", fmt.Errorf("nodeAddress cannot be used, as the controller only watches some namespaces")
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(&source.Selector)
+		if err != nil {
+			return "", fmt.Errorf("invalid nodeAddress selector: %w", err)
+		}
+
+		nodes := &corev1.NodeList{}
+		if err := r.List(ctx, nodes, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return "", fmt.Errorf("unable to list the Nodes of the nodeAddress: %w", err)
+		}
+
+		return nodeIp(nodes.Items, nodeAddressType(source), ipv6)
+	}
+}
+
+// nodeAddressType returns the type of the addresses of the Nodes that are used, ExternalIP if not set
+func nodeAddressType(source *ddnsv1alpha1.NodeAddressSource) corev1.NodeAddressType {
+	if source.AddressType == "" {
+		return corev1.NodeExternalIP
+	}
+
+	return corev1.NodeAddressType(source.AddressType)
+}
+
+// nodeIp returns the first address of the type and family of the Nodes, sorted by name. Ready Nodes are preferred, so
+// the records move to a replacement as soon as a node goes down
+func nodeIp(nodes []corev1.Node, addressType corev1.NodeAddressType, ipv6 bool) (string, error) {
+	slices.SortFunc(nodes, func(a, b corev1.Node) int {
+		if nodeReady(&a) != nodeReady(&b) {
+			if nodeReady(&a) {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, node := range nodes {
+		for _, address := range node.Status.Addresses {
+			ip := net.ParseIP(address.Address)
+			if address.Type != addressType || ip == nil || (ip.To4() == nil) != ipv6 {
+				continue
+			}
+
+			return address.Address, nil
+		}
+	}
+
+	family := "IPv4"
+	if ipv6 {
+		family = "IPv6"
+	}
+
+	return "", fmt.Errorf("none of the %d selected Nodes has an %s %s address", len(nodes), family, addressType)
+}
+
+// nodeReady returns true if the Ready condition of the Node is True
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// selectsNode returns true if the Provider takes its public IP from the addresses of Nodes that include the Node
+func selectsNode(spec *ddnsv1alpha1.ProviderSpec, node client.Object) bool {
+	if spec.NodeAddress == nil {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&spec.NodeAddress.Selector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(node.GetLabels()))
+}
+
+// providersForNode returns a list of requests for the Providers that take their public IP from the addresses of the Node,
+// so the records follow a changed address or a replaced node immediately
+func (r *ProviderReconciler) providersForNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	providers := &ddnsv1alpha1.ProviderList{}
+	if err := r.List(ctx, providers); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Providers")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, provider := range providers.Items {
+		if selectsNode(&provider.Spec, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&provider)})
+		}
+	}
+
+	return requests
+}
+
+// nodeEventFilter only maps the updates of a Node that may change the address of the Providers to them: its addresses,
+// its labels or its readiness. The frequent heartbeats in the status of the Node are filtered out
+func nodeEventFilter() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode := e.ObjectOld.(*corev1.Node)
+			node := e.ObjectNew.(*corev1.Node)
+
+			return !equality.Semantic.DeepEqual(oldNode.Status.Addresses, node.Status.Addresses) ||
+				!equality.Semantic.DeepEqual(oldNode.Labels, node.Labels) ||
+				nodeReady(oldNode) != nodeReady(node)
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+)
+
+// patchNotifierRefs reports in the NotifierRefs condition if every notifierRef of the provider points at an existing
+// notifier, so a typo does not silently produce no notifications. Notifiers are looked up in the given namespace, unless
+// the ref sets another one. If the provider has no notifierRefs, the condition is removed
+func (r *ProviderReconciler) patchNotifierRefs(ctx context.Context, namespace string, provider ddnsv1alpha1.ProviderObject) error {
+	refs := provider.GetProviderSpec().NotifierRefs
+	if len(refs) == 0 {
+		meta.RemoveStatusCondition(&provider.GetProviderStatus().Conditions, ddnsv1alpha1.ProviderConditionTypeNotifierRefs)
+		return nil
+	}
+
+	missing, err := r.missingNotifiers(ctx, namespace, refs)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		conditions.PatchConditionsSet(provider).Set(ddnsv1alpha1.ProviderConditionTypeNotifierRefs,
+			conditions.WithReasonAndMessage("NotifiersFound", fmt.Sprintf("All %d referenced notifiers exist", len(refs))),
+			conditions.True(),
+		)
+
+		return nil
+	}
+
+	conditions.PatchConditionsSet(provider).Set(ddnsv1alpha1.ProviderConditionTypeNotifierRefs,
+		conditions.WithReasonAndMessage("NotifiersNotFound", fmt.Sprintf("Not notified through %s", strings.Join(missing, ", "))),
+		conditions.False(),
+	)
+
+	return nil
+}
+
+// missingNotifiers describes the notifierRefs that do not point at a notifier the controller can notify through:
+// notifiers that do not exist, Notifiers in other namespaces unless AllowCrossNamespaceNotifierRefs is set, and
+// ClusterNotifiers if the controller is NamespaceScoped
+func (r *ProviderReconciler) missingNotifiers(ctx context.Context, namespace string, refs []ddnsv1alpha1.ResourceRef) ([]string, error) {
+	missing := []string{}
+
+	for _, ref := range refs {
+		var notifier client.Object = &ddnsv1alpha1.Notifier{}
+		key := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+
+		if ref.IsClusterNotifier() {
+			if r.NamespaceScoped {
+				missing = append(missing, fmt.Sprintf("ClusterNotifier %s (not watched by a namespace-scoped controller)", ref.Name))
+				continue
+			}
+
+			notifier = &ddnsv1alpha1.ClusterNotifier{}
+			key.Namespace = ""
+		} else if ref.Namespace != "" && ref.Namespace != namespace {
+			if !r.AllowCrossNamespaceNotifierRefs {
+				missing = append(missing, fmt.Sprintf("Notifier %s/%s (cross-namespace notifierRefs are not allowed)", ref.Namespace, ref.Name))
+				continue
+			}
+
+			key.Namespace = ref.Namespace
+		}
+
+		if err := r.Get(ctx, key, notifier); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("unable to get the notifier %s: %w", key, err)
+			}
+
+			if key.Namespace == "" {
+				missing = append(missing, fmt.Sprintf("ClusterNotifier %s (not found)", key.Name))
+			} else {
+				missing = append(missing, fmt.Sprintf("Notifier %s (not found)", key))
+			}
+		}
+	}
+
+	return missing, nil
+}
+
+// providersForNotifier returns a list of requests for the Providers with a notifierRef of the name of the notifier,
+// so the NotifierRefs condition is updated as soon as a referenced notifier is created or deleted
+func (r *ProviderReconciler) providersForNotifier(ctx context.Context, obj client.Object) []reconcile.Request {
+	providers := &ddnsv1alpha1.ProviderList{}
+	if err := r.List(ctx, providers, client.MatchingFields{notifierRefsNameIndex: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Providers")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, provider := range providers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&provider)})
+	}
+
+	return requests
+}
+
+// notifierEventFilter only maps the creation and deletion of a notifier to the providers, which is all the
+// NotifierRefs condition depends on
+func notifierEventFilter() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(event.UpdateEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// propagatingRecords returns the records that are set to the public IP and whose propagation can be verified.
+// Proxied records resolve to the proxy instead of the public IP, so they are left out
+func propagatingRecords(records []clients.DNSRecord, publicIp string) []clients.DNSRecord {
+	propagating := []clients.DNSRecord{}
+	for _, record := range records {
+		if record.Content == publicIp && !record.Proxied {
+			propagating = append(propagating, record)
+		}
+	}
+
+	return propagating
+}
+
+// patchPropagated verifies that the records resolve to their value at the resolvers of the PropagationCheck and
+// reports the result in the Propagated condition. The records are only resolved after they were updated or while they
+// did not propagate yet. It returns the records that did not propagate, e.g. "www.example.com (A) at 1.1.1.1 is 1.2.3.3".
+// If the Provider has no PropagationCheck, the condition is removed
+func (r *ProviderReconciler) patchPropagated(
+	ctx context.Context,
+	provider ddnsv1alpha1.ProviderObject,
+	updated bool,
+	records []clients.DNSRecord,
+) []string {
+	spec := provider.GetProviderSpec()
+
+	if spec.PropagationCheck == nil || spec.DryRun {
+		meta.RemoveStatusCondition(&provider.GetProviderStatus().Conditions, ddnsv1alpha1.ProviderConditionTypePropagated)
+		return nil
+	}
+
+	if !updated && meta.IsStatusConditionTrue(provider.GetProviderStatus().Conditions, ddnsv1alpha1.ProviderConditionTypePropagated) {
+		return nil
+	}
+
+	pending := r.pendingPropagation(ctx, spec.PropagationCheck.Resolvers, records)
+	if len(pending) > 0 {
+		conditions.PatchConditionsSet(provider).Set(ddnsv1alpha1.ProviderConditionTypePropagated,
+			conditions.WithReasonAndMessage("Pending", fmt.Sprintf("Waiting for %s", strings.Join(pending, ", "))),
+			conditions.False(),
+		)
+
+		return pending
+	}
+
+	conditions.PatchConditionsSet(provider).Set(ddnsv1alpha1.ProviderConditionTypePropagated,
+		conditions.WithReasonAndMessage("Propagated", fmt.Sprintf(
+			"All %d records resolve to their value at %s", len(records), strings.Join(spec.PropagationCheck.Resolvers, ", "),
+		)),
+		conditions.True(),
+	)
+
+	return nil
+}
+
+// pendingPropagation resolves every record at every resolver and describes the ones that do not resolve to their value
+func (r *ProviderReconciler) pendingPropagation(ctx context.Context, resolvers []string, records []clients.DNSRecord) []string {
+	pending := []string{}
+
+	for _, record := range records {
+		for _, resolver := range resolvers {
+			ips, err := r.Resolver(ctx, resolver, record.Name, record.Type)
+
+			switch {
+			case err != nil:
+				pending = append(pending, fmt.Sprintf("%s (%s) at %s: %s", record.Name, record.Type, resolver, err))
+			case len(ips) == 0:
+				pending = append(pending, fmt.Sprintf("%s (%s) at %s does not exist", record.Name, record.Type, resolver))
+			case !slices.Contains(ips, record.Content):
+				pending = append(pending, fmt.Sprintf("%s (%s) at %s is %s", record.Name, record.Type, resolver, strings.Join(ips, ", ")))
+			}
+		}
+	}
+
+	return pending
+}
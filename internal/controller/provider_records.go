@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// providerRecords caches the records of the providers by UID, as last read from the DNS provider by a successful sync, so
+// syncs whose public IP did not change do not have to list them again. The records are dropped once the spec, the Secret or
+// the ConfigMap of their provider change. A nil providerRecords caches nothing
+type providerRecords struct {
+	mu      sync.Mutex
+	records map[types.UID]cachedProviderRecords
+}
+
+// cachedProviderRecords are the records of a provider by record type, together with the key of the provider they were read for
+type cachedProviderRecords struct {
+	key    string
+	byType map[string]verifiedRecords
+}
+
+// verifiedRecords are the records of a single type and when they were read from the DNS provider
+type verifiedRecords struct {
+	records    []clients.DNSRecord
+	verifiedAt time.Time
+}
+
+// providerRecordsKey identifies what the records of the provider were read with: its spec and the Secret and ConfigMap it read
+func providerRecordsKey(provider ddnsv1alpha1.ProviderObject, observed ddnsv1alpha1.ObservedResources) string {
+	return fmt.Sprintf("%d/%s/%s", provider.GetGeneration(), observed.SecretHash, observed.ConfigMapHash)
+}
+
+// inSync returns true if every record has the IP and the proxied setting it should have
+func (v verifiedRecords) inSync(ip string) bool {
+	for _, record := range v.records {
+		if record.Content != ip || record.ProxiedDrift {
+			return false
+		}
+	}
+
+	return true
+}
+
+// recordContents returns the values of the records that exist, like GetIp of the client returns them
+func recordContents(records []clients.DNSRecord) []string {
+	contents := make([]string, 0, len(records))
+	for _, record := range records {
+		if record.Content != "" {
+			contents = append(contents, record.Content)
+		}
+	}
+
+	return contents
+}
+
+// get returns the records of the type cached for the provider, if they were read for the same key less than maxAge ago
+func (c *providerRecords) get(uid types.UID, key, recordType string, maxAge time.Duration) (verifiedRecords, bool) {
+	if c == nil || maxAge <= 0 {
+		return verifiedRecords{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.records[uid]
+	if !ok || cached.key != key {
+		return verifiedRecords{}, false
+	}
+
+	verified, ok := cached.byType[recordType]
+	if !ok || time.Since(verified.verifiedAt) >= maxAge {
+		return verifiedRecords{}, false
+	}
+
+	return verified, true
+}
+
+// set caches the records of the given types that were just read from the DNS provider. The records of the other types are
+// kept with the time they were read at, unless they were read for another key
+func (c *providerRecords) set(uid types.UID, key string, byType map[string][]clients.DNSRecord) {
+	if c == nil || len(byType) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.records == nil {
+		c.records = map[types.UID]cachedProviderRecords{}
+	}
+
+	cached, ok := c.records[uid]
+	if !ok || cached.key != key {
+		cached = cachedProviderRecords{key: key, byType: map[string]verifiedRecords{}}
+	}
+
+	now := time.Now()
+	for recordType, records := range byType {
+		cached.byType[recordType] = verifiedRecords{records: records, verifiedAt: now}
+	}
+
+	c.records[uid] = cached
+}
+
+// delete forgets the records of the provider, e.g. because it was deleted or its last sync failed
+func (c *providerRecords) delete(uid types.UID) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.records, uid)
+}
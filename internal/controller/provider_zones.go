@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// mergeZoneErrors adds the errors of the zones that failed in err to zoneErrs, so the sync can go on with the other zones.
+// It returns false if err is not limited to some zones, in which case the whole call failed
+func mergeZoneErrors(zoneErrs map[string]error, err error) bool {
+	errs, partial := clients.ZoneErrors(err)
+	if !partial {
+		return false
+	}
+
+	maps.Copy(zoneErrs, errs)
+
+	return true
+}
+
+// zonesError aggregates the errors of the failed zones out of all the zones, e.g. "1 of 3 zones failed: zone example.com: permission denied"
+func zonesError(zones []string, zoneErrs map[string]error) error {
+	failed := failedZones(zoneErrs)
+	messages := make([]string, 0, len(failed))
+	for _, zone := range failed {
+		messages = append(messages, fmt.Sprintf("zone %s: %s", zone, zoneErrs[zone]))
+	}
+
+	return fmt.Errorf("%d of %d zones failed: %s", len(failed), len(zones), strings.Join(messages, "; "))
+}
+
+// failedZones returns the names of the zones that failed, sorted
+func failedZones(zoneErrs map[string]error) []string {
+	failed := make([]string, 0, len(zoneErrs))
+	for zone := range zoneErrs {
+		failed = append(failed, zone)
+	}
+
+	slices.Sort(failed)
+
+	return failed
+}
+
+// providerZones returns the zones of the client, and the ones that failed in case the client does not list them
+func providerZones(providerClient clients.Client, zoneErrs map[string]error) []string {
+	zones := []string{}
+	if lister, ok := providerClient.(clients.ZoneLister); ok {
+		zones = append(zones, lister.Zones()...)
+	}
+
+	for _, zone := range failedZones(zoneErrs) {
+		if !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+	}
+
+	return zones
+}
+
+// patchZones sets the Ready condition of every zone, False with the error for the zones that failed
+func (p ProviderReconciler) patchZones(zones []string, zoneErrs map[string]error) func(provider ddnsv1alpha1.ProviderObject) bool {
+	return func(provider ddnsv1alpha1.ProviderObject) bool {
+		status := provider.GetProviderStatus()
+
+		var statuses []ddnsv1alpha1.ProviderZoneStatus
+		for _, zone := range zones {
+			zoneStatus := ddnsv1alpha1.ProviderZoneStatus{Name: zone}
+			for _, previous := range status.Zones {
+				if previous.Name == zone {
+					zoneStatus = *previous.DeepCopy()
+					break
+				}
+			}
+
+			condition := metav1.Condition{
+				Type:    ddnsv1alpha1.ProviderConditionTypeReady,
+				Status:  metav1.ConditionTrue,
+				Reason:  "ZoneSynced",
+				Message: "The records of the zone were synced",
+			}
+			if err, failed := zoneErrs[zone]; failed {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = "ZoneFailed"
+				condition.Message = err.Error()
+			}
+
+			meta.SetStatusCondition(&zoneStatus.Conditions, condition)
+			statuses = append(statuses, zoneStatus)
+		}
+
+		if equality.Semantic.DeepEqual(status.Zones, statuses) {
+			return false
+		}
+
+		status.Zones = statuses
+
+		return true
+	}
+}
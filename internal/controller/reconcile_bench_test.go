@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// The benchmarks cover the work that is done on every reconciliation of a Provider, apart from the calls to the API server
+// and the DNS provider. Run them with `go test ./internal/controller -run '^$' -bench . -benchmem` and compare the allocs/op
+// before and after a change
+
+const benchIp = "1.2.3.4"
+
+// benchRecords returns n records at the provider, every fourth of them with an outdated IP
+func benchRecords(n int) []clients.DNSRecord {
+	records := make([]clients.DNSRecord, 0, n)
+	for i := range n {
+		content := benchIp
+		if i%4 == 0 {
+			content = "1.2.3.3"
+		}
+
+		records = append(records, clients.DNSRecord{
+			Zone:    "example.com",
+			Name:    fmt.Sprintf("host-%d.example.com", i),
+			Type:    clients.RecordTypeA,
+			Content: content,
+		})
+	}
+
+	return records
+}
+
+func BenchmarkObservedResources(b *testing.B) {
+	secret := &corev1.Secret{Data: map[string][]byte{"apiToken": []byte("token")}}
+	configMap := &corev1.ConfigMap{Data: map[string]string{"config": `{"cloudflare":{"zones":[{"name":"example.com","records":[{"name":"www.example.com"}]}]}}`}}
+
+	b.ReportAllocs()
+	for range b.N {
+		observedResources(secret, configMap)
+	}
+}
+
+func BenchmarkRecordStatuses(b *testing.B) {
+	records := benchRecords(20)
+
+	b.ReportAllocs()
+	for range b.N {
+		syncedOptions(recordStatuses(records, benchIp))
+	}
+}
+
+func BenchmarkRecordsMessageInSync(b *testing.B) {
+	statuses := recordStatuses(benchRecords(20), "1.2.3.3")
+	for i := range statuses {
+		statuses[i].Synced = true
+		statuses[i].DesiredValue = benchIp
+	}
+
+	b.ReportAllocs()
+	for range b.N {
+		recordsMessage(statuses)
+	}
+}
+
+func BenchmarkDriftedRecords(b *testing.B) {
+	records := benchRecords(20)
+	previous := recordStatuses(records, benchIp)
+
+	b.ReportAllocs()
+	for range b.N {
+		driftedRecords(previous, records, "1.2.3.5")
+	}
+}
+
+func BenchmarkDesyncedChanges(b *testing.B) {
+	records := benchRecords(20)
+
+	b.ReportAllocs()
+	for range b.N {
+		desyncedChanges(clients.RecordTypeA, "1.2.3.3", benchIp, records)
+	}
+}
+
+func BenchmarkInlineConfigMap(b *testing.B) {
+	spec := &ddnsv1alpha1.ProviderSpec{Config: &ddnsv1alpha1.ProviderConfig{}}
+	for i := range 20 {
+		spec.Config.Records = append(spec.Config.Records, ddnsv1alpha1.ManagedRecord{
+			Zone: "example.com",
+			Name: fmt.Sprintf("host-%d.example.com", i),
+		})
+	}
+
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := inlineConfigMap(spec); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
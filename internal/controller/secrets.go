@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+)
+
+// providerUsesResource returns true if the Provider reads the given Secret or ConfigMap, including the ones of its backends
+// The namespace of the resource is not checked
+func providerUsesResource(spec *ddnsv1alpha1.ProviderSpec, obj client.Object) bool {
+	switch resourceKind(obj) {
+	case "Secret":
+		if spec.GetSecretRef().Name == obj.GetName() {
+			return true
+		}
+
+		for _, backend := range spec.Backends {
+			if backend.SecretRef.Name == obj.GetName() {
+				return true
+			}
+		}
+	case "ConfigMap":
+		if spec.Config == nil && spec.ConfigMap == obj.GetName() {
+			return true
+		}
+
+		for _, backend := range spec.Backends {
+			if backend.ConfigRef != nil && backend.ConfigRef.Name == obj.GetName() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// notifierUsesResource returns true if the Notifier reads the given Secret or ConfigMap
+// The namespace of the resource is not checked
+func notifierUsesResource(spec *ddnsv1alpha1.NotifierSpec, obj client.Object) bool {
+	switch resourceKind(obj) {
+	case "Secret":
+		return spec.GetSecretRef().Name == obj.GetName()
+	case "ConfigMap":
+		return spec.ConfigMap == obj.GetName()
+	}
+
+	return false
+}
+
+// resourceKind returns the kind of a Secret or ConfigMap. They are watched as metadata only, so the events carry a
+// PartialObjectMetadata with the kind set instead of the typed object
+func resourceKind(obj client.Object) string {
+	switch obj.(type) {
+	case *corev1.Secret:
+		return "Secret"
+	case *corev1.ConfigMap:
+		return "ConfigMap"
+	}
+
+	return obj.GetObjectKind().GroupVersionKind().Kind
+}
+
+// mapSecretKeys returns a copy of the secret where the values of the keys mapped in the SecretRef
+// are available under the key names that the clients and notifiers expect
+func mapSecretKeys(secret *corev1.Secret, secretRef ddnsv1alpha1.SecretRef) *corev1.Secret {
+	if len(secretRef.Keys) == 0 {
+		return secret
+	}
+
+	mapped := secret.DeepCopy()
+	if mapped.Data == nil {
+		mapped.Data = make(map[string][]byte)
+	}
+
+	for expected := range secretRef.Keys {
+		if value, ok := secret.Data[secretRef.KeyFor(expected)]; ok {
+			mapped.Data[expected] = value
+		}
+	}
+
+	return mapped
+}
+
+// observedResources hashes the data of the given Secret and ConfigMap, so changes to them can be detected
+// without storing the data in the status. A nil ConfigMap (e.g. an inline config) is not hashed
+func observedResources(secret *corev1.Secret, configMap *corev1.ConfigMap) ddnsv1alpha1.ObservedResources {
+	observed := ddnsv1alpha1.ObservedResources{
+		SecretHash: dataHash(secret.Data),
+	}
+
+	if configMap != nil {
+		observed.ConfigMapHash = dataHash(map[string]any{"data": configMap.Data, "binaryData": configMap.BinaryData})
+	}
+
+	return observed
+}
+
+// dataHash returns the sha256 of the JSON encoding of data. Maps are encoded with sorted keys, so the hash is stable
+func dataHash(data any) string {
+	encoded, _ := json.Marshal(data)
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// resourcesChanged returns true if the Secret or ConfigMap changed since the last successful reconciliation.
+// Resources that were never observed did not change
+func resourcesChanged(last *ddnsv1alpha1.ObservedResources, observed ddnsv1alpha1.ObservedResources) bool {
+	return last != nil && *last != observed
+}
+
+// resourcesChangedOptions returns the options for the ResourcesChanged condition
+// It is True if the observed resources differ from the ones used for the last successful reconciliation
+func resourcesChangedOptions(last *ddnsv1alpha1.ObservedResources, observed ddnsv1alpha1.ObservedResources) []conditions.ConditionOption {
+	changed := []string{}
+
+	if last.SecretHash != observed.SecretHash {
+		changed = append(changed, "Secret")
+	}
+
+	if last.ConfigMapHash != observed.ConfigMapHash {
+		changed = append(changed, "ConfigMap")
+	}
+
+	if len(changed) == 0 {
+		return []conditions.ConditionOption{
+			conditions.WithReasonAndMessage("Unchanged", "Secret and ConfigMap are the ones used for the last successful reconciliation"),
+			conditions.False(),
+		}
+	}
+
+	return []conditions.ConditionOption{
+		conditions.WithReasonAndMessage("Changed", fmt.Sprintf("%s changed since the last successful reconciliation", strings.Join(changed, " and "))),
+		conditions.True(),
+	}
+}
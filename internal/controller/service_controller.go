@@ -0,0 +1,201 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// recordTypeCNAME is the type of the records of load balancers that only have a hostname, e.g. on AWS
+const recordTypeCNAME = "CNAME"
+
+// ServiceReconciler keeps a DNSRecord for every host annotated on the Services of type LoadBalancer, pointed at the
+// address of the load balancer instead of the public IP
+type ServiceReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder reports misconfigured Services as events. No events are recorded if it is nil
+	Recorder record.EventRecorder
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+}
+
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=dnsrecords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile creates or updates the DNSRecords of the hosts of the Service and deletes the ones it no longer needs
+// The DNSRecords are owned by the Service, so they are garbage collected with it
+func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	service := &corev1.Service{}
+	if err := r.Get(ctx, req.NamespacedName, service); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	desired, err := r.desiredRecords(ctx, service)
+	if err != nil {
+		// The records are left as they are until the Service is fixed, instead of removing them from the provider
+		log.Error(err, "Service is misconfigured, leaving its DNSRecords as they are")
+		r.event(service, "InvalidAnnotations", err.Error())
+
+		return ctrl.Result{}, nil
+	}
+
+	if desired == nil && serviceManaged(service) {
+		// The load balancer lost its address or was not assigned one yet. The Service is reconciled again once it is
+		log.Info("Load balancer has no address yet, leaving the DNSRecords of the Service as they are")
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, syncOwnedRecords(ctx, r.Client, r.Scheme, service, ddnsv1alpha1.ServiceLabel, desired)
+}
+
+// =================================================== PRIVATE FUNCTIONS ===================================================
+
+// serviceManaged returns true if the Service is of type LoadBalancer and names a Provider in its annotations
+func serviceManaged(service *corev1.Service) bool {
+	return service.Spec.Type == corev1.ServiceTypeLoadBalancer && service.GetAnnotations()[ddnsv1alpha1.IngressProviderAnnotation] != ""
+}
+
+// desiredRecords returns the DNSRecords the Service needs: one for every annotated host and every IP family of its load
+// balancer. Load balancers that only have a hostname get a CNAME record instead.
+// It returns nil if the Service is not managed or its load balancer has no address
+func (r *ServiceReconciler) desiredRecords(ctx context.Context, service *corev1.Service) ([]ddnsv1alpha1.DNSRecord, error) {
+	if !serviceManaged(service) {
+		return nil, nil
+	}
+
+	annotations := service.GetAnnotations()
+	ref, err := annotatedProviderRef(annotations[ddnsv1alpha1.IngressProviderAnnotation])
+	if err != nil {
+		return nil, err
+	}
+
+	zone, err := annotatedZone(annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	values := loadBalancerValues(service.Status.LoadBalancer.Ingress)
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	records := []ddnsv1alpha1.DNSRecord{}
+	for _, host := range serviceHosts(service) {
+		if !inZone(host, zone) {
+			log.FromContext(ctx).Info("Host is not in the zone of the Service, skipping it", "host", host, "zone", zone)
+			continue
+		}
+
+		for _, recordType := range []string{clients.RecordTypeA, clients.RecordTypeAAAA, recordTypeCNAME} {
+			value, ok := values[recordType]
+			if !ok {
+				continue
+			}
+
+			records = append(records, ownedRecord(service, ddnsv1alpha1.ServiceLabel, ddnsv1alpha1.DNSRecordSpec{
+				ProviderRef: ref,
+				Zone:        zone,
+				Name:        host,
+				Type:        recordType,
+				Value:       value,
+				TTL:         1,
+				Proxied:     annotations[ddnsv1alpha1.IngressProxiedAnnotation] == "true",
+			}))
+		}
+	}
+
+	return records, nil
+}
+
+// loadBalancerValues returns the values of the records of the load balancer by type: its first IPv4 and IPv6 address, or
+// its first hostname if it has no IP, as a CNAME cannot exist next to other records of the same name
+func loadBalancerValues(ingresses []corev1.LoadBalancerIngress) map[string]string {
+	values := map[string]string{}
+	hostname := ""
+
+	for _, ingress := range ingresses {
+		if ip := net.ParseIP(ingress.IP); ip != nil {
+			recordType := clients.RecordTypeAAAA
+			if ip.To4() != nil {
+				recordType = clients.RecordTypeA
+			}
+
+			if _, ok := values[recordType]; !ok {
+				values[recordType] = ingress.IP
+			}
+		}
+
+		if hostname == "" {
+			hostname = ingress.Hostname
+		}
+	}
+
+	if len(values) == 0 && hostname != "" {
+		values[recordTypeCNAME] = hostname
+	}
+
+	return values
+}
+
+// serviceHosts returns the distinct hosts of the ServiceHostnamesAnnotation, in the order they are listed
+func serviceHosts(service *corev1.Service) []string {
+	hosts := []string{}
+	for _, host := range strings.Split(service.GetAnnotations()[ddnsv1alpha1.ServiceHostnamesAnnotation], ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// event records an event on the Service, if there is a Recorder
+func (r *ServiceReconciler) event(service *corev1.Service, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(service, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Service{}).
+		WithOptions(r.ControllerOptions.options()).
+		Owns(&ddnsv1alpha1.DNSRecord{}).
+		Complete(r)
+}
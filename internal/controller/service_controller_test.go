@@ -0,0 +1,173 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+var _ = Describe("Service Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+		var controllerReconciler *ServiceReconciler
+
+		serviceNamespacedName := types.NamespacedName{
+			Name:      "test-service",
+			Namespace: "default",
+		}
+
+		// serviceRecords returns the DNSRecords created for the Service
+		serviceRecords := func() []ddnsv1alpha1.DNSRecord {
+			records := &ddnsv1alpha1.DNSRecordList{}
+			Expect(k8sClient.List(ctx, records,
+				client.InNamespace(serviceNamespacedName.Namespace),
+				client.MatchingLabels{ddnsv1alpha1.ServiceLabel: serviceNamespacedName.Name},
+			)).To(Succeed())
+
+			return records.Items
+		}
+
+		// setLoadBalancer sets the addresses of the load balancer of the Service
+		setLoadBalancer := func(ingresses ...corev1.LoadBalancerIngress) {
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, serviceNamespacedName, service)).To(Succeed())
+			service.Status.LoadBalancer.Ingress = ingresses
+			Expect(k8sClient.Status().Update(ctx, service)).To(Succeed())
+		}
+
+		reconcileService := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: serviceNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			By("creating the annotated Service")
+			err := k8sClient.Get(ctx, serviceNamespacedName, &corev1.Service{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      serviceNamespacedName.Name,
+						Namespace: serviceNamespacedName.Namespace,
+						Annotations: map[string]string{
+							ddnsv1alpha1.IngressProviderAnnotation:  "ClusterProvider/test-service-provider",
+							ddnsv1alpha1.IngressZoneAnnotation:      "example.com",
+							ddnsv1alpha1.ServiceHostnamesAnnotation: "app.example.com, api.example.com,other.org",
+						},
+					},
+					Spec: corev1.ServiceSpec{
+						Type:  corev1.ServiceTypeLoadBalancer,
+						Ports: []corev1.ServicePort{{Port: 443}},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			controllerReconciler = &ServiceReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the Service and its DNSRecords")
+			service := &corev1.Service{}
+			if err := k8sClient.Get(ctx, serviceNamespacedName, service); err == nil {
+				Expect(k8sClient.Delete(ctx, service)).To(Succeed())
+			}
+
+			// There is no garbage collector in the test environment to delete the owned records
+			for _, record := range serviceRecords() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &record))).To(Succeed())
+			}
+		})
+
+		It("should point the hosts in the zone at the addresses of the load balancer", func() {
+			setLoadBalancer(
+				corev1.LoadBalancerIngress{IP: "192.168.1.240"},
+				corev1.LoadBalancerIngress{IP: "192.168.1.241"},
+				corev1.LoadBalancerIngress{IP: "fd00::240"},
+			)
+			reconcileService()
+
+			records := serviceRecords()
+			Expect(records).To(HaveLen(4))
+
+			values := map[string]string{}
+			for _, record := range records {
+				Expect(record.Spec.ProviderRef).To(Equal(ddnsv1alpha1.ProviderRef{
+					Kind: ddnsv1alpha1.ClusterProviderKind,
+					Name: "test-service-provider",
+				}))
+				Expect(record.Spec.Name).To(BeElementOf("app.example.com", "api.example.com"))
+
+				values[record.Spec.Type] = record.Spec.Value
+			}
+
+			Expect(values).To(Equal(map[string]string{"A": "192.168.1.240", "AAAA": "fd00::240"}))
+		})
+
+		It("should create CNAME records for load balancers with only a hostname", func() {
+			setLoadBalancer(corev1.LoadBalancerIngress{Hostname: "lb.cloud.example.net"})
+			reconcileService()
+
+			records := serviceRecords()
+			Expect(records).To(HaveLen(2))
+			for _, record := range records {
+				Expect(record.Spec.Type).To(Equal("CNAME"))
+				Expect(record.Spec.Value).To(Equal("lb.cloud.example.net"))
+			}
+		})
+
+		It("should leave the DNSRecords as they are while the load balancer has no address", func() {
+			setLoadBalancer(corev1.LoadBalancerIngress{IP: "192.168.1.240"})
+			reconcileService()
+			Expect(serviceRecords()).To(HaveLen(2))
+
+			setLoadBalancer()
+			reconcileService()
+			Expect(serviceRecords()).To(HaveLen(2))
+		})
+
+		It("should delete the DNSRecords once the annotation is removed", func() {
+			setLoadBalancer(corev1.LoadBalancerIngress{IP: "192.168.1.240"})
+			reconcileService()
+			Expect(serviceRecords()).To(HaveLen(2))
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, serviceNamespacedName, service)).To(Succeed())
+			delete(service.Annotations, ddnsv1alpha1.IngressProviderAnnotation)
+			Expect(k8sClient.Update(ctx, service)).To(Succeed())
+
+			reconcileService()
+			Expect(serviceRecords()).To(BeEmpty())
+		})
+	})
+})
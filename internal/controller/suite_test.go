@@ -17,6 +17,7 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"runtime"
@@ -25,8 +26,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -53,18 +56,7 @@ var _ = BeforeSuite(func() {
 	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
 
 	By("bootstrapping test environment")
-	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
-		ErrorIfCRDPathMissing: true,
-
-		// The BinaryAssetsDirectory is only required if you want to run the tests directly
-		// without call the makefile target test. If not informed it will look for the
-		// default path defined in controller-runtime which is /usr/local/kubebuilder/.
-		// Note that you must have the required binaries setup under the bin directory to perform
-		// the tests directly. When we run make test it will be setup and used automatically.
-		BinaryAssetsDirectory: filepath.Join("..", "..", "bin", "k8s",
-			fmt.Sprintf("1.30.0-%s-%s", runtime.GOOS, runtime.GOARCH)),
-	}
+	testEnv = newTestEnvironment()
 
 	var err error
 	// cfg is defined in this file globally.
@@ -77,14 +69,76 @@ var _ = BeforeSuite(func() {
 
 	// +kubebuilder:scaffold:scheme
 
-	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	directClient, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
 	Expect(err).NotTo(HaveOccurred())
-	Expect(k8sClient).NotTo(BeNil())
+	Expect(directClient).NotTo(BeNil())
+
+	indexedClient := &IndexedClient{Client: directClient}
+	Expect(SetupIndexes(context.Background(), indexedClient, false)).To(Succeed())
+	k8sClient = indexedClient
 
 })
 
+// deleteProvider deletes the Provider and removes its finalizer,
+// as there is no running controller that would do it
+func deleteProvider(ctx context.Context, provider *ddnsv1alpha1.Provider) {
+	Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, provider))).To(Succeed())
+
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(provider), provider); err != nil {
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		return
+	}
+
+	patch := client.MergeFrom(provider.DeepCopy())
+	provider.SetFinalizers(nil)
+	Expect(client.IgnoreNotFound(k8sClient.Patch(ctx, provider, patch))).To(Succeed())
+}
+
+// deleteNotifier deletes the notifier and removes its finalizer, as there is no controller running in the tests to do it
+func deleteNotifier(ctx context.Context, notifier ddnsv1alpha1.NotifierObject) {
+	Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, notifier))).To(Succeed())
+
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(notifier), notifier); err != nil {
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		return
+	}
+
+	patch := client.MergeFrom(notifier.DeepCopyObject().(client.Object))
+	notifier.SetFinalizers(nil)
+	Expect(client.IgnoreNotFound(k8sClient.Patch(ctx, notifier, patch))).To(Succeed())
+}
+
+// receivedEvents drains the events recorded so far by the recorder
+func receivedEvents(recorder *record.FakeRecorder) []string {
+	events := []string{}
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
 var _ = AfterSuite(func() {
 	By("tearing down the test environment")
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
+
+// newTestEnvironment returns the envtest environment with the CRDs of the controller, for the suite and the benchmarks
+func newTestEnvironment() *envtest.Environment {
+	return &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+
+		// The BinaryAssetsDirectory is only required if you want to run the tests directly
+		// without call the makefile target test. If not informed it will look for the
+		// default path defined in controller-runtime which is /usr/local/kubebuilder/.
+		// Note that you must have the required binaries setup under the bin directory to perform
+		// the tests directly. When we run make test it will be setup and used automatically.
+		BinaryAssetsDirectory: filepath.Join("..", "..", "bin", "k8s",
+			fmt.Sprintf("1.30.0-%s-%s", runtime.GOOS, runtime.GOARCH)),
+	}
+}
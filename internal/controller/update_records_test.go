@@ -0,0 +1,49 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+var _ = Describe("Updating records", func() {
+	records := []clients.DNSRecord{
+		{Zone: "example.com", Name: "example.com", Type: clients.RecordTypeA, Content: "127.0.0.1"},
+		{Zone: "example.com", Name: "www.example.com", Type: clients.RecordTypeA, Content: "127.0.0.2"},
+	}
+
+	It("should only update the records that are out of sync", func() {
+		var updated []clients.DNSRecord
+		client := MockRecordsUpdater{Records: records, UpdateInterceptor: func(r []clients.DNSRecord) { updated = r }}
+
+		Expect(updateRecords(client, "127.0.0.1", clients.RecordTypeA, client.Records)).To(Succeed())
+		Expect(updated).To(Equal(records[1:]))
+	})
+
+	It("should update the records whose proxied setting drifted", func() {
+		var updated []clients.DNSRecord
+		drifted := []clients.DNSRecord{records[0], {Zone: "example.com", Name: "vpn.example.com", Type: clients.RecordTypeA, Content: "127.0.0.1", ProxiedDrift: true}}
+		client := MockRecordsUpdater{Records: drifted, UpdateInterceptor: func(r []clients.DNSRecord) { updated = r }}
+
+		Expect(updateRecords(client, "127.0.0.1", clients.RecordTypeA, client.Records)).To(Succeed())
+		Expect(updated).To(Equal(drifted[1:]))
+		Expect(desyncedChanges(clients.RecordTypeA, "127.0.0.1", "127.0.0.1", drifted)).To(Equal([]string{"proxied setting of vpn.example.com (A)"}))
+	})
+
+	It("should not update anything if all the records are in sync", func() {
+		called := false
+		client := MockRecordsUpdater{Records: records[:1], UpdateInterceptor: func([]clients.DNSRecord) { called = true }}
+
+		Expect(updateRecords(client, "127.0.0.1", clients.RecordTypeA, client.Records)).To(Succeed())
+		Expect(called).To(BeFalse())
+	})
+
+	It("should set the IP of all the records if the client cannot update single records", func() {
+		var setIp string
+		client := MockClient{SetIPInterceptor: func(ip string) { setIp = ip }}
+
+		Expect(updateRecords(client, "127.0.0.1", clients.RecordTypeA, records)).To(Succeed())
+		Expect(setIp).To(Equal("127.0.0.1"))
+	})
+})
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+// updateWindowsOpen returns true if any of the windows is open at the given time, or if there are no windows at all.
+// If they are all closed, it also returns when the next one opens, which is zero if none of them ever opens again
+func updateWindowsOpen(windows []ddnsv1alpha1.UpdateWindow, now time.Time) (bool, time.Time, error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var next time.Time
+
+	for _, window := range windows {
+		schedule, err := cron.ParseStandard(window.Schedule)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid schedule %q of update window: %w", window.Schedule, err)
+		}
+
+		location, err := time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid time zone %q of update window: %w", window.TimeZone, err)
+		}
+
+		local := now.In(location)
+
+		// The window is open if it opened at most Duration ago
+		if opened := schedule.Next(local.Add(-window.Duration.Duration)); !opened.IsZero() && !opened.After(local) {
+			return true, time.Time{}, nil
+		}
+
+		if opens := schedule.Next(local); !opens.IsZero() && (next.IsZero() || opens.Before(next)) {
+			next = opens
+		}
+	}
+
+	return false, next, nil
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+)
+
+var _ = Describe("Update windows", func() {
+	// Saturdays between 02:00 and 04:00 in Sofia, which is UTC+3 in the summer
+	windows := []ddnsv1alpha1.UpdateWindow{{
+		Schedule: "0 2 * * 6",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone: "Europe/Sofia",
+	}}
+
+	It("should be open without any windows", func() {
+		open, next, err := updateWindowsOpen(nil, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeTrue())
+		Expect(next.IsZero()).To(BeTrue())
+	})
+
+	It("should be open while a window is open in its time zone", func() {
+		open, _, err := updateWindowsOpen(windows, time.Date(2026, time.August, 1, 0, 30, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeTrue())
+	})
+
+	It("should return when the next window opens if all of them are closed", func() {
+		open, next, err := updateWindowsOpen(windows, time.Date(2026, time.August, 1, 1, 0, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeFalse())
+		Expect(next.Equal(time.Date(2026, time.August, 7, 23, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("should fail with an invalid schedule or time zone", func() {
+		_, _, err := updateWindowsOpen([]ddnsv1alpha1.UpdateWindow{{Schedule: "every day"}}, time.Now())
+		Expect(err).To(HaveOccurred())
+
+		_, _, err = updateWindowsOpen([]ddnsv1alpha1.UpdateWindow{{Schedule: "0 2 * * *", TimeZone: "Nowhere/Nothing"}}, time.Now())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,331 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1/conditions"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+// ZoneReconciler reconciles a Zone object
+type ZoneReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	ClientFactory ClientFactory
+	// ClusterResourceNamespace is the namespace the secrets of ClusterProviders are read from
+	ClusterResourceNamespace string
+	// NamespaceScoped leaves out the ClusterProviders, which cannot be read without cluster-wide RBAC
+	NamespaceScoped bool
+	// ControllerOptions configures the concurrency and rate limiting of the controller
+	ControllerOptions ControllerOptions
+}
+
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=zones,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=zones/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=zones/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=providers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ddns.stefangenov.site,resources=clusterproviders,verbs=get;list;watch
+
+// Reconcile will keep the records of the Zone in sync with the public IP detected by the referenced Provider
+func (r *ZoneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		err            error
+		provider       ddnsv1alpha1.ProviderObject
+		providerClient clients.Client
+		providerIps    []string
+	)
+
+	zone := &ddnsv1alpha1.Zone{}
+	if err = r.Get(ctx, req.NamespacedName, zone); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	zone.Conditions().FillConditions()
+
+	if provider, err = r.fetchProvider(ctx, req, zone); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	spec := provider.GetProviderSpec()
+
+	if spec.Suspend {
+		log.FromContext(ctx).Info("Provider is suspended, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	requeue := ctrl.Result{
+		Requeue:      true,
+		RequeueAfter: spec.GetRetryInterval(),
+	}
+
+	if providerClient, err = r.fetchClient(ctx, zone, provider); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, family := range ipFamilies(spec, nil, nil) {
+		publicIp := *family.publicIp(provider.GetProviderStatus())
+		if publicIp == "" {
+			log.FromContext(ctx).Info("Provider has not detected a public IP yet, waiting", "type", family.recordType)
+
+			return requeue, r.patchSynced(ctx, zone, "WaitingForPublicIP",
+				fmt.Sprintf("Provider %s has not detected a public %s IP yet", provider.GetName(), family.recordType), false)
+		}
+
+		if providerIps, err = providerClient.GetIp(family.recordType); err != nil {
+			_ = r.patchSynced(ctx, zone, "RecordsFetched", err.Error(), false)
+			return ctrl.Result{}, err
+		}
+
+		providerIp := strings.Join(uniqueIps(providerIps), ", ")
+
+		proxiedDrift := false
+		if enforcesProxied(zone.Spec.Records) {
+			records, err := providerClient.GetRecords(family.recordType)
+			if err != nil {
+				_ = r.patchSynced(ctx, zone, "RecordsFetched", err.Error(), false)
+				return ctrl.Result{}, err
+			}
+
+			proxiedDrift = len(proxiedDrifted(records)) > 0
+		}
+
+		if (publicIp != providerIp || proxiedDrift) && !spec.DryRun {
+			log.FromContext(ctx).Info("Records desynced, updating zone records", "type", family.recordType, "proxiedDrift", proxiedDrift)
+
+			if err = providerClient.SetIp(publicIp, family.recordType); err != nil {
+				_ = r.patchSynced(ctx, zone, "RecordsUpdated", err.Error(), false)
+				return ctrl.Result{}, err
+			}
+
+			providerIp = publicIp
+		}
+
+		if err = r.patchStatus(ctx, zone, r.patchProviderIp(family.recordType, providerIp)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err = r.patchSynced(ctx, zone, "RecordsSynced", fmt.Sprintf("Records of zone %s are in sync", zone.Spec.Name), true); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.patchStatus(ctx, zone, r.patchLastSyncTime()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.patchStatus(ctx, zone, r.patchObservedGeneration()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return requeue, nil
+}
+
+// =================================================== PRIVATE FUNCTIONS ===================================================
+
+// enforcesProxied returns true if any of the records enforces its proxied setting, which needs the records to be checked
+func enforcesProxied(records []ddnsv1alpha1.RecordConfig) bool {
+	for _, record := range records {
+		if record.EnforceProxied {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchProvider will fetch the referenced Provider or ClusterProvider and set the status of the Zone
+func (r *ZoneReconciler) fetchProvider(
+	ctx context.Context,
+	req ctrl.Request,
+	zone *ddnsv1alpha1.Zone,
+) (ddnsv1alpha1.ProviderObject, error) {
+	condOptions := []conditions.ConditionOption{}
+
+	provider, err := getProvider(ctx, r.Client, r.NamespaceScoped, zone.Spec.ProviderRef, req.Namespace)
+	if err != nil {
+		condOptions = append(condOptions,
+			conditions.WithReasonAndMessage("ProviderFound", err.Error()),
+			conditions.False(),
+		)
+	} else {
+		condOptions = append(condOptions,
+			conditions.WithReasonAndMessage("ProviderFound", fmt.Sprintf("Provider %s found", provider.GetName())),
+			conditions.True(),
+		)
+	}
+
+	_ = conditions.PatchConditions(ctx, r.Client, zone, ddnsv1alpha1.ZoneConditionTypeProvider, condOptions...)
+
+	return provider, err
+}
+
+// fetchClient builds a client with the credentials of the Provider that only manages the records of the Zone
+func (r *ZoneReconciler) fetchClient(
+	ctx context.Context,
+	zone *ddnsv1alpha1.Zone,
+	provider ddnsv1alpha1.ProviderObject,
+) (clients.Client, error) {
+	var providerClient clients.Client
+
+	condOptions := []conditions.ConditionOption{}
+
+	spec := provider.GetProviderSpec()
+
+	secret, err := fetchProviderSecret(ctx, r.Client, resourceNamespace(provider, r.ClusterResourceNamespace), spec)
+	if err == nil {
+		configMap, configErr := zonesConfigMap(spec.Name, []ddnsv1alpha1.ZoneConfig{
+			{Name: zone.Spec.Name, Records: zone.Spec.Records},
+		})
+		if err = configErr; err == nil {
+			providerClient, err = r.ClientFactory(ctx, spec.Name, secret, configMap, log.FromContext(ctx))
+		}
+	}
+
+	if err != nil {
+		condOptions = append(condOptions,
+			conditions.WithReasonAndMessage("ClientCreated", err.Error()),
+			conditions.False(),
+		)
+	} else {
+		condOptions = append(condOptions,
+			conditions.WithReasonAndMessage("ClientCreated", "Client created successfully"),
+			conditions.True(),
+		)
+	}
+
+	_ = conditions.PatchConditions(ctx, r.Client, zone, ddnsv1alpha1.ZoneConditionTypeClient, condOptions...)
+
+	return providerClient, err
+}
+
+// patchSynced sets the Synced condition of the Zone
+func (r *ZoneReconciler) patchSynced(
+	ctx context.Context,
+	zone *ddnsv1alpha1.Zone,
+	reason, message string,
+	synced bool,
+) error {
+	status := conditions.False()
+	if synced {
+		status = conditions.True()
+	}
+
+	return conditions.PatchConditions(ctx, r.Client, zone, ddnsv1alpha1.ZoneConditionTypeSynced,
+		conditions.WithReasonAndMessage(reason, message),
+		status,
+	)
+}
+
+func (r *ZoneReconciler) patchStatus(
+	ctx context.Context,
+	zone *ddnsv1alpha1.Zone,
+	apply func(*ddnsv1alpha1.Zone) bool,
+) error {
+	if apply(zone) {
+		return conditions.ApplyStatus(ctx, r.Client, zone)
+	}
+
+	return nil
+}
+
+// zonesForProvider maps a Provider or ClusterProvider to the Zones that reference it,
+// so the records are updated as soon as the public IP changes
+func (r *ZoneReconciler) zonesForProvider(ctx context.Context, obj client.Object) []reconcile.Request {
+	zones := &ddnsv1alpha1.ZoneList{}
+	if err := r.List(ctx, zones, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Zones")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, zone := range zones.Items {
+		if refersTo(zone.Spec.ProviderRef, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&zone)})
+		}
+	}
+
+	return requests
+}
+
+// =================================================== SETUP FUNCTIONS ===================================================
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ZoneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&ddnsv1alpha1.Zone{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(r.ControllerOptions.options()).
+		Watches(&ddnsv1alpha1.Provider{}, handler.EnqueueRequestsFromMapFunc(r.zonesForProvider))
+
+	if !r.NamespaceScoped {
+		controllerBuilder = controllerBuilder.Watches(&ddnsv1alpha1.ClusterProvider{}, handler.EnqueueRequestsFromMapFunc(r.zonesForProvider))
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+// =================================================== PATCH FUNCTIONS ===================================================
+
+func (r *ZoneReconciler) patchProviderIp(recordType, providerIp string) func(zone *ddnsv1alpha1.Zone) bool {
+	return func(zone *ddnsv1alpha1.Zone) bool {
+		current := &zone.Status.ProviderIP
+		if recordType == clients.RecordTypeAAAA {
+			current = &zone.Status.ProviderIPv6
+		}
+
+		if *current == providerIp {
+			return false
+		}
+
+		*current = providerIp
+
+		return true
+	}
+}
+
+func (r *ZoneReconciler) patchLastSyncTime() func(zone *ddnsv1alpha1.Zone) bool {
+	return func(zone *ddnsv1alpha1.Zone) bool {
+		now := metav1.Now()
+		zone.Status.LastSyncTime = &now
+
+		return true
+	}
+}
+
+func (r *ZoneReconciler) patchObservedGeneration() func(zone *ddnsv1alpha1.Zone) bool {
+	return func(zone *ddnsv1alpha1.Zone) bool {
+		if zone.Status.ObservedGeneration == zone.GetGeneration() {
+			return false
+		}
+		zone.Status.ObservedGeneration = zone.GetGeneration()
+		return true
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+)
+
+var _ = Describe("Zone Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+		publicIp := "127.0.0.1"
+		var (
+			controllerReconciler *ZoneReconciler
+			providerClient       MockClient
+			usedConfig           string
+		)
+
+		zoneNamespacedName := types.NamespacedName{
+			Name:      "test-zone",
+			Namespace: "default",
+		}
+
+		providerNamespacedName := types.NamespacedName{
+			Name:      "test-zone-provider",
+			Namespace: "default",
+		}
+
+		secretNamespacedName := types.NamespacedName{
+			Name:      "test-zone-secret",
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			var err error
+
+			By("creating the Secret for the Provider")
+			err = k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      secretNamespacedName.Name,
+						Namespace: secretNamespacedName.Namespace,
+					},
+					StringData: map[string]string{
+						"apiToken": "test-token",
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the Provider for the Zone")
+			err = k8sClient.Get(ctx, providerNamespacedName, &ddnsv1alpha1.Provider{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &ddnsv1alpha1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      providerNamespacedName.Name,
+						Namespace: providerNamespacedName.Namespace,
+					},
+					Spec: ddnsv1alpha1.ProviderSpec{
+						Name:          "Cloudflare",
+						SecretName:    secretNamespacedName.Name,
+						RetryInterval: ptr.To(intstr.FromInt32(123)),
+						Config: &ddnsv1alpha1.ProviderConfig{
+							Zones: []ddnsv1alpha1.ZoneConfig{{
+								Name:    "example.org",
+								Records: []ddnsv1alpha1.RecordConfig{{Name: "example.org"}},
+							}},
+						},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+				resource.Status.PublicIP = publicIp
+				Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the custom resource for the Kind Zone")
+			err = k8sClient.Get(ctx, zoneNamespacedName, &ddnsv1alpha1.Zone{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &ddnsv1alpha1.Zone{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      zoneNamespacedName.Name,
+						Namespace: zoneNamespacedName.Namespace,
+					},
+					Spec: ddnsv1alpha1.ZoneSpec{
+						ProviderRef: ddnsv1alpha1.ProviderRef{Name: providerNamespacedName.Name},
+						Name:        "example.com",
+						Records:     []ddnsv1alpha1.RecordConfig{{Name: "home.example.com", Proxied: true}},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the ZoneReconciler")
+			providerClient = MockClient{IP: "127.0.0.2"}
+			usedConfig = ""
+			controllerReconciler = &ZoneReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				ClientFactory: func(ctx context.Context, name string, secret *corev1.Secret, configMap *corev1.ConfigMap, log logr.Logger) (clients.Client, error) {
+					usedConfig = configMap.Data["config"]
+					return providerClient, nil
+				},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Zone and related resources")
+			Expect(k8sClient.Delete(ctx, &ddnsv1alpha1.Zone{
+				ObjectMeta: metav1.ObjectMeta{Name: zoneNamespacedName.Name, Namespace: zoneNamespacedName.Namespace},
+			})).To(Succeed())
+			deleteProvider(ctx, &ddnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: providerNamespacedName.Name, Namespace: providerNamespacedName.Namespace},
+			})
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretNamespacedName.Name, Namespace: secretNamespacedName.Namespace},
+			})).To(Succeed())
+		})
+
+		It("should only manage the records of the zone", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: zoneNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(usedConfig).To(MatchJSON(`{"cloudflare": {"zones": [{"name": "example.com", "records": [{"name": "home.example.com", "proxied": true}]}]}}`))
+		})
+
+		It("should update the records with the public IP of the Provider", func() {
+			setIp := ""
+			providerClient.SetIPInterceptor = func(ip string) {
+				setIp = ip
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: zoneNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Second * 123))
+			Expect(setIp).To(Equal(publicIp))
+
+			zone := &ddnsv1alpha1.Zone{}
+			Expect(k8sClient.Get(ctx, zoneNamespacedName, zone)).To(Succeed())
+			Expect(zone.Status.ProviderIP).To(Equal(publicIp))
+			Expect(zone.Status.LastSyncTime).NotTo(BeNil())
+			Expect(zone.Status.ObservedGeneration).To(Equal(zone.GetGeneration()))
+			Expect(meta.IsStatusConditionTrue(zone.Status.Conditions, ddnsv1alpha1.ZoneConditionTypeProvider)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(zone.Status.Conditions, ddnsv1alpha1.ZoneConditionTypeClient)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(zone.Status.Conditions, ddnsv1alpha1.ZoneConditionTypeSynced)).To(BeTrue())
+		})
+
+		It("should not update the records if they are in sync", func() {
+			providerClient.IP = publicIp
+			providerClient.SetIPInterceptor = func(ip string) {
+				Fail("SetIp should not be called")
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: zoneNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the Provider does not exist", func() {
+			zone := &ddnsv1alpha1.Zone{}
+			Expect(k8sClient.Get(ctx, zoneNamespacedName, zone)).To(Succeed())
+			zone.Spec.ProviderRef.Name = "missing-provider"
+			Expect(k8sClient.Update(ctx, zone)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: zoneNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, zoneNamespacedName, zone)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(zone.Status.Conditions, ddnsv1alpha1.ZoneConditionTypeProvider)).To(BeTrue())
+		})
+
+		It("should set the Synced condition if the records cannot be updated", func() {
+			providerClient.SetIPError = fmt.Errorf("set ip error")
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: zoneNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			zone := &ddnsv1alpha1.Zone{}
+			Expect(k8sClient.Get(ctx, zoneNamespacedName, zone)).To(Succeed())
+			condition := meta.FindStatusCondition(zone.Status.Conditions, ddnsv1alpha1.ZoneConditionTypeSynced)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("set ip error"))
+		})
+	})
+})
@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// controllerKey is the key controller-runtime adds the name of the controller to the loggers of the reconciliations with
+const controllerKey = "controller"
+
+// maxVerbosity is the highest verbosity zap can log at, as its levels are an int8
+const maxVerbosity = 127
+
+// Levels are the verbosities of the logs of the controllers, by the name of the controller, e.g. provider or notifier
+type Levels map[string]int
+
+// ParseLevels parses a comma separated list of controller=verbosity pairs, e.g. provider=2,notifier=1
+func ParseLevels(value string) (Levels, error) {
+	levels := Levels{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		name, verbosity, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid controller log level %q, expected <controller>=<verbosity>", pair)
+		}
+
+		level, err := strconv.Atoi(strings.TrimSpace(verbosity))
+		if err != nil || level < 0 || level > maxVerbosity {
+			return nil, fmt.Errorf("invalid verbosity %q of controller %q, expected a number from 0 to %d", verbosity, name, maxVerbosity)
+		}
+
+		levels[strings.ToLower(strings.TrimSpace(name))] = level
+	}
+
+	return levels, nil
+}
+
+// New builds the zap logger of the options, with the logs of the controllers in the levels logged at their verbosity
+// instead of the one of the options
+func New(opts zap.Options, levels Levels) logr.Logger {
+	verbosity := Verbosity(opts.Level, opts.Development)
+	if len(levels) == 0 {
+		return zap.New(zap.UseFlagOptions(&opts))
+	}
+
+	// zap drops the logs above its own level, so it has to let through the most verbose ones of the controllers
+	highest := verbosity
+	for _, level := range levels {
+		highest = max(highest, level)
+	}
+	opts.Level = zapcore.Level(-highest)
+
+	return WithLevels(zap.New(zap.UseFlagOptions(&opts)), levels, verbosity)
+}
+
+// Verbosity returns the highest logr verbosity that the level of zap enables, -1 if only errors are logged.
+// A nil level is the default of controller-runtime: debug in development mode and info otherwise
+func Verbosity(level zapcore.LevelEnabler, development bool) int {
+	if level == nil {
+		if development {
+			return 1
+		}
+
+		return 0
+	}
+
+	verbosity := -1
+	for verbosity < maxVerbosity && level.Enabled(zapcore.Level(-verbosity-1)) {
+		verbosity++
+	}
+
+	return verbosity
+}
+
+// WithLevels limits the logs of the logger to the verbosity, except for the ones of the controllers in the levels, which
+// are limited to the verbosity of the controller. The logger itself has to log at the highest of the verbosities
+func WithLevels(logger logr.Logger, levels Levels, verbosity int) logr.Logger {
+	return logr.New(&levelSink{sink: logger.GetSink(), levels: levels, verbosity: verbosity})
+}
+
+// levelSink passes the logs enabled at its verbosity on to the sink. The verbosity is replaced by the one of the controller
+// once the name of a controller in the levels is added to the values of the logger
+type levelSink struct {
+	sink      logr.LogSink
+	levels    Levels
+	verbosity int
+}
+
+var _ logr.CallDepthLogSink = &levelSink{}
+
+func (s *levelSink) Init(info logr.RuntimeInfo) {
+	// The levelSink is one more frame between the caller and the sink
+	info.CallDepth++
+	s.sink.Init(info)
+}
+
+func (s *levelSink) Enabled(level int) bool {
+	return level <= s.verbosity && s.sink.Enabled(level)
+}
+
+func (s *levelSink) Info(level int, msg string, keysAndValues ...any) {
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *levelSink) Error(err error, msg string, keysAndValues ...any) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *levelSink) WithValues(keysAndValues ...any) logr.LogSink {
+	sink := *s
+	sink.sink = s.sink.WithValues(keysAndValues...)
+
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if keysAndValues[i] != controllerKey {
+			continue
+		}
+
+		if name, ok := keysAndValues[i+1].(string); ok {
+			if level, ok := s.levels[strings.ToLower(name)]; ok {
+				sink.verbosity = level
+			}
+		}
+	}
+
+	return &sink
+}
+
+func (s *levelSink) WithName(name string) logr.LogSink {
+	sink := *s
+	sink.sink = s.sink.WithName(name)
+
+	return &sink
+}
+
+func (s *levelSink) WithCallDepth(depth int) logr.LogSink {
+	sink := *s
+	if callDepthSink, ok := s.sink.(logr.CallDepthLogSink); ok {
+		sink.sink = callDepthSink.WithCallDepth(depth)
+	}
+
+	return &sink
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"go.uber.org/zap/zapcore"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging", func() {
+	Context("When parsing the levels of the controllers", func() {
+		It("should parse the verbosity of every controller", func() {
+			levels, err := ParseLevels("provider=2, Notifier=1,")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(levels).To(Equal(Levels{"provider": 2, "notifier": 1}))
+		})
+
+		It("should reject invalid levels", func() {
+			_, err := ParseLevels("provider")
+			Expect(err).To(HaveOccurred())
+
+			_, err = ParseLevels("provider=debug")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When limiting the verbosity of the logs", func() {
+		var (
+			messages []string
+			logger   logr.Logger
+		)
+
+		BeforeEach(func() {
+			messages = nil
+			sink := funcr.New(func(prefix, args string) { messages = append(messages, args) }, funcr.Options{Verbosity: 10})
+			logger = WithLevels(sink, Levels{"provider": 2}, 0)
+		})
+
+		It("should log the controllers in the levels at their verbosity", func() {
+			logger.WithValues("controller", "provider").V(2).Info("Calling the provider")
+			logger.WithValues("controller", "provider").V(3).Info("Response of the provider")
+
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0]).To(ContainSubstring("Calling the provider"))
+		})
+
+		It("should log the other controllers at the global verbosity", func() {
+			logger.WithValues("controller", "notifier").Info("Sending the notification")
+			logger.WithValues("controller", "notifier").V(1).Info("Response of the webhook")
+			logger.WithName("setup").V(1).Info("Starting")
+
+			Expect(messages).To(HaveLen(1))
+			Expect(messages[0]).To(ContainSubstring("Sending the notification"))
+		})
+	})
+
+	It("should read the verbosity from the level of zap", func() {
+		Expect(Verbosity(nil, true)).To(Equal(1))
+		Expect(Verbosity(nil, false)).To(Equal(0))
+		Expect(Verbosity(zapcore.Level(-3), false)).To(Equal(3))
+		Expect(Verbosity(zapcore.ErrorLevel, false)).To(Equal(-1))
+	})
+})
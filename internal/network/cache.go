@@ -0,0 +1,140 @@
+package network
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ipCacheEntry is the last public IP fetched with a custom IP provider. Concurrent lookups wait for the one in flight
+// instead of all hitting the echo services
+type ipCacheEntry struct {
+	ip      string
+	fetched time.Time
+	// fetching is closed once the lookup in flight finished. It is nil if there is none
+	fetching chan struct{}
+}
+
+// IPCache reuses the public IP fetched with getIp for the ttl for every Provider with the same customIpProvider,
+// instead of fetching it again for each of them. Failed lookups are not cached. Nothing is cached if the ttl is 0
+type IPCache struct {
+	getIp func(ctx context.Context, customIpProvider string) (string, error)
+	ttl   time.Duration
+
+	// mu guards the entries, it is not held while fetching
+	mu      sync.Mutex
+	entries map[string]*ipCacheEntry
+}
+
+// CachedIP is a public IP held by an IPCache, as dumped for debugging
+type CachedIP struct {
+	// Provider is the custom IP provider without its credentials and query, or default for the built-in services
+	Provider string     `json:"provider"`
+	IP       string     `json:"ip,omitempty"`
+	Fetched  *time.Time `json:"fetched,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	// Fetching is set while the IP is being fetched again
+	Fetching bool `json:"fetching,omitempty"`
+}
+
+// NewIPCache returns an IPCache of the public IPs fetched with getIp
+func NewIPCache(getIp func(ctx context.Context, customIpProvider string) (string, error), ttl time.Duration) *IPCache {
+	return &IPCache{getIp: getIp, ttl: ttl, entries: map[string]*ipCacheEntry{}}
+}
+
+// Get returns the public IP of the customIpProvider, fetching it only if the cached one expired. A lookup of the same
+// customIpProvider that is already in flight is waited for until ctx is done
+func (c *IPCache) Get(ctx context.Context, customIpProvider string) (string, error) {
+	if c.ttl <= 0 {
+		return c.getIp(ctx, customIpProvider)
+	}
+
+	for {
+		c.mu.Lock()
+		entry, ok := c.entries[customIpProvider]
+		if !ok {
+			entry = &ipCacheEntry{}
+			c.entries[customIpProvider] = entry
+		}
+
+		if entry.ip != "" && time.Since(entry.fetched) < c.ttl {
+			ip := entry.ip
+			c.mu.Unlock()
+			return ip, nil
+		}
+
+		fetching := entry.fetching
+		if fetching == nil {
+			entry.fetching = make(chan struct{})
+			c.mu.Unlock()
+			return c.fetch(ctx, customIpProvider, entry)
+		}
+		c.mu.Unlock()
+
+		// The IP of the lookup in flight is used once it finished, or fetched again if it failed
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// fetch fetches the public IP of the customIpProvider into the entry and wakes up the lookups waiting for it
+func (c *IPCache) fetch(ctx context.Context, customIpProvider string, entry *ipCacheEntry) (string, error) {
+	ip, err := c.getIp(ctx, customIpProvider)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		entry.ip = ip
+		entry.fetched = time.Now()
+	}
+
+	close(entry.fetching)
+	entry.fetching = nil
+
+	if err != nil {
+		return "", err
+	}
+
+	return ip, nil
+}
+
+// Entries returns the cached public IPs by custom IP provider, without waiting for the lookups in flight
+func (c *IPCache) Entries() []CachedIP {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := []CachedIP{}
+	for customIpProvider, entry := range c.entries {
+		cached := CachedIP{Provider: "default"}
+		if customIpProvider != "" {
+			cached.Provider = providerLabel(customIpProvider)
+		}
+
+		if entry.fetching != nil {
+			cached.Fetching = true
+			entries = append(entries, cached)
+			continue
+		}
+
+		if entry.ip != "" {
+			cached.IP = entry.ip
+			fetched, expires := entry.fetched, entry.fetched.Add(c.ttl)
+			cached.Fetched = &fetched
+			cached.Expires = &expires
+		}
+
+		entries = append(entries, cached)
+	}
+
+	slices.SortFunc(entries, func(a, b CachedIP) int {
+		return cmp.Compare(a.Provider, b.Provider)
+	})
+
+	return entries
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// resolveTimeout is how long a single lookup at a resolver may take
+const resolveTimeout = 5 * time.Second
+
+// Resolve looks up the A or AAAA records (depending on recordType) of the name at the given DNS resolver,
+// bypassing the resolver of the system. The resolver is a `host` or `host:port`, port 53 is used if none is set
+func Resolve(ctx context.Context, resolver, name, recordType string) ([]string, error) {
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(resolver, "53")
+	}
+
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: resolveTimeout}
+			return dialer.DialContext(ctx, network, resolver)
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	family := "ip4"
+	if recordType == "AAAA" {
+		family = "ip6"
+	}
+
+	ips, err := r.LookupIP(ctx, family, name)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		resolved = append(resolved, ip.String())
+	}
+
+	return resolved, nil
+}
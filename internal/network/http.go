@@ -1,27 +1,39 @@
 package network
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // defaultClient is used as we want to set a timeout for the http requests
-var defaultClient = http.Client{
-	Timeout: time.Second * 1,
-}
+var defaultClient = NewHTTPClient(time.Second * 1)
 
 // GetBody does a Get request on the given url and returns the body in a []byte.
-// Will also close the ReadStream
-func GetBody(url string) ([]byte, error) {
-	if resp, err := defaultClient.Get(url); err == nil {
+// Will also close the ReadStream. The request is canceled with the context
+func GetBody(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New("http: Invalid url")
+	}
+
+	logger := log.FromContext(ctx).V(1)
+	logger.Info("Sending HTTP request", "method", http.MethodGet, "url", providerLabel(url))
+
+	if resp, err := defaultClient.Do(req); err == nil {
 		defer resp.Body.Close()
 
+		logger.Info("Received HTTP response", "url", providerLabel(url), "code", resp.StatusCode)
+
 		if body, err := io.ReadAll(resp.Body); err == nil {
 			return body, nil
 		}
 	}
 
-	return nil, fmt.Errorf("http: Error while trying to fetch url: %s", url)
+	return nil, fmt.Errorf("http: Error while trying to fetch url: %s", providerLabel(url))
 }
@@ -1,13 +1,17 @@
 package network
 
 import (
+	"context"
 	"fmt"
-	"log/slog"
 	"net"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/rand"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/Michaelpalacce/go-ddns-controller/internal/tracing"
 )
 
 // ipProviders is a list of providers that will be used to fetch the public IP
@@ -19,6 +23,12 @@ var ipProviders = []string{
 	"http://www.trackip.net/ip", "http://ifconfig.me",
 }
 
+// ipv6Providers is a list of providers that will be used to fetch the public IPv6 address
+var ipv6Providers = []string{
+	"https://ipv6.icanhazip.com", "https://api6.ipify.org",
+	"https://6.ident.me/",
+}
+
 // shuffle will shuffle the slice
 func shuffle(slice []string) {
 	rand.Seed(uint64(time.Now().UnixNano()))
@@ -30,25 +40,68 @@ func shuffle(slice []string) {
 
 // GetPublicIp will fetch the public IP of the
 // machine that is running goip
-func GetPublicIp(customIpProvider string) (string, error) {
-	currentIpProviders := ipProviders
+func GetPublicIp(ctx context.Context, customIpProvider string) (string, error) {
+	return getPublicIp(ctx, customIpProvider, ipProviders, isIPv4)
+}
+
+// GetPublicIpv6 will fetch the public IPv6 address of the
+// machine that is running goip
+// The customIpProvider is tried first, but only an IPv6 response is accepted from it.
+func GetPublicIpv6(ctx context.Context, customIpProvider string) (string, error) {
+	return getPublicIp(ctx, customIpProvider, ipv6Providers, isIPv6)
+}
+
+// getPublicIp will go through the providers until one returns a valid IP of the expected family
+// It stops once the context is done
+func getPublicIp(ctx context.Context, customIpProvider string, providers []string, valid func(net.IP) bool) (string, error) {
+	currentIpProviders := make([]string, len(providers))
+	copy(currentIpProviders, providers)
 	shuffle(currentIpProviders)
 
-	currentIpProviders = append([]string{customIpProvider}, ipProviders...)
+	currentIpProviders = append([]string{customIpProvider}, currentIpProviders...)
+
+	logger := log.FromContext(ctx)
 
 	for _, provider := range currentIpProviders {
 		if provider == "" {
 			continue
 		}
 
-		ip, err := GetBody(provider)
+		if err := ctx.Err(); err != nil {
+			return "", fmt.Errorf("could not retrieve the public IP: %w", err)
+		}
+
+		lookupCtx, span := tracing.Start(ctx, "Network.GetPublicIP", attribute.String("ddns.ip_provider", providerLabel(provider)))
+		start := time.Now()
+		body, err := GetBody(lookupCtx, provider)
 		if err != nil {
-			slog.Error("Error while trying to fetch ip from provider", "error", err, "provider", provider)
+			logger.Error(err, "Error while trying to fetch ip from provider", "provider", providerLabel(provider))
+			recordLookup(provider, "error", time.Since(start), "")
+			tracing.End(span, err)
 			continue
 		}
 
-		return net.ParseIP(strings.TrimSpace(string(ip))).String(), nil
+		ip := net.ParseIP(strings.TrimSpace(string(body)))
+		if ip == nil || !valid(ip) {
+			logger.Error(nil, "Provider returned an unexpected response", "provider", providerLabel(provider), "response", string(body))
+			recordLookup(provider, "invalid", time.Since(start), "")
+			tracing.End(span, fmt.Errorf("unexpected response: %q", string(body)))
+			continue
+		}
+
+		recordLookup(provider, "success", time.Since(start), ip.String())
+		tracing.End(span, nil)
+
+		return ip.String(), nil
 	}
 
 	return "", fmt.Errorf("could not retrieve a response from any of the providers")
 }
+
+func isIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
+
+func isIPv6(ip net.IP) bool {
+	return ip.To4() == nil && ip.To16() != nil
+}
@@ -0,0 +1,76 @@
+package network
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The metrics of the services the public IP is fetched from are labeled by their URL, so unreliable ones can be told
+// apart and left out with a customIpProvider
+var (
+	ipProviderRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ddns_ip_provider_requests_total",
+		Help: "How many times the public IP was fetched from a service, by result: success, error or invalid.",
+	}, []string{"provider", "result"})
+
+	ipProviderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ddns_ip_provider_request_duration_seconds",
+		Help:    "How long fetching the public IP from a service took.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5},
+	}, []string{"provider"})
+
+	ipProviderLastChange = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ddns_ip_provider_last_change_timestamp_seconds",
+		Help: "When a service last returned a different public IP than before, as a Unix timestamp.",
+	}, []string{"provider"})
+)
+
+// lastIps is the last IP returned by every service, to tell when it changed
+var lastIps sync.Map
+
+func init() {
+	metrics.Registry.MustRegister(ipProviderRequests, ipProviderDuration, ipProviderLastChange)
+}
+
+// recordLookup reports the result and duration of fetching the public IP from a service. The IP is only set on success
+func recordLookup(provider, result string, duration time.Duration, ip string) {
+	label := providerLabel(provider)
+
+	ipProviderRequests.WithLabelValues(label, result).Inc()
+	ipProviderDuration.WithLabelValues(label).Observe(duration.Seconds())
+
+	if ip == "" {
+		return
+	}
+
+	if last, ok := lastIps.Swap(label, ip); !ok || last.(string) != ip {
+		ipProviderLastChange.WithLabelValues(label).SetToCurrentTime()
+	}
+}
+
+// LastIPs returns the last IP returned by every service the public IP was fetched from, by the URL of the service without
+// its credentials and query
+func LastIPs() map[string]string {
+	ips := map[string]string{}
+	lastIps.Range(func(service, ip interface{}) bool {
+		ips[service.(string)] = ip.(string)
+		return true
+	})
+
+	return ips
+}
+
+// providerLabel returns the URL of the service without its credentials and query, which a customIpProvider may contain,
+// so it can be used in the metrics and logs
+func providerLabel(provider string) string {
+	u, err := url.Parse(provider)
+	if err != nil || u.Host == "" {
+		return "invalid"
+	}
+
+	return u.Scheme + "://" + u.Host + u.Path
+}
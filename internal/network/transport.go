@@ -0,0 +1,50 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Transport is the HTTP transport shared by all the outbound calls of the controller: the public IP lookups, the calls
+// to the DNS providers and the webhook notifiers. Its connections are pooled and kept alive, so many Providers reuse the
+// same few connections to every host instead of dialing a new one for each call.
+// Requests go through the proxy in the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, unless SetProxy is called
+var Transport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   10,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: 30 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// NewHTTPClient returns a client over the shared Transport, whose requests time out after the timeout. 0 means that
+// only the context of the requests cancels them
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: Transport,
+		Timeout:   timeout,
+	}
+}
+
+// SetProxy sends all the outbound calls through the proxy of the given url, instead of the one in the environment.
+// It must be called before any call is made
+func SetProxy(proxyUrl string) error {
+	u, err := url.Parse(proxyUrl)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid proxy url %q, expected e.g. http://proxy.example.com:3128", proxyUrl)
+	}
+
+	Transport.Proxy = http.ProxyURL(u)
+
+	return nil
+}
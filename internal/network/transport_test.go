@@ -0,0 +1,33 @@
+package network
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transport", func() {
+	It("should share the transport between the clients", func() {
+		Expect(NewHTTPClient(0).Transport).To(BeIdenticalTo(Transport))
+		Expect(defaultClient.Transport).To(BeIdenticalTo(Transport))
+	})
+
+	It("should send the calls through the proxy", func() {
+		proxy := Transport.Proxy
+		DeferCleanup(func() { Transport.Proxy = proxy })
+
+		Expect(SetProxy("http://proxy.example.com:3128")).To(Succeed())
+
+		req, err := http.NewRequest(http.MethodGet, "https://api.cloudflare.com", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		proxyUrl, err := Transport.Proxy(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proxyUrl.String()).To(Equal("http://proxy.example.com:3128"))
+	})
+
+	It("should reject invalid proxy urls", func() {
+		Expect(SetProxy("proxy.example.com")).To(MatchError(ContainSubstring("invalid proxy url")))
+	})
+})
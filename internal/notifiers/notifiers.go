@@ -1,9 +1,12 @@
 package notifiers
 
 import (
+	"context"
 	"fmt"
 
 	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -12,26 +15,44 @@ var Webhook = "Webhook"
 // Notifier is an interface for sending notifications.
 // All Notifiers should implement this interface
 type Notifier interface {
+	// SendNotification may be called concurrently, as the notifications about several providers are delivered at once
 	SendNotification(message any) error
-	SendGreetings(notifier *ddnsv1alpha1.Notifier) error
+	SendGreetings(notifier ddnsv1alpha1.NotifierObject) error
 }
 
-// NotifierFactory will return a Notifier based on the Notifier spec
+// NotifierFactory will return a Notifier based on the Notifier spec, which logs its calls with the logger
 func NotifierFactory(
-	notifier *ddnsv1alpha1.Notifier,
+	notifier ddnsv1alpha1.NotifierObject,
 	secret *corev1.Secret,
 	configMap *corev1.ConfigMap,
+	log logr.Logger,
 ) (Notifier, error) {
-	switch notifier.Spec.Name {
+	switch notifier.GetNotifierSpec().Name {
 	case Webhook:
 		if secret.Data["url"] == nil {
 			return nil, fmt.Errorf("`url` not found in secret")
 		}
 
 		return &WebhookNotifier{
-			Url: string(secret.Data["url"]),
+			Url:    string(secret.Data["url"]),
+			Logger: log,
 		}, nil
 	default:
-		return nil, fmt.Errorf("unknown notifier %s", notifier.Spec.Name)
+		return nil, fmt.Errorf("unknown notifier %s", notifier.GetNotifierSpec().Name)
+	}
+}
+
+// VerifyNotifier checks the credentials in the secret of the notifier, without sending anything to it.
+// The error wraps clients.ErrInvalidCredentials if they were rejected
+func VerifyNotifier(ctx context.Context, notifier ddnsv1alpha1.NotifierObject, secret *corev1.Secret) error {
+	switch notifier.GetNotifierSpec().Name {
+	case Webhook:
+		if secret.Data["url"] == nil {
+			return fmt.Errorf("%w: `url` not found in secret", clients.ErrInvalidCredentials)
+		}
+
+		return verifyWebhook(ctx, string(secret.Data["url"]))
+	default:
+		return fmt.Errorf("unknown notifier %s", notifier.GetNotifierSpec().Name)
 	}
 }
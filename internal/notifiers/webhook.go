@@ -2,26 +2,37 @@ package notifiers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/http"
+	"net/url"
+	"time"
 
 	ddnsv1alpha1 "github.com/Michaelpalacce/go-ddns-controller/api/v1alpha1"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/clients"
+	"github.com/Michaelpalacce/go-ddns-controller/internal/network"
+	"github.com/go-logr/logr"
 )
 
+// httpClient calls the webhooks over the shared transport. The timeout keeps a hanging webhook from blocking the notifier
+var httpClient = network.NewHTTPClient(30 * time.Second)
+
 type webhookData struct {
 	Content string `json:"content"`
 }
 
 type WebhookNotifier struct {
 	Url string
+	// Logger logs the calls to the webhook at verbosity 1. Nothing is logged if it is the zero logr.Logger
+	Logger logr.Logger
 }
 
 // SendGreetings sends a greeting message to the webhook
-func (w *WebhookNotifier) SendGreetings(notifier *ddnsv1alpha1.Notifier) error {
-	err := w.sendToWebhook(fmt.Sprintf("`go-ddns-controller` is starting its watch. From notifier: (%s).", notifier.Name))
+func (w *WebhookNotifier) SendGreetings(notifier ddnsv1alpha1.NotifierObject) error {
+	err := w.sendToWebhook(fmt.Sprintf("`go-ddns-controller` is starting its watch. From notifier: (%s).", notifier.GetName()))
 	if err != nil {
 		return err
 	}
@@ -57,16 +68,17 @@ func (w *WebhookNotifier) sendToWebhook(data string) error {
 		return err
 	}
 
-	slog.Debug("Sending to webhook", "data", string(requestBody))
+	logger := w.Logger.V(1).WithValues("url", redactedUrl(w.Url))
+	logger.Info("Sending to webhook", "data", string(requestBody))
 
-	resp, err := http.Post(w.Url, "application/json", bytes.NewBuffer(requestBody))
+	resp, err := httpClient.Post(w.Url, "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
 		return err
 	}
 
 	defer resp.Body.Close()
 
-	slog.Debug("Status Code", "code", resp.StatusCode)
+	logger.Info("Received response from webhook", "code", resp.StatusCode)
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		var (
@@ -82,3 +94,42 @@ func (w *WebhookNotifier) sendToWebhook(data string) error {
 
 	return nil
 }
+
+// verifyWebhook sends a HEAD request to the webhook, which most services answer without posting anything, to catch URLs
+// with a mistyped or revoked token. Any answer but 401, 403, 404 or 410 is accepted, e.g. 405 from webhooks that only allow POST
+func verifyWebhook(ctx context.Context, webhookUrl string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, webhookUrl, nil)
+	if err != nil {
+		return fmt.Errorf("%w: the webhook url %s is not valid", clients.ErrInvalidCredentials, redactedUrl(webhookUrl))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// The url.Error would hold the token in the path or query of the url
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return fmt.Errorf("could not reach the webhook %s: %w", redactedUrl(webhookUrl), err)
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("%w: the webhook %s answered %s", clients.ErrInvalidCredentials, redactedUrl(webhookUrl), resp.Status)
+	}
+
+	return nil
+}
+
+// redactedUrl returns the scheme and host of the url only, as the path and query of webhooks usually hold their token
+func redactedUrl(webhookUrl string) string {
+	u, err := url.Parse(webhookUrl)
+	if err != nil || u.Host == "" {
+		return "invalid"
+	}
+
+	return u.Scheme + "://" + u.Host + "/REDACTED"
+}
@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracing", func() {
+	var recorder *tracetest.SpanRecorder
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+
+	It("should nest the spans and record their errors", func() {
+		ctx, parent := Start(context.Background(), "Provider.Reconcile")
+		_, child := Start(ctx, "Provider.GetIp")
+
+		End(child, fmt.Errorf("provider is unavailable"))
+		End(parent, nil)
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+
+		Expect(spans[0].Name()).To(Equal("Provider.GetIp"))
+		Expect(spans[0].Parent().SpanID()).To(Equal(spans[1].SpanContext().SpanID()))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spans[0].Status().Description).To(Equal("provider is unavailable"))
+
+		Expect(spans[1].Status().Code).To(Equal(codes.Unset))
+	})
+})
@@ -0,0 +1,50 @@
+package version
+
+import (
+	"runtime/debug"
+)
+
+// Version is the version of the controller. It is set when building it with
+// -ldflags "-X github.com/Michaelpalacce/go-ddns-controller/internal/version.Version=v1.2.3".
+// If it is not set, the version of the module is read from the build info
+var Version = ""
+
+// unknown is reported for the versions that cannot be read from the build info, e.g. in a binary built without modules
+const unknown = "unknown"
+
+// Controller returns the version of the controller, e.g. v1.2.3, or (devel) for a build from a local checkout
+func Controller() string {
+	if Version != "" {
+		return Version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return unknown
+	}
+
+	return info.Main.Version
+}
+
+// Module returns the version of the given Go module the controller is built with, e.g. v0.101.0, taking replacements
+// into account
+func Module(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return unknown
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != path {
+			continue
+		}
+
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+
+		return dep.Version
+	}
+
+	return unknown
+}
@@ -0,0 +1,20 @@
+package version
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	It("should prefer the version set at build time", func() {
+		DeferCleanup(func(version string) { Version = version }, Version)
+
+		Version = "v1.2.3"
+		Expect(Controller()).To(Equal("v1.2.3"))
+	})
+
+	It("should read the versions of the modules from the build info", func() {
+		Expect(Module("github.com/onsi/gomega")).To(HavePrefix("v1."))
+		Expect(Module("example.com/not-a-dependency")).To(Equal(unknown))
+	})
+})